@@ -0,0 +1,204 @@
+package game
+
+import (
+	"sync"
+	"time"
+)
+
+// Rule decides whether a settlement unlocks an achievement.
+// progress is the rule's own per-player counter and survives between settlements.
+type Rule interface {
+	Check(s Settlement, progress *int) bool
+}
+
+// Achievement is a badge and the rule that unlocks it
+type Achievement struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Rule        Rule   `json:"-"`
+}
+
+// Badge is an achievement unlocked by a player
+type Badge struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	UnlockedAt  time.Time `json:"unlocked_at"`
+	GameID      string    `json:"game_id,omitempty"` // Game that unlocked it
+}
+
+// DefaultAchievements are the achievements players can unlock.
+// New ones are added here; the game flow does not need to change.
+var DefaultAchievements = []Achievement{
+	{ID: "first_win", Name: "Beginner's Luck", Description: "Win a game", Rule: WinStreak{Count: 1}},
+	{ID: "hot_streak", Name: "Hot Streak", Description: "Win 5 games in a row", Rule: WinStreak{Count: 5}},
+	{ID: "natural", Name: "Natural", Description: "Get dealt a blackjack", Rule: DealtBlackjack{}},
+	{ID: "five_card_21", Name: "Five Card 21", Description: "Win with a 5-card 21", Rule: WinningHand{Cards: 5, Score: 21}},
+	{ID: "double_aces", Name: "Double Aces", Description: "Split aces into two blackjacks", Rule: SplitBlackjacks{Rank: Ace}},
+	{ID: "comeback", Name: "Comeback Kid", Description: "Survive from 1 token back to 100", Rule: Comeback{Low: 1, High: 100}},
+}
+
+// WinStreak unlocks after Count wins in a row
+type WinStreak struct {
+	Count int
+}
+
+func (r WinStreak) Check(s Settlement, streak *int) bool {
+	if !s.Won() {
+		*streak = 0
+		return false
+	}
+	*streak++
+	return *streak >= r.Count
+}
+
+// DealtBlackjack unlocks on a natural
+type DealtBlackjack struct{}
+
+func (DealtBlackjack) Check(s Settlement, _ *int) bool {
+	return s.Blackjack
+}
+
+// WinningHand unlocks when a hand of at least Cards cards scoring Score beats the dealer.
+// Whether it won is taken from the settlement, under the game's own rules.
+type WinningHand struct {
+	Cards int
+	Score int
+}
+
+func (r WinningHand) Check(s Settlement, _ *int) bool {
+	for i, hand := range s.Hands {
+		if len(hand.Cards) >= r.Cards && hand.Score == r.Score && i < len(s.HandsWon) && s.HandsWon[i] {
+			return true
+		}
+	}
+	return false
+}
+
+// SplitBlackjacks unlocks when a split pair of Rank turns into two 2-card 21s
+type SplitBlackjacks struct {
+	Rank Rank
+}
+
+func (r SplitBlackjacks) Check(s Settlement, _ *int) bool {
+	if len(s.Hands) != 2 {
+		return false
+	}
+	for _, hand := range s.Hands {
+		// Switch deals two hands without a split
+		if !hand.Split || !IsBlackjack(hand) || hand.Cards[0].Rank != r.Rank {
+			return false
+		}
+	}
+	return true
+}
+
+// Comeback unlocks when the balance climbs back to High after dropping to Low.
+// Going broke resets it: a comeback from nothing needs a bankroll grant or a deposit, not play.
+type Comeback struct {
+	Low  int
+	High int
+}
+
+func (r Comeback) Check(s Settlement, wasLow *int) bool {
+	switch {
+	case s.Balance <= 0:
+		*wasLow = 0
+	case s.Balance <= r.Low:
+		*wasLow = 1
+	case *wasLow == 1 && s.Balance >= r.High:
+		return true
+	}
+	return false
+}
+
+// AchievementProgress holds each player's rule counters by achievement ID
+type AchievementProgress map[string]map[string]int
+
+// Achievements evaluates settlements against the achievement rules and stores badges on players.
+// All methods are safe for concurrent use.
+type Achievements struct {
+	mu           sync.Mutex
+	players      *PlayerStore
+	achievements []Achievement
+	progress     AchievementProgress
+}
+
+// NewAchievements creates an engine for the given achievements
+func NewAchievements(players *PlayerStore, achievements []Achievement) *Achievements {
+	return &Achievements{
+		players:      players,
+		achievements: achievements,
+		progress:     make(AchievementProgress),
+	}
+}
+
+// List returns the achievements that can be unlocked
+func (a *Achievements) List() []Achievement {
+	return append([]Achievement(nil), a.achievements...)
+}
+
+// Evaluate runs every rule against a settlement and returns the badges it unlocked
+func (a *Achievements) Evaluate(s Settlement) []Badge {
+	if _, ok := a.players.Get(s.PlayerID); !ok {
+		return nil
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	progress := a.progress[s.PlayerID]
+	if progress == nil {
+		progress = make(map[string]int)
+		a.progress[s.PlayerID] = progress
+	}
+
+	var unlocked []Badge
+	for _, achievement := range a.achievements {
+		// Rules keep counting even once unlocked so their progress stays accurate
+		counter := progress[achievement.ID]
+		passed := achievement.Rule.Check(s, &counter)
+		progress[achievement.ID] = counter
+		if !passed {
+			continue
+		}
+		badge := Badge{
+			ID:          achievement.ID,
+			Name:        achievement.Name,
+			Description: achievement.Description,
+			UnlockedAt:  s.Time,
+			GameID:      s.GameID,
+		}
+		if a.players.AwardBadge(s.PlayerID, badge) {
+			unlocked = append(unlocked, badge)
+		}
+	}
+	return unlocked
+}
+
+// Progress returns a copy of every player's rule counters, for snapshots
+func (a *Achievements) Progress() AchievementProgress {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	progress := make(AchievementProgress, len(a.progress))
+	for playerID, counters := range a.progress {
+		progress[playerID] = make(map[string]int, len(counters))
+		for id, n := range counters {
+			progress[playerID][id] = n
+		}
+	}
+	return progress
+}
+
+// Restore replaces the rule counters with saved ones
+func (a *Achievements) Restore(progress AchievementProgress) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.progress = make(AchievementProgress, len(progress))
+	for playerID, counters := range progress {
+		a.progress[playerID] = make(map[string]int, len(counters))
+		for id, n := range counters {
+			a.progress[playerID][id] = n
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package game
+
+import (
+	"testing"
+	"time"
+)
+
+func hand(ranks ...Rank) Hand {
+	h := Hand{}
+	for _, r := range ranks {
+		h.Cards = append(h.Cards, Card{Suit: Hearts, Rank: r})
+	}
+	h.Score = CalculateScore(h.Cards)
+	return h
+}
+
+func split(ranks ...Rank) Hand {
+	h := hand(ranks...)
+	h.Split = true
+	return h
+}
+
+func TestAchievementRules(t *testing.T) {
+	win := Settlement{Result: StatusPlayerWon, Staked: 10, Payout: 20}
+	loss := Settlement{Result: StatusDealerWon, Staked: 10}
+
+	tests := []struct {
+		name        string
+		rule        Rule
+		settlements []Settlement
+		want        []bool
+	}{
+		{"streak resets on a loss", WinStreak{Count: 2}, []Settlement{win, loss, win, win}, []bool{false, false, false, true}},
+		{"five card 21 must win", WinningHand{Cards: 5, Score: 21}, []Settlement{
+			{Hands: []Hand{hand(Two, Three, Four, Five, Seven)}, HandsWon: []bool{false}, Dealer: hand(King, Ace)},
+			{Hands: []Hand{hand(Two, Three, Four, Five, Seven)}, HandsWon: []bool{true}, Dealer: hand(King, Eight)},
+			// Spanish 21 pays a player 21 against a dealer 21
+			{Hands: []Hand{hand(Two, Three, Four, Five, Seven)}, HandsWon: []bool{true}, Dealer: hand(King, Five, Six)},
+		}, []bool{false, true, true}},
+		{"split aces into blackjacks", SplitBlackjacks{Rank: Ace}, []Settlement{
+			{Hands: []Hand{split(Ace, King), split(Ace, Nine)}},
+			{Hands: []Hand{split(King, Ace), split(Queen, Ace)}},
+			// Blackjack Switch deals two hands without a split
+			{Hands: []Hand{hand(Ace, King), hand(Ace, Queen)}},
+			{Hands: []Hand{split(Ace, King), split(Ace, Queen)}},
+		}, []bool{false, false, false, true}},
+		{"comeback needs the low point first", Comeback{Low: 1, High: 100}, []Settlement{
+			{Balance: 120}, {Balance: 1}, {Balance: 60}, {Balance: 100},
+		}, []bool{false, false, false, true}},
+		{"going broke resets the comeback", Comeback{Low: 1, High: 100}, []Settlement{
+			{Balance: 1}, {Balance: 0}, {Balance: 100},
+		}, []bool{false, false, false}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			progress := 0
+			for i, s := range tt.settlements {
+				if got := tt.rule.Check(s, &progress); got != tt.want[i] {
+					t.Errorf("settlement %d: expected %v, got %v", i+1, tt.want[i], got)
+				}
+			}
+		})
+	}
+}
+
+func TestAchievementsUnlockOnce(t *testing.T) {
+	players := NewPlayerStore()
+	players.Save(&Player{ID: "p1", Balance: 100})
+	a := NewAchievements(players, DefaultAchievements)
+
+	s := Settlement{PlayerID: "p1", GameID: "g1", Time: time.Now(), Result: StatusPlayerWon, Staked: 10, Payout: 25, Blackjack: true,
+		Hands: []Hand{hand(Ace, King)}, Dealer: hand(Ten, Nine), Balance: 115}
+	unlocked := a.Evaluate(s)
+	if len(unlocked) != 2 || unlocked[0].ID != "first_win" || unlocked[1].ID != "natural" {
+		t.Fatalf("Expected first_win and natural, got %+v", unlocked)
+	}
+	if again := a.Evaluate(s); len(again) != 0 {
+		t.Errorf("Expected badges to unlock once, got %+v", again)
+	}
+
+	p, _ := players.Get("p1")
+	if !p.HasBadge("natural") || len(p.Badges) != 2 || p.Badges[0].GameID != "g1" {
+		t.Errorf("Expected the badges on the player, got %+v", p.Badges)
+	}
+
+	// The streak kept counting after first_win unlocked
+	if progress := a.Progress()["p1"]["hot_streak"]; progress != 2 {
+		t.Errorf("Expected a streak of 2, got %d", progress)
+	}
+}
+
+func TestAchievementsLeaveSnapshotCopiesAlone(t *testing.T) {
+	players := NewPlayerStore()
+	players.Save(&Player{ID: "p1", Balance: 100, Badges: make([]Badge, 0, 4)})
+	a := NewAchievements(players, DefaultAchievements)
+
+	taken := players.All()
+	a.Evaluate(Settlement{PlayerID: "p1", GameID: "g1", Time: time.Now(), Result: StatusPlayerWon, Staked: 10, Payout: 20,
+		Hands: []Hand{hand(Ten, Nine)}, Dealer: hand(Ten, Eight), Balance: 110})
+
+	if p, _ := players.Get("p1"); !p.HasBadge("first_win") {
+		t.Fatalf("Expected first_win on the stored player, got %+v", p.Badges)
+	}
+	if len(taken[0].Badges) != 0 || taken[0].Badges[:1][0].ID != "" {
+		t.Errorf("Expected the earlier copy to keep its badges, got %+v", taken[0].Badges[:1])
+	}
+}
+
+func TestSettlementHandsWonFollowRules(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		rules Rules
+		want  []bool
+	}{
+		{"classic pushes a 21 against a dealer 21", DefaultRules, []bool{false, false}},
+		{"spanish 21 pays a player 21", Spanish21Rules, []bool{true, false}},
+	} {
+		g := &GameState{Rules: tt.rules, BetAmount: 10, DealerHand: hand(King, Five, Six),
+			Hands: []Hand{split(Two, Three, Four, Five, Seven), split(Ten, Eight)}}
+		s := GameSettlement(g)
+		if len(s.HandsWon) != 2 || s.HandsWon[0] != tt.want[0] || s.HandsWon[1] != tt.want[1] {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, s.HandsWon)
+		}
+	}
+}
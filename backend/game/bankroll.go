@@ -0,0 +1,176 @@
+package game
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// BankrollPolicy decides how players get chips once they are out of them
+type BankrollPolicy string
+
+const (
+	BankrollNone  BankrollPolicy = "none"  // No refills at all
+	BankrollDaily BankrollPolicy = "daily" // Players claim a free-chip grant once per cooldown
+	BankrollAd    BankrollPolicy = "ad"    // Players claim a small grant for watching an ad, with a short cooldown
+	BankrollAdmin BankrollPolicy = "admin" // Only admins grant chips
+)
+
+// GrantSource is how a grant came about
+type GrantSource string
+
+const (
+	GrantDaily GrantSource = "daily"
+	GrantAd    GrantSource = "ad"
+	GrantAdmin GrantSource = "admin"
+)
+
+var (
+	ErrClaimsDisabled = errors.New("the bankroll policy does not allow claims")
+	ErrClaimCooldown  = errors.New("already claimed; try again later")
+)
+
+// BankrollConfig configures the bankroll policy
+type BankrollConfig struct {
+	Policy   BankrollPolicy `json:"policy"`
+	Amount   int            `json:"amount"`   // Chips per claim
+	Cooldown time.Duration  `json:"cooldown"` // Between two claims of a player
+}
+
+// DefaultBankrollConfig returns the usual grant and cooldown of a policy
+func DefaultBankrollConfig(policy BankrollPolicy) (BankrollConfig, bool) {
+	switch policy {
+	case BankrollDaily:
+		return BankrollConfig{Policy: policy, Amount: 100, Cooldown: 24 * time.Hour}, true
+	case BankrollAd:
+		return BankrollConfig{Policy: policy, Amount: 25, Cooldown: 5 * time.Minute}, true
+	case BankrollNone, BankrollAdmin:
+		return BankrollConfig{Policy: policy}, true
+	}
+	return BankrollConfig{}, false
+}
+
+// Grant is a recorded gift of chips
+type Grant struct {
+	PlayerID      string      `json:"player_id"`
+	Source        GrantSource `json:"source"`
+	Amount        int         `json:"amount"`
+	Note          string      `json:"note,omitempty"`
+	Time          time.Time   `json:"time"`
+	TransactionID int         `json:"transaction_id"`
+}
+
+// Bankroll gives out chips under the configured policy and records every grant.
+// All methods are safe for concurrent use.
+type Bankroll struct {
+	mu     sync.Mutex
+	config BankrollConfig
+	ledger *Ledger
+	grants map[string][]Grant // Per player, oldest first
+}
+
+// NewBankroll creates a Bankroll paying grants through the ledger
+func NewBankroll(ledger *Ledger, config BankrollConfig) *Bankroll {
+	return &Bankroll{config: config, ledger: ledger, grants: make(map[string][]Grant)}
+}
+
+// Config returns the bankroll policy in force
+func (b *Bankroll) Config() BankrollConfig {
+	return b.config
+}
+
+// NextClaim returns when a player may claim again; the zero time if right away or never
+func (b *Bankroll) NextClaim(playerID string) time.Time {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.nextClaim(playerID)
+}
+
+// nextClaim is NextClaim for callers holding the lock
+func (b *Bankroll) nextClaim(playerID string) time.Time {
+	grants := b.grants[playerID]
+	for i := len(grants) - 1; i >= 0; i-- {
+		if grants[i].Source != GrantAdmin {
+			return grants[i].Time.Add(b.config.Cooldown)
+		}
+	}
+	return time.Time{}
+}
+
+// Claim gives a player the grant of the daily or ad policy, unless still cooling down
+func (b *Bankroll) Claim(playerID string, now time.Time) (Grant, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	source := GrantDaily
+	switch b.config.Policy {
+	case BankrollDaily:
+	case BankrollAd:
+		source = GrantAd
+	default:
+		return Grant{}, ErrClaimsDisabled
+	}
+	if next := b.nextClaim(playerID); now.Before(next) {
+		return Grant{}, ErrClaimCooldown
+	}
+	return b.grant(playerID, source, b.config.Amount, "", now)
+}
+
+// AdminGrant gives a player chips on an admin's behalf, whatever the policy
+func (b *Bankroll) AdminGrant(playerID string, amount int, note string, now time.Time) (Grant, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.grant(playerID, GrantAdmin, amount, note, now)
+}
+
+// grant credits and records a grant; callers hold the lock
+func (b *Bankroll) grant(playerID string, source GrantSource, amount int, note string, now time.Time) (Grant, error) {
+	// The ledger posts signed amounts, so a negative grant would quietly take chips away
+	if amount <= 0 {
+		return Grant{}, ErrInvalidAmount
+	}
+	reason := string(source) + " grant"
+	if note != "" {
+		reason += ": " + note
+	}
+	tx, err := b.ledger.Credit(playerID, TxGrant, "", amount, reason)
+	if err != nil {
+		return Grant{}, err
+	}
+	g := Grant{PlayerID: playerID, Source: source, Amount: amount, Note: note, Time: now, TransactionID: tx.ID}
+	b.grants[playerID] = append(b.grants[playerID], g)
+	return g, nil
+}
+
+// Grants returns a player's grants, newest first
+func (b *Bankroll) Grants(playerID string) []Grant {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	grants := make([]Grant, 0, len(b.grants[playerID]))
+	for i := len(b.grants[playerID]) - 1; i >= 0; i-- {
+		grants = append(grants, b.grants[playerID][i])
+	}
+	return grants
+}
+
+// All returns every grant, oldest first
+func (b *Bankroll) All() []Grant {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var grants []Grant
+	for _, g := range b.grants {
+		grants = append(grants, g...)
+	}
+	sort.SliceStable(grants, func(i, j int) bool { return grants[i].Time.Before(grants[j].Time) })
+	return grants
+}
+
+// Restore loads grants saved with All
+func (b *Bankroll) Restore(grants []Grant) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, g := range grants {
+		b.grants[g.PlayerID] = append(b.grants[g.PlayerID], g)
+	}
+}
@@ -0,0 +1,56 @@
+package game
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBankrollClaims(t *testing.T) {
+	players := NewPlayerStore()
+	players.Save(&Player{ID: "p1"})
+	ledger := NewLedger(players)
+	now := time.Now()
+
+	daily, _ := DefaultBankrollConfig(BankrollDaily)
+	bankroll := NewBankroll(ledger, daily)
+	if _, err := bankroll.Claim("p1", now); err != nil {
+		t.Fatalf("Expected the first claim to succeed, got %v", err)
+	}
+	if _, err := bankroll.Claim("p1", now.Add(time.Hour)); !errors.Is(err, ErrClaimCooldown) {
+		t.Errorf("Expected ErrClaimCooldown within a day, got %v", err)
+	}
+	if _, err := bankroll.AdminGrant("p1", 50, "support ticket", now.Add(2*time.Hour)); err != nil {
+		t.Fatalf("Expected admin grants during the cooldown, got %v", err)
+	}
+	if _, err := bankroll.AdminGrant("p1", -500, "", now.Add(2*time.Hour)); !errors.Is(err, ErrInvalidAmount) {
+		t.Errorf("Expected ErrInvalidAmount for a negative grant, got %v", err)
+	}
+	if _, err := bankroll.Claim("p1", now.Add(25*time.Hour)); err != nil {
+		t.Errorf("Expected a claim after the cooldown, got %v", err)
+	}
+	if p, _ := players.Get("p1"); p.Balance != 250 {
+		t.Errorf("Expected balance 250, got %d", p.Balance)
+	}
+
+	grants := bankroll.Grants("p1")
+	if len(grants) != 3 || grants[1].Source != GrantAdmin || grants[1].Note != "support ticket" {
+		t.Errorf("Expected three grants, newest first, got %+v", grants)
+	}
+	posted := 0
+	for _, tx := range ledger.Transactions("p1") {
+		if tx.Kind == TxGrant {
+			posted++
+		}
+	}
+	if posted != 3 {
+		t.Errorf("Expected every grant in the ledger, got %d", posted)
+	}
+
+	for _, policy := range []BankrollPolicy{BankrollNone, BankrollAdmin} {
+		config, _ := DefaultBankrollConfig(policy)
+		if _, err := NewBankroll(ledger, config).Claim("p1", now); !errors.Is(err, ErrClaimsDisabled) {
+			t.Errorf("%s: expected ErrClaimsDisabled, got %v", policy, err)
+		}
+	}
+}
@@ -0,0 +1,252 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrInvalidBonusRule = errors.New("invalid bonus rule")
+	ErrInvalidPromotion = errors.New("invalid promotion")
+)
+
+// BonusHand is a bonus payout parsed from a rule of the bonus rules language:
+//
+//	[suited | hearts | diamonds | clubs | spades] [<rank>-<rank>-...] [21] [<n> cards | <n>+ cards] pays <a>:<b>
+//
+// e.g. "suited 6-7-8 pays 2:1", "7-7-7 pays 3:1" or "21 5+ cards pays 3:2".
+// Ranks are A, 2-10, J, Q and K and match in any order. Every condition must hold.
+// A matching hand wins regardless of the dealer's total, except against a natural, so every rule
+// is limited to 21s: it either says 21 or names ranks that make 21.
+type BonusHand struct {
+	Rule string  `json:"rule"`
+	Pays float64 `json:"pays"` // To 1
+
+	ranks    []Rank
+	suited   bool
+	suit     Suit
+	total    int
+	minCards int
+	maxCards int // 0 for no limit
+}
+
+// bonusSuits are the suit words of the bonus rules language
+var bonusSuits = map[string]Suit{"hearts": Hearts, "diamonds": Diamonds, "clubs": Clubs, "spades": Spades}
+
+// ParseBonus parses a rule of the bonus rules language
+func ParseBonus(rule string) (BonusHand, error) {
+	b := BonusHand{Rule: rule}
+	tokens := strings.Fields(strings.ToLower(rule))
+	n := len(tokens)
+	if n < 3 || tokens[n-2] != "pays" {
+		return b, fmt.Errorf("%w: expected <conditions> pays <a>:<b>", ErrInvalidBonusRule)
+	}
+
+	odds := strings.Split(tokens[n-1], ":")
+	if len(odds) != 2 {
+		return b, fmt.Errorf("%w: odds must be written as <a>:<b>", ErrInvalidBonusRule)
+	}
+	a, errA := strconv.Atoi(odds[0])
+	d, errD := strconv.Atoi(odds[1])
+	if errA != nil || errD != nil || a < 1 || d < 1 {
+		return b, fmt.Errorf("%w: odds must be written as <a>:<b>", ErrInvalidBonusRule)
+	}
+	b.Pays = float64(a) / float64(d)
+
+	conditions := tokens[:n-2]
+	for i := 0; i < len(conditions); i++ {
+		token := conditions[i]
+		switch {
+		case token == "suited":
+			b.suited = true
+		case bonusSuits[token] != "":
+			b.suit = bonusSuits[token]
+		case i+1 < len(conditions) && conditions[i+1] == "cards":
+			count, err := strconv.Atoi(strings.TrimSuffix(token, "+"))
+			if err != nil || count < 2 {
+				return b, fmt.Errorf("%w: bad card count %q", ErrInvalidBonusRule, token)
+			}
+			b.minCards = count
+			if !strings.HasSuffix(token, "+") {
+				b.maxCards = count
+			}
+			i++
+		case token == "21":
+			b.total = 21
+		case strings.Contains(token, "-"):
+			for _, r := range strings.Split(token, "-") {
+				rank := Rank(strings.ToUpper(r))
+				if _, ok := rankOrder[rank]; !ok {
+					return b, fmt.Errorf("%w: unknown rank %q", ErrInvalidBonusRule, r)
+				}
+				b.ranks = append(b.ranks, rank)
+			}
+		default:
+			return b, fmt.Errorf("%w: unexpected %q", ErrInvalidBonusRule, token)
+		}
+	}
+	if len(conditions) == 0 {
+		return b, fmt.Errorf("%w: no conditions", ErrInvalidBonusRule)
+	}
+	if b.ranks != nil {
+		cards := make([]Card, len(b.ranks))
+		for i, r := range b.ranks {
+			cards[i] = Card{Rank: r}
+		}
+		if CalculateScore(cards) != 21 {
+			return b, fmt.Errorf("%w: the ranks must make 21", ErrInvalidBonusRule)
+		}
+		b.total = 21
+	}
+	if b.total != 21 {
+		return b, fmt.Errorf("%w: only 21s can pay a bonus", ErrInvalidBonusRule)
+	}
+	return b, nil
+}
+
+// Matches reports whether a hand that did not bust meets every condition of the rule
+func (b BonusHand) Matches(hand Hand) bool {
+	cards := hand.Cards
+	if IsBust(hand.Score) || hand.Surrendered {
+		return false
+	}
+	if b.ranks != nil {
+		if len(cards) != len(b.ranks) {
+			return false
+		}
+		want := map[Rank]int{}
+		for _, r := range b.ranks {
+			want[r]++
+		}
+		for _, c := range cards {
+			want[c.Rank]--
+			if want[c.Rank] < 0 {
+				return false
+			}
+		}
+	}
+	for _, c := range cards {
+		if (b.suited && c.Suit != cards[0].Suit) || (b.suit != "" && c.Suit != b.suit) {
+			return false
+		}
+	}
+	if b.total != 0 && hand.Score != b.total {
+		return false
+	}
+	if len(cards) < b.minCards || (b.maxCards != 0 && len(cards) > b.maxCards) {
+		return false
+	}
+	return true
+}
+
+// Payout returns what the bonus pays back, including the bet
+func (b BonusHand) Payout(bet int) int {
+	return bet + int(float64(bet)*b.Pays)
+}
+
+// Promotion runs a bonus rule for a limited time
+type Promotion struct {
+	ID       string    `json:"id"`
+	Name     string    `json:"name"`
+	Rule     string    `json:"rule"`               // See BonusHand
+	Variants []Variant `json:"variants,omitempty"` // Empty for every variant
+	StartsAt time.Time `json:"starts_at"`
+	EndsAt   time.Time `json:"ends_at"`
+}
+
+// Validate checks the rule and the schedule
+func (p Promotion) Validate() error {
+	if _, err := ParseBonus(p.Rule); err != nil {
+		return err
+	}
+	if p.Name == "" || p.EndsAt.IsZero() || !p.EndsAt.After(p.StartsAt) {
+		return fmt.Errorf("%w: a name and an end after the start are required", ErrInvalidPromotion)
+	}
+	for _, v := range p.Variants {
+		if _, ok := RulesFor(v); !ok {
+			return fmt.Errorf("%w: unknown variant %q", ErrInvalidPromotion, v)
+		}
+	}
+	return nil
+}
+
+// Running reports whether the promotion runs at a time
+func (p Promotion) Running(now time.Time) bool {
+	return !now.Before(p.StartsAt) && now.Before(p.EndsAt)
+}
+
+// ActiveAt reports whether the promotion runs at a time for games of a variant
+func (p Promotion) ActiveAt(now time.Time, variant Variant) bool {
+	if !p.Running(now) {
+		return false
+	}
+	if len(p.Variants) == 0 {
+		return true
+	}
+	for _, v := range p.Variants {
+		if v == variant {
+			return true
+		}
+	}
+	return false
+}
+
+// PromotionStore is a thread-safe in-memory store for bonus promotions
+type PromotionStore struct {
+	mu         sync.RWMutex
+	promotions map[string]Promotion
+}
+
+// NewPromotionStore creates an empty PromotionStore
+func NewPromotionStore() *PromotionStore {
+	return &PromotionStore{promotions: make(map[string]Promotion)}
+}
+
+// Save stores a promotion, replacing any with the same ID
+func (s *PromotionStore) Save(p Promotion) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.promotions[p.ID] = p
+}
+
+// Delete removes a promotion; it reports whether it existed
+func (s *PromotionStore) Delete(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, exists := s.promotions[id]
+	delete(s.promotions, id)
+	return exists
+}
+
+// All returns every promotion, by start time
+func (s *PromotionStore) All() []Promotion {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	promotions := make([]Promotion, 0, len(s.promotions))
+	for _, p := range s.promotions {
+		promotions = append(promotions, p)
+	}
+	sort.Slice(promotions, func(i, j int) bool {
+		if !promotions[i].StartsAt.Equal(promotions[j].StartsAt) {
+			return promotions[i].StartsAt.Before(promotions[j].StartsAt)
+		}
+		return promotions[i].ID < promotions[j].ID
+	})
+	return promotions
+}
+
+// Active returns the promotions running at a time for games of a variant
+func (s *PromotionStore) Active(now time.Time, variant Variant) []Promotion {
+	var active []Promotion
+	for _, p := range s.All() {
+		if p.ActiveAt(now, variant) {
+			active = append(active, p)
+		}
+	}
+	return active
+}
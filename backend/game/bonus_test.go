@@ -0,0 +1,108 @@
+package game
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseBonus(t *testing.T) {
+	hand := func(suit Suit, ranks ...Rank) Hand {
+		h := Hand{}
+		for _, r := range ranks {
+			h.Cards = append(h.Cards, Card{Suit: suit, Rank: r})
+		}
+		h.Score = CalculateScore(h.Cards)
+		return h
+	}
+
+	tests := []struct {
+		rule    string
+		hand    Hand
+		matches bool
+		payout  int
+	}{
+		{"suited 6-7-8 pays 2:1", hand(Hearts, Eight, Six, Seven), true, 30},
+		{"suited 6-7-8 pays 2:1", Hand{Cards: []Card{{Hearts, Six, 0}, {Clubs, Seven, 0}, {Hearts, Eight, 0}}, Score: 21}, false, 0},
+		{"7-7-7 pays 3:1", hand(Spades, Seven, Seven, Seven), true, 40},
+		{"spades 7-7-7 pays 5:1", hand(Hearts, Seven, Seven, Seven), false, 0},
+		{"21 5+ cards pays 3:2", hand(Clubs, Two, Three, Four, Five, Seven), true, 25},
+		{"21 5+ cards pays 3:2", hand(Clubs, Two, Three, Four, Five, Six), false, 0},
+		{"21 6 cards pays 1:1", hand(Clubs, Two, Two, Three, Three, Four, Seven), true, 20},
+		{"21 6 cards pays 1:1", hand(Clubs, Two, Two, Two, Three, Three, Four), false, 0},
+	}
+	for _, tt := range tests {
+		bonus, err := ParseBonus(tt.rule)
+		if err != nil {
+			t.Fatalf("%q: %v", tt.rule, err)
+		}
+		if got := bonus.Matches(tt.hand); got != tt.matches {
+			t.Errorf("%q on %v: expected match %v, got %v", tt.rule, tt.hand.Cards, tt.matches, got)
+		}
+		if tt.matches && bonus.Payout(10) != tt.payout {
+			t.Errorf("%q: expected payout %d, got %d", tt.rule, tt.payout, bonus.Payout(10))
+		}
+	}
+
+	for _, rule := range []string{"", "pays 2:1", "6-7-8 pays", "6-7-8 pays 2", "6-7-X pays 2:1", "lucky 7-7-7 pays 3:1", "1 cards pays 1:1",
+		"5-5 pays 2:1", "21 5-5 pays 2:1", "suited pays 2:1", "6 cards pays 1:1"} {
+		if _, err := ParseBonus(rule); !errors.Is(err, ErrInvalidBonusRule) {
+			t.Errorf("%q: expected ErrInvalidBonusRule, got %v", rule, err)
+		}
+	}
+}
+
+func TestCharlie(t *testing.T) {
+	rules := DefaultRules
+	rules.Charlie = 5
+	charlie := Hand{Cards: []Card{{Rank: Two}, {Rank: Three}, {Rank: Two}, {Rank: Four}, {Rank: Five}}, Score: 16}
+	twenty := Hand{Cards: []Card{{Rank: King}, {Rank: Queen}}, Score: 20}
+	natural := Hand{Cards: []Card{{Rank: Ace}, {Rank: King}}, Score: 21}
+
+	if !rules.HandComplete(charlie) || DefaultRules.HandComplete(charlie) {
+		t.Error("Expected a five-card Charlie to take no more cards")
+	}
+	if got := rules.HandPayout(charlie, twenty, 10); got != 20 {
+		t.Errorf("Expected the Charlie to win at even money, got %d", got)
+	}
+	if got := rules.HandPayout(charlie, natural, 10); got != 0 {
+		t.Errorf("Expected a dealer natural to beat the Charlie, got %d", got)
+	}
+
+	// The best-paying bonus wins
+	charlie21 := Hand{Cards: []Card{{Rank: Two}, {Rank: Three}, {Rank: Two}, {Rank: Four}, {Rank: King}}, Score: 21}
+	rules.Bonuses = []string{"21 5 cards pays 2:1"}
+	if bonus, ok := rules.BonusFor(charlie21); !ok || bonus.Payout(10) != 30 {
+		t.Errorf("Expected the 2:1 bonus over the Charlie, got %+v", bonus)
+	}
+	if bonus, ok := rules.BonusFor(charlie); !ok || bonus.Payout(10) != 20 {
+		t.Errorf("Expected the Charlie without a 21, got %+v", bonus)
+	}
+
+	// A Spanish 21 five-card 21 pays its 3:2 bonus rather than the Charlie's even money
+	spanish := Spanish21Rules
+	spanish.Charlie = 5
+	if got := spanish.HandPayout(charlie21, twenty, 10); got != 25 {
+		t.Errorf("Expected the 3:2 five-card 21 over the Charlie, got %d", got)
+	}
+}
+
+func TestPromotionSchedule(t *testing.T) {
+	now := time.Now()
+	store := NewPromotionStore()
+	store.Save(Promotion{ID: "all", Name: "All", Rule: "7-7-7 pays 3:1", StartsAt: now.Add(-time.Hour), EndsAt: now.Add(time.Hour)})
+	store.Save(Promotion{ID: "spanish", Name: "Spanish", Rule: "7-7-7 pays 3:1", Variants: []Variant{VariantSpanish21}, StartsAt: now.Add(-time.Hour), EndsAt: now.Add(time.Hour)})
+	store.Save(Promotion{ID: "over", Name: "Over", Rule: "7-7-7 pays 3:1", StartsAt: now.Add(-2 * time.Hour), EndsAt: now.Add(-time.Hour)})
+
+	if active := store.Active(now, VariantClassic); len(active) != 1 || active[0].ID != "all" {
+		t.Errorf("Expected only the promotion for every variant, got %+v", active)
+	}
+	if active := store.Active(now, VariantSpanish21); len(active) != 2 {
+		t.Errorf("Expected both running promotions, got %+v", active)
+	}
+
+	invalid := Promotion{Name: "Backwards", Rule: "7-7-7 pays 3:1", StartsAt: now, EndsAt: now.Add(-time.Hour)}
+	if err := invalid.Validate(); !errors.Is(err, ErrInvalidPromotion) {
+		t.Errorf("Expected ErrInvalidPromotion, got %v", err)
+	}
+}
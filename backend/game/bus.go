@@ -0,0 +1,95 @@
+package game
+
+import (
+	"sync"
+	"time"
+)
+
+// NotificationType identifies what a Notification carries
+type NotificationType string
+
+const (
+	NotifyGameEvent NotificationType = "game_event" // A deal, action or settlement
+	NotifyBalance   NotificationType = "balance"    // A ledger transaction
+	NotifyTable     NotificationType = "table"      // A table changed; fetch its state to see how
+)
+
+// Notification is a message published on the Bus
+type Notification struct {
+	Type        NotificationType `json:"type"`
+	Time        time.Time        `json:"time"`
+	GameID      string           `json:"game_id,omitempty"`
+	TableID     string           `json:"table_id,omitempty"`
+	PlayerID    string           `json:"player_id,omitempty"`
+	Event       *Event           `json:"event,omitempty"`
+	Transaction *Transaction     `json:"transaction,omitempty"`
+	Seated      []string         `json:"-"` // Players at the table of a NotifyTable, for subscribers to filter on
+}
+
+// Name returns the name used for the notification on event streams
+func (n Notification) Name() string {
+	if n.Event != nil {
+		return string(n.Event.Type)
+	}
+	return string(n.Type)
+}
+
+// subscriberBuffer is how many notifications a slow subscriber may fall behind before dropping
+const subscriberBuffer = 64
+
+type subscription struct {
+	ch     chan Notification
+	filter func(Notification) bool
+}
+
+// Bus is an in-process publish/subscribe hub for game and balance notifications
+type Bus struct {
+	mu   sync.RWMutex
+	subs map[int]*subscription
+	next int
+}
+
+// NewBus creates a new Bus
+func NewBus() *Bus {
+	return &Bus{subs: make(map[int]*subscription)}
+}
+
+// Subscribe registers a subscriber; filter may be nil to receive everything.
+// The returned function unsubscribes and closes the channel.
+func (b *Bus) Subscribe(filter func(Notification) bool) (<-chan Notification, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	id := b.next
+	b.next++
+	sub := &subscription{ch: make(chan Notification, subscriberBuffer), filter: filter}
+	b.subs[id] = sub
+
+	var once sync.Once
+	return sub.ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			delete(b.subs, id)
+			close(sub.ch)
+		})
+	}
+}
+
+// Publish delivers a notification to every matching subscriber.
+// It never blocks: subscribers with a full buffer miss the notification.
+func (b *Bus) Publish(n Notification) {
+	if n.Time.IsZero() {
+		n.Time = time.Now()
+	}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, sub := range b.subs {
+		if sub.filter != nil && !sub.filter(n) {
+			continue
+		}
+		select {
+		case sub.ch <- n:
+		default:
+		}
+	}
+}
@@ -0,0 +1,360 @@
+package game
+
+import (
+	"errors"
+	"math"
+	"sync"
+)
+
+// CountSystem is a card counting system
+type CountSystem string
+
+const (
+	CountHiLo    CountSystem = "hi-lo"
+	CountKO      CountSystem = "ko"       // Unbalanced: no true count conversion
+	CountOmegaII CountSystem = "omega-ii" // Level 2
+)
+
+// countTags holds the tag of every rank per system
+var countTags = map[CountSystem]map[Rank]int{
+	CountHiLo: {
+		Two: 1, Three: 1, Four: 1, Five: 1, Six: 1,
+		Ten: -1, Jack: -1, Queen: -1, King: -1, Ace: -1,
+	},
+	CountKO: {
+		Two: 1, Three: 1, Four: 1, Five: 1, Six: 1, Seven: 1,
+		Ten: -1, Jack: -1, Queen: -1, King: -1, Ace: -1,
+	},
+	CountOmegaII: {
+		Two: 1, Three: 1, Seven: 1, Four: 2, Five: 2, Six: 2,
+		Nine: -1, Ten: -2, Jack: -2, Queen: -2, King: -2,
+	},
+}
+
+// Valid reports whether the system is supported
+func (s CountSystem) Valid() bool {
+	_, ok := countTags[s]
+	return ok
+}
+
+// Tag returns how a card changes the running count
+func (s CountSystem) Tag(card Card) int {
+	return countTags[s][card.Rank]
+}
+
+// Balanced reports whether the system converts to a true count
+func (s CountSystem) Balanced() bool {
+	return s != CountKO
+}
+
+// InitialCount is the running count off the top of a fresh shoe
+func (s CountSystem) InitialCount(decks int) int {
+	if s == CountKO {
+		return 4 - 4*decks
+	}
+	return 0
+}
+
+// TrainerMode is how the trainer deals
+type TrainerMode string
+
+const (
+	TrainerTable TrainerMode = "table" // Rounds dealt to seats and a dealer
+	TrainerDrill TrainerMode = "drill" // Count-only: cards in batches, no betting
+)
+
+// TrueCountTolerance is how far off a true count answer may be and still count as correct
+const TrueCountTolerance = 0.5
+
+var (
+	ErrQuizPending          = errors.New("answer the count quiz first")
+	ErrNoQuiz               = errors.New("no count quiz pending")
+	ErrInvalidTrainerConfig = errors.New("invalid trainer settings")
+)
+
+// TrainerConfig configures a counting trainer
+type TrainerConfig struct {
+	System       CountSystem `json:"system"`
+	Decks        int         `json:"decks"`
+	Mode         TrainerMode `json:"mode"`
+	QuizEvery    int         `json:"quiz_every"`     // Cards dealt between quizzes
+	Seats        int         `json:"seats"`          // Table mode: seats dealt besides the dealer
+	CardsPerDeal int         `json:"cards_per_deal"` // Drill mode
+}
+
+// DefaultTrainerConfig fills in settings left at zero
+var DefaultTrainerConfig = TrainerConfig{System: CountHiLo, Decks: 6, Mode: TrainerTable, QuizEvery: 30, Seats: 3, CardsPerDeal: 3}
+
+// Validate checks the settings and fills in defaults
+func (c *TrainerConfig) Validate() error {
+	if c.System == "" {
+		c.System = DefaultTrainerConfig.System
+	}
+	if c.Mode == "" {
+		c.Mode = DefaultTrainerConfig.Mode
+	}
+	if c.Decks == 0 {
+		c.Decks = DefaultTrainerConfig.Decks
+	}
+	if c.QuizEvery == 0 {
+		c.QuizEvery = DefaultTrainerConfig.QuizEvery
+	}
+	if c.Seats == 0 {
+		c.Seats = DefaultTrainerConfig.Seats
+	}
+	if c.CardsPerDeal == 0 {
+		c.CardsPerDeal = DefaultTrainerConfig.CardsPerDeal
+	}
+	if !c.System.Valid() || (c.Mode != TrainerTable && c.Mode != TrainerDrill) ||
+		c.Decks < 1 || c.Decks > 8 || c.Seats < 1 || c.Seats > MaxSeats {
+		return ErrInvalidTrainerConfig
+	}
+	// A deal must fit in the cards played before the cut card, a quiz in one shoe
+	playable := c.Decks * 52 * 3 / 4
+	if c.CardsPerDeal < 1 || c.CardsPerDeal >= playable || c.QuizEvery < 1 || c.QuizEvery > c.Decks*52 {
+		return ErrInvalidTrainerConfig
+	}
+	return nil
+}
+
+// TrainerDeal is what one deal showed
+type TrainerDeal struct {
+	Shuffled bool   `json:"shuffled"`        // The shoe was reshuffled first; the count starts over
+	Seats    []Hand `json:"seats,omitempty"` // Table mode
+	Dealer   *Hand  `json:"dealer,omitempty"`
+	Cards    []Card `json:"cards"`    // Every card dealt, in order
+	QuizDue  bool   `json:"quiz_due"` // Answer the count before the next deal
+}
+
+// TrainerQuiz is the state of the count quiz, without the answer
+type TrainerQuiz struct {
+	Pending        bool        `json:"pending"`
+	System         CountSystem `json:"system"`
+	AskTrueCount   bool        `json:"ask_true_count"`
+	CardsSeen      int         `json:"cards_seen"` // Since the last shuffle
+	DecksRemaining float64     `json:"decks_remaining"`
+	Asked          int         `json:"asked"`
+	Correct        int         `json:"correct"`
+	Streak         int         `json:"streak"`
+}
+
+// QuizResult grades an answer
+type QuizResult struct {
+	Correct      bool     `json:"correct"`
+	RunningCount int      `json:"running_count"`
+	TrueCount    *float64 `json:"true_count,omitempty"` // Balanced systems only
+	Asked        int      `json:"asked"`
+	Accuracy     float64  `json:"accuracy"`
+	Streak       int      `json:"streak"`
+}
+
+// TrainerState is the serializable state of a counting trainer
+type TrainerState struct {
+	PlayerID       string
+	Config         TrainerConfig
+	Shoe           Shoe
+	RunningCount   int
+	CardsSeen      int
+	CardsSinceQuiz int
+	QuizPending    bool
+	Asked          int
+	Correct        int
+	Streak         int
+}
+
+// Trainer keeps a persistent shoe and quizzes a player on its count.
+// All methods are safe for concurrent use.
+type Trainer struct {
+	mu    sync.Mutex
+	state TrainerState
+}
+
+// NewTrainer starts a trainer on a fresh shoe
+func NewTrainer(playerID string, config TrainerConfig) (*Trainer, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	t := &Trainer{state: TrainerState{PlayerID: playerID, Config: config, Shoe: *NewShoe(config.Decks)}}
+	t.state.RunningCount = config.System.InitialCount(config.Decks)
+	return t, nil
+}
+
+// Deal deals the next round or batch of cards and updates the count
+func (t *Trainer) Deal() (TrainerDeal, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.state.QuizPending {
+		return TrainerDeal{}, ErrQuizPending
+	}
+
+	var deal TrainerDeal
+	// A table round must not run out of cards halfway; six per hand is plenty in practice
+	short := t.state.Config.Mode == TrainerTable && t.state.Shoe.Remaining() < (t.state.Config.Seats+1)*6
+	short = short || (t.state.Config.Mode == TrainerDrill && t.state.Shoe.Remaining() <= t.state.Config.CardsPerDeal)
+	if t.state.Shoe.NeedsShuffle() || short {
+		t.state.Shoe.Shuffle()
+		t.state.RunningCount = t.state.Config.System.InitialCount(t.state.Config.Decks)
+		t.state.CardsSeen = 0
+		deal.Shuffled = true
+	}
+
+	if t.state.Config.Mode == TrainerDrill {
+		for i := 0; i < t.state.Config.CardsPerDeal && t.state.Shoe.Remaining() > 0; i++ {
+			deal.Cards = append(deal.Cards, t.draw())
+		}
+	} else {
+		t.dealRound(&deal)
+	}
+
+	if t.state.CardsSinceQuiz >= t.state.Config.QuizEvery {
+		t.state.QuizPending = true
+	}
+	deal.QuizDue = t.state.QuizPending
+	return deal, nil
+}
+
+// dealRound deals every seat and the dealer two cards; seats and dealer then draw to 17
+func (t *Trainer) dealRound(deal *TrainerDeal) {
+	seats := make([]Hand, t.state.Config.Seats)
+	dealer := Hand{}
+	for pass := 0; pass < 2; pass++ {
+		for i := range seats {
+			seats[i].Cards = append(seats[i].Cards, t.draw())
+		}
+		dealer.Cards = append(dealer.Cards, t.draw())
+	}
+	for _, hand := range append(seats, dealer) {
+		deal.Cards = append(deal.Cards, hand.Cards...)
+	}
+
+	play := func(hand *Hand) {
+		hand.Score = CalculateScore(hand.Cards)
+		for ShouldDealerHit(*hand) && t.state.Shoe.Remaining() > 0 {
+			card := t.draw()
+			deal.Cards = append(deal.Cards, card)
+			hand.Cards = append(hand.Cards, card)
+			hand.Score = CalculateScore(hand.Cards)
+		}
+	}
+	for i := range seats {
+		play(&seats[i])
+	}
+	play(&dealer)
+
+	deal.Seats = seats
+	deal.Dealer = &dealer
+}
+
+// draw takes a card and counts it; callers hold the lock
+func (t *Trainer) draw() Card {
+	card := t.state.Shoe.Draw()
+	t.state.RunningCount += t.state.Config.System.Tag(card)
+	t.state.CardsSeen++
+	t.state.CardsSinceQuiz++
+	return card
+}
+
+// Quiz returns the state of the count quiz
+func (t *Trainer) Quiz() TrainerQuiz {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return TrainerQuiz{
+		Pending:        t.state.QuizPending,
+		System:         t.state.Config.System,
+		AskTrueCount:   t.state.Config.System.Balanced(),
+		CardsSeen:      t.state.CardsSeen,
+		DecksRemaining: math.Round(t.state.Shoe.DecksRemaining()*10) / 10,
+		Asked:          t.state.Asked,
+		Correct:        t.state.Correct,
+		Streak:         t.state.Streak,
+	}
+}
+
+// Answer grades the player's count. For balanced systems a true count may be given too;
+// it must be within TrueCountTolerance.
+func (t *Trainer) Answer(runningCount int, trueCount *float64) (QuizResult, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.state.QuizPending {
+		return QuizResult{}, ErrNoQuiz
+	}
+
+	correct := runningCount == t.state.RunningCount
+	result := QuizResult{RunningCount: t.state.RunningCount}
+	if t.state.Config.System.Balanced() {
+		// An empty shoe counts as one card left, so the true count stays finite
+		tc := float64(t.state.RunningCount) / max(t.state.Shoe.DecksRemaining(), 1.0/52)
+		tc = math.Round(tc*10) / 10
+		result.TrueCount = &tc
+		if trueCount != nil && math.Abs(*trueCount-tc) > TrueCountTolerance {
+			correct = false
+		}
+	}
+
+	t.state.Asked++
+	if correct {
+		t.state.Correct++
+		t.state.Streak++
+	} else {
+		t.state.Streak = 0
+	}
+	t.state.QuizPending = false
+	t.state.CardsSinceQuiz = 0
+
+	result.Correct = correct
+	result.Asked = t.state.Asked
+	result.Accuracy = float64(t.state.Correct) / float64(t.state.Asked)
+	result.Streak = t.state.Streak
+	return result, nil
+}
+
+// persistentState returns a copy of the state including the shoe, for snapshots
+func (t *Trainer) persistentState() TrainerState {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	state := t.state
+	state.Shoe.Cards = append([]Card(nil), t.state.Shoe.Cards...)
+	return state
+}
+
+// TrainerStore keeps one counting trainer per player
+type TrainerStore struct {
+	mu       sync.RWMutex
+	trainers map[string]*Trainer
+}
+
+// NewTrainerStore creates an empty TrainerStore
+func NewTrainerStore() *TrainerStore {
+	return &TrainerStore{trainers: make(map[string]*Trainer)}
+}
+
+// Save stores a player's trainer, replacing any previous one
+func (s *TrainerStore) Save(t *Trainer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.trainers[t.state.PlayerID] = t
+}
+
+// Get retrieves a player's trainer
+func (s *TrainerStore) Get(playerID string) (*Trainer, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	t, exists := s.trainers[playerID]
+	return t, exists
+}
+
+// All returns every stored trainer
+func (s *TrainerStore) All() []*Trainer {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	trainers := make([]*Trainer, 0, len(s.trainers))
+	for _, t := range s.trainers {
+		trainers = append(trainers, t)
+	}
+	return trainers
+}
+
+// Restore stores a trainer recreated from saved state
+func (s *TrainerStore) Restore(state TrainerState) {
+	s.Save(&Trainer{state: state})
+}
@@ -0,0 +1,140 @@
+package game
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCountSystemTags(t *testing.T) {
+	tests := []struct {
+		system CountSystem
+		rank   Rank
+		want   int
+	}{
+		{CountHiLo, Two, 1}, {CountHiLo, Seven, 0}, {CountHiLo, King, -1}, {CountHiLo, Ace, -1},
+		{CountKO, Seven, 1}, {CountKO, Ace, -1},
+		{CountOmegaII, Four, 2}, {CountOmegaII, Nine, -1}, {CountOmegaII, Queen, -2}, {CountOmegaII, Ace, 0},
+	}
+	for _, tt := range tests {
+		if got := tt.system.Tag(Card{Suit: Spades, Rank: tt.rank}); got != tt.want {
+			t.Errorf("%s tag for %s: expected %d, got %d", tt.system, tt.rank, tt.want, got)
+		}
+	}
+
+	// A balanced count ends at zero after a full shoe; KO ends at its pivot
+	for _, system := range []CountSystem{CountHiLo, CountOmegaII, CountKO} {
+		count := system.InitialCount(2)
+		for _, card := range NewDecks(2) {
+			count += system.Tag(card)
+		}
+		want := 0
+		if system == CountKO {
+			want = 4 // KO starts at 4 - 4*decks and adds +4 per deck
+		}
+		if count != want {
+			t.Errorf("%s: expected count %d after a full shoe, got %d", system, want, count)
+		}
+	}
+}
+
+func TestTrainerQuizCycle(t *testing.T) {
+	trainer, err := NewTrainer("p1", TrainerConfig{Mode: TrainerDrill, QuizEvery: 4, CardsPerDeal: 2})
+	if err != nil {
+		t.Fatalf("new trainer: %v", err)
+	}
+	if _, err := trainer.Answer(0, nil); err != ErrNoQuiz {
+		t.Fatalf("expected ErrNoQuiz before any quiz, got %v", err)
+	}
+
+	running := 0
+	for i := 0; i < 2; i++ {
+		deal, err := trainer.Deal()
+		if err != nil {
+			t.Fatalf("deal %d: %v", i, err)
+		}
+		if len(deal.Cards) != 2 || deal.Seats != nil || deal.Dealer != nil {
+			t.Fatalf("expected a drill batch of 2 cards, got %+v", deal)
+		}
+		for _, card := range deal.Cards {
+			running += CountHiLo.Tag(card)
+		}
+	}
+	if !trainer.Quiz().Pending {
+		t.Fatal("expected a quiz after 4 cards")
+	}
+	if _, err := trainer.Deal(); err != ErrQuizPending {
+		t.Fatalf("expected ErrQuizPending, got %v", err)
+	}
+
+	result, err := trainer.Answer(running, nil)
+	if err != nil {
+		t.Fatalf("answer: %v", err)
+	}
+	if !result.Correct || result.RunningCount != running || result.TrueCount == nil || result.Streak != 1 {
+		t.Errorf("expected a correct answer for count %d, got %+v", running, result)
+	}
+
+	trainer.Deal()
+	trainer.Deal()
+	result, _ = trainer.Answer(result.RunningCount+100, nil)
+	if result.Correct || result.Streak != 0 || result.Accuracy != 0.5 {
+		t.Errorf("expected a wrong answer to reset the streak, got %+v", result)
+	}
+}
+
+func TestTrainerTableRoundAndReshuffle(t *testing.T) {
+	trainer, _ := NewTrainer("p1", TrainerConfig{Decks: 1, Seats: 2, QuizEvery: 52})
+	deal, err := trainer.Deal()
+	if err != nil {
+		t.Fatalf("deal: %v", err)
+	}
+	if len(deal.Seats) != 2 || deal.Dealer == nil || len(deal.Dealer.Cards) < 2 {
+		t.Fatalf("expected two seats and a dealer, got %+v", deal)
+	}
+
+	shuffled := false
+	for i := 0; i < 20 && !shuffled; i++ {
+		deal, _ = trainer.Deal()
+		shuffled = deal.Shuffled
+	}
+	if !shuffled {
+		t.Fatal("expected the single-deck shoe to reach the cut card")
+	}
+	if seen := trainer.Quiz().CardsSeen; seen != len(deal.Cards) {
+		t.Errorf("expected the count to restart at the shuffle, saw %d cards for %d dealt", seen, len(deal.Cards))
+	}
+}
+
+func TestTrainerConfigValidate(t *testing.T) {
+	if _, err := NewTrainer("p1", TrainerConfig{System: "zen"}); err != ErrInvalidTrainerConfig {
+		t.Errorf("expected unknown system to be rejected, got %v", err)
+	}
+	if _, err := NewTrainer("p1", TrainerConfig{Decks: 9}); err != ErrInvalidTrainerConfig {
+		t.Errorf("expected 9 decks to be rejected, got %v", err)
+	}
+	if _, err := NewTrainer("p1", TrainerConfig{Decks: 1, Mode: TrainerDrill, CardsPerDeal: 52}); err != ErrInvalidTrainerConfig {
+		t.Errorf("expected a deal past the cut card to be rejected, got %v", err)
+	}
+	if _, err := NewTrainer("p1", TrainerConfig{Decks: 1, QuizEvery: 53}); err != ErrInvalidTrainerConfig {
+		t.Errorf("expected a quiz interval beyond the shoe to be rejected, got %v", err)
+	}
+}
+
+func TestTrainerDrillNeverEmptiesShoe(t *testing.T) {
+	trainer, err := NewTrainer("p1", TrainerConfig{Decks: 1, Mode: TrainerDrill, CardsPerDeal: 38, QuizEvery: 1})
+	if err != nil {
+		t.Fatalf("expected the largest drill to be valid, got %v", err)
+	}
+	for i := 0; i < 10; i++ {
+		if _, err := trainer.Deal(); err != nil {
+			t.Fatalf("deal %d: %v", i, err)
+		}
+		result, err := trainer.Answer(0, nil)
+		if err != nil {
+			t.Fatalf("answer %d: %v", i, err)
+		}
+		if tc := *result.TrueCount; math.IsNaN(tc) || math.IsInf(tc, 0) {
+			t.Fatalf("expected a finite true count, got %v", tc)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package game
+
+import (
+	"sort"
+	"sync"
+)
+
+// MaxMistakesPerPlayer caps the mistakes kept per player; the cheapest ones are dropped first
+const MaxMistakesPerPlayer = 100
+
+// Accuracy counts decisions against basic strategy
+type Accuracy struct {
+	Decisions  int     `json:"decisions"`
+	Correct    int     `json:"correct"`
+	Rate       float64 `json:"accuracy"`    // Correct / Decisions
+	EVLost     float64 `json:"ev_lost"`     // In units of the bet
+	TokensLost float64 `json:"tokens_lost"` // EV lost times the bet
+}
+
+func (a *Accuracy) add(d Decision) {
+	a.Decisions++
+	if d.Correct() {
+		a.Correct++
+	}
+	a.Rate = float64(a.Correct) / float64(a.Decisions)
+	a.EVLost += d.Cost
+	a.TokensLost += d.Cost * float64(d.Bet)
+}
+
+// Unscored counts decisions left out of the accuracy, and why
+type Unscored struct {
+	Decisions int    `json:"decisions"`
+	Reason    string `json:"reason"`
+}
+
+// PlayerDecisions is the accuracy of a player by hand category and their costliest mistakes
+type PlayerDecisions struct {
+	PlayerID   string                     `json:"player_id"`
+	Overall    Accuracy                   `json:"overall"`
+	ByCategory map[HandCategory]*Accuracy `json:"by_category"`
+	Unscored   map[Variant]*Unscored      `json:"unscored"` // By rule set, see UnscoredReason
+	Mistakes   []Decision                 `json:"-"`        // Costliest first
+}
+
+func (p *PlayerDecisions) add(d Decision) {
+	p.Overall.add(d)
+	if p.ByCategory[d.Category] == nil {
+		p.ByCategory[d.Category] = &Accuracy{}
+	}
+	p.ByCategory[d.Category].add(d)
+
+	if d.Correct() {
+		return
+	}
+	i := sort.Search(len(p.Mistakes), func(i int) bool { return tokenCost(p.Mistakes[i]) < tokenCost(d) })
+	p.Mistakes = append(p.Mistakes, Decision{})
+	copy(p.Mistakes[i+1:], p.Mistakes[i:])
+	p.Mistakes[i] = d
+	if len(p.Mistakes) > MaxMistakesPerPlayer {
+		p.Mistakes = p.Mistakes[:MaxMistakesPerPlayer]
+	}
+}
+
+func (p *PlayerDecisions) clone() PlayerDecisions {
+	c := *p
+	c.ByCategory = make(map[HandCategory]*Accuracy, len(p.ByCategory))
+	for category, acc := range p.ByCategory {
+		a := *acc
+		c.ByCategory[category] = &a
+	}
+	c.Unscored = make(map[Variant]*Unscored, len(p.Unscored))
+	for variant, u := range p.Unscored {
+		unscored := *u
+		c.Unscored[variant] = &unscored
+	}
+	c.Mistakes = append([]Decision(nil), p.Mistakes...)
+	return c
+}
+
+// tokenCost is what a decision cost in tokens
+func tokenCost(d Decision) float64 {
+	return d.Cost * float64(d.Bet)
+}
+
+// DecisionStore keeps every player's accuracy against basic strategy.
+// All methods are safe for concurrent use.
+type DecisionStore struct {
+	mu      sync.RWMutex
+	players map[string]*PlayerDecisions
+}
+
+// NewDecisionStore creates an empty DecisionStore
+func NewDecisionStore() *DecisionStore {
+	return &DecisionStore{players: make(map[string]*PlayerDecisions)}
+}
+
+// Record adds a scored decision
+func (s *DecisionStore) Record(d Decision) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.player(d.PlayerID).add(d)
+}
+
+// RecordUnscored counts a decision made under rules that are not scored
+func (s *DecisionStore) RecordUnscored(playerID string, variant Variant, reason string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p := s.player(playerID)
+	if p.Unscored[variant] == nil {
+		p.Unscored[variant] = &Unscored{}
+	}
+	p.Unscored[variant].Decisions++
+	p.Unscored[variant].Reason = reason
+}
+
+// player returns a player's decisions, creating them if needed; callers hold the lock
+func (s *DecisionStore) player(playerID string) *PlayerDecisions {
+	p, ok := s.players[playerID]
+	if !ok {
+		p = &PlayerDecisions{PlayerID: playerID, ByCategory: make(map[HandCategory]*Accuracy), Unscored: make(map[Variant]*Unscored)}
+		s.players[playerID] = p
+	}
+	return p
+}
+
+// Get returns a copy of a player's accuracy
+func (s *DecisionStore) Get(playerID string) (PlayerDecisions, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	p, ok := s.players[playerID]
+	if !ok {
+		return PlayerDecisions{}, false
+	}
+	return p.clone(), true
+}
+
+// Mistakes returns up to limit of a player's costliest mistakes
+func (s *DecisionStore) Mistakes(playerID string, limit int) []Decision {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	p, ok := s.players[playerID]
+	if !ok {
+		return []Decision{}
+	}
+	return append([]Decision{}, p.Mistakes[:min(limit, len(p.Mistakes))]...)
+}
+
+// All returns a copy of every player's accuracy, for snapshots
+func (s *DecisionStore) All() []PlayerDecisions {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	all := make([]PlayerDecisions, 0, len(s.players))
+	for _, p := range s.players {
+		all = append(all, p.clone())
+	}
+	return all
+}
+
+// Restore stores a player's previously saved accuracy
+func (s *DecisionStore) Restore(p PlayerDecisions) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if p.ByCategory == nil {
+		p.ByCategory = make(map[HandCategory]*Accuracy)
+	}
+	if p.Unscored == nil {
+		p.Unscored = make(map[Variant]*Unscored)
+	}
+	s.players[p.PlayerID] = &p
+}
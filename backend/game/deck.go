@@ -26,6 +26,26 @@ func NewDeck() []Card {
 	return deck
 }
 
+// NewSpanishDeck creates a 48-card Spanish deck: a standard deck without the pip 10s
+func NewSpanishDeck() []Card {
+	var deck []Card
+	for _, card := range NewDeck() {
+		if card.Rank != Ten {
+			deck = append(deck, card)
+		}
+	}
+	return deck
+}
+
+// NewDecks creates n standard decks, unshuffled, for a multi-deck shoe
+func NewDecks(n int) []Card {
+	var cards []Card
+	for i := 0; i < n; i++ {
+		cards = append(cards, NewDeck()...)
+	}
+	return cards
+}
+
 // Shuffle randomizes the order of cards in the deck
 func Shuffle(deck []Card) []Card {
 	shuffled := make([]Card, len(deck))
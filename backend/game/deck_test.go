@@ -21,6 +21,18 @@ func TestNewDeck(t *testing.T) {
 	}
 }
 
+func TestNewSpanishDeck(t *testing.T) {
+	deck := NewSpanishDeck()
+	if len(deck) != 48 {
+		t.Errorf("Expected Spanish deck length of 48, got %d", len(deck))
+	}
+	for _, card := range deck {
+		if card.Rank == Ten {
+			t.Errorf("Expected no pip 10s, found %v", card)
+		}
+	}
+}
+
 func TestShuffle(t *testing.T) {
 	deck := NewDeck()
 	shuffled := Shuffle(deck)
@@ -42,6 +42,19 @@ func CalculateScore(hand []Card) int {
 	return score
 }
 
+// IsSoft reports whether a hand counts an Ace as 11
+func IsSoft(cards []Card) bool {
+	hard := 0
+	for _, card := range cards {
+		if card.Rank == Ace {
+			hard++
+		} else {
+			hard += CalculateScore([]Card{card})
+		}
+	}
+	return hard != CalculateScore(cards)
+}
+
 // IsBust checks if a score is over 21
 func IsBust(score int) bool {
 	return score > 21
@@ -135,3 +148,31 @@ func DealCard(deck *[]Card) Card {
 	*deck = (*deck)[1:]
 	return card
 }
+
+// IsBlackjack reports whether a hand is a natural 21 (two cards)
+func IsBlackjack(hand Hand) bool {
+	return len(hand.Cards) == 2 && hand.Score == 21
+}
+
+// BlackjackPayout returns what a natural pays back: the bet plus 3:2
+func BlackjackPayout(bet int) int {
+	return int(float64(bet) * 2.5)
+}
+
+// Payout returns what a finished hand pays back against the dealer, including the returned bet.
+// Win: Bet * 2. Push: Bet. Loss or bust: 0.
+func Payout(hand Hand, dealer Hand, bet int) int {
+	if IsBust(hand.Score) {
+		return 0 // Lost
+	}
+	if IsBust(dealer.Score) {
+		return bet * 2
+	}
+	if hand.Score > dealer.Score {
+		return bet * 2
+	}
+	if hand.Score == dealer.Score {
+		return bet // Push
+	}
+	return 0
+}
@@ -0,0 +1,264 @@
+package game
+
+import "time"
+
+// EventType identifies what happened in a game
+type EventType string
+
+const (
+	EventBetPlaced      EventType = "bet_placed"
+	EventDeckShuffled   EventType = "deck_shuffled"
+	EventCardDealt      EventType = "card_dealt"
+	EventActionTaken    EventType = "action_taken"
+	EventHandAdvanced   EventType = "hand_advanced"    // Player moved on to the next hand
+	EventHoleRevealed   EventType = "hole_revealed"    // Dealer's face-down card turned over
+	EventDealerDraw     EventType = "dealer_draw"      // Dealer drew during the dealer turn
+	EventSideBetSettled EventType = "side_bet_settled" // Results of the side bets resolved at once
+	EventSettled        EventType = "settled"          // Final result and payout
+)
+
+// Seat identifies who receives a card
+type Seat string
+
+const (
+	SeatPlayer Seat = "player"
+	SeatDealer Seat = "dealer"
+)
+
+// Event is a single entry in a game's event log.
+// Applying a game's events in order rebuilds its GameState (see Replay).
+type Event struct {
+	Seq        int             `json:"seq"`
+	Type       EventType       `json:"type"`
+	Time       time.Time       `json:"time"`
+	GameID     string          `json:"game_id,omitempty"`
+	PlayerID   string          `json:"player_id,omitempty"`
+	Tournament string          `json:"tournament_id,omitempty"` // Set on bet_placed for tournament games
+	Seat       Seat            `json:"seat,omitempty"`
+	HandIndex  int             `json:"hand_index"` // Index in GameState.Hands
+	Card       *Card           `json:"card,omitempty"`
+	FaceUp     bool            `json:"face_up"`
+	Action     string          `json:"action,omitempty"`
+	Amount     int             `json:"amount,omitempty"` // Bet placed, or total payout when settled
+	Status     GameStatus      `json:"status,omitempty"`
+	SideBets   []SideBetResult `json:"side_bets,omitempty"` // Placed with the bet, updated as they settle
+	Rules      *Rules          `json:"rules,omitempty"`     // Set on bet_placed; classic if missing
+	Limits     *TableProfile   `json:"limits,omitempty"`    // Set on bet_placed for cash games; no limits if missing
+	Free       bool            `json:"free,omitempty"`      // Split or double funded by the house
+	Deck       []Card          `json:"-"`                   // Shuffled deck, never exposed
+}
+
+// Record stamps an event, applies it to the game and appends it to the log
+func (g *GameState) Record(e Event) Event {
+	e.Seq = len(g.Events) + 1
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	g.apply(e)
+	g.Events = append(g.Events, e)
+	return e
+}
+
+// Deal takes the top card of the deck and gives it to a hand
+func (g *GameState) Deal(seat Seat, handIndex int, faceUp bool) Card {
+	card := g.topCard()
+	g.Record(Event{Type: EventCardDealt, Seat: seat, HandIndex: handIndex, Card: &card, FaceUp: faceUp})
+	return card
+}
+
+// DealerDraw takes the top card of the deck for the dealer during the dealer turn
+func (g *GameState) DealerDraw() Card {
+	card := g.topCard()
+	g.Record(Event{Type: EventDealerDraw, Seat: SeatDealer, Card: &card, FaceUp: true})
+	return card
+}
+
+// RevealHole turns over the dealer's face-down cards, if they are still hidden
+func (g *GameState) RevealHole() {
+	if g.HoleRevealed() {
+		return
+	}
+	for _, e := range g.Events {
+		if e.Type == EventCardDealt && e.Seat == SeatDealer && !e.FaceUp {
+			card := *e.Card
+			g.Record(Event{Type: EventHoleRevealed, Seat: SeatDealer, Card: &card, FaceUp: true})
+		}
+	}
+}
+
+// HoleRevealed reports whether the dealer's face-down card has been turned over
+func (g *GameState) HoleRevealed() bool {
+	for _, e := range g.Events {
+		if e.Type == EventHoleRevealed {
+			return true
+		}
+	}
+	return false
+}
+
+// HoleHidden reports whether the dealer has a face-down card that is not turned over yet
+func (g *GameState) HoleHidden() bool {
+	for _, e := range g.Events {
+		if e.Type == EventCardDealt && e.Seat == SeatDealer && !e.FaceUp {
+			return !g.HoleRevealed()
+		}
+	}
+	return false
+}
+
+// HasTwisted reports whether a player hand has drawn a card without buying it
+func (g *GameState) HasTwisted(handIndex int) bool {
+	for _, e := range g.Events {
+		if e.Type == EventActionTaken && e.Action == "hit" && e.HandIndex == handIndex {
+			return true
+		}
+	}
+	return false
+}
+
+// HasActed reports whether the player has taken any action yet
+func (g *GameState) HasActed() bool {
+	for _, e := range g.Events {
+		if e.Type == EventActionTaken {
+			return true
+		}
+	}
+	return false
+}
+
+// Settle reveals the dealer's hand and records the final result
+func (g *GameState) Settle(status GameStatus, payout int) {
+	g.RevealHole()
+	g.Record(Event{Type: EventSettled, Status: status, Amount: payout})
+}
+
+// Replay rebuilds a game by applying its events in order
+func Replay(events []Event) *GameState {
+	g := &GameState{}
+	for _, e := range events {
+		g.apply(e)
+		g.Events = append(g.Events, e)
+	}
+	if len(events) > 0 {
+		g.CreatedAt = events[0].Time
+		g.UpdatedAt = events[len(events)-1].Time
+	}
+	return g
+}
+
+// apply mutates the game state according to a single event
+func (g *GameState) apply(e Event) {
+	switch e.Type {
+	case EventBetPlaced:
+		g.ID = e.GameID
+		g.PlayerID = e.PlayerID
+		g.TournamentID = e.Tournament
+		g.BetAmount = e.Amount
+		g.SideBets = append([]SideBetResult(nil), e.SideBets...)
+		g.Rules = DefaultRules
+		if e.Rules != nil {
+			g.Rules = *e.Rules
+		}
+		if e.Limits != nil {
+			g.Limits = *e.Limits
+		}
+		g.Hands = make([]Hand, g.Rules.StartingHands())
+		for i := range g.Hands {
+			g.Hands[i].Cards = []Card{}
+		}
+		g.CurrentHandIndex = 0
+		g.DealerHand = Hand{Cards: []Card{}}
+		g.Status = StatusPlayerTurn
+	case EventDeckShuffled:
+		g.Deck = append([]Card(nil), e.Deck...)
+	case EventCardDealt, EventDealerDraw:
+		hand := g.handFor(e.Seat, e.HandIndex)
+		hand.Cards = append(hand.Cards, *e.Card)
+		hand.Score = CalculateScore(hand.Cards)
+		if len(g.Deck) > 0 {
+			g.Deck = g.Deck[1:]
+		}
+	case EventActionTaken:
+		if e.Action == "split" {
+			// Move the second card to a new hand right after it; each hand is dealt its second card next
+			hand := g.Hands[e.HandIndex]
+			first := Hand{Cards: []Card{hand.Cards[0]}, Split: true, FreeStake: hand.FreeStake}
+			second := Hand{Cards: []Card{hand.Cards[1]}, Split: true, FreeStake: e.Free}
+			first.Score = CalculateScore(first.Cards)
+			second.Score = CalculateScore(second.Cards)
+			hands := append([]Hand(nil), g.Hands[:e.HandIndex]...)
+			hands = append(hands, first, second)
+			g.Hands = append(hands, g.Hands[e.HandIndex+1:]...)
+			g.CurrentHandIndex = e.HandIndex
+		}
+		if e.Action == "switch" {
+			// Swap the second cards of the two hands
+			a, b := &g.Hands[0], &g.Hands[1]
+			a.Cards[1], b.Cards[1] = b.Cards[1], a.Cards[1]
+			a.Score = CalculateScore(a.Cards)
+			b.Score = CalculateScore(b.Cards)
+			a.Switched, b.Switched = true, true
+		}
+		if e.Action == "double" {
+			hand := g.handFor(SeatPlayer, e.HandIndex)
+			hand.Doubled = true
+			hand.FreeDouble = e.Free
+		}
+		if e.Action == "buy" {
+			g.handFor(SeatPlayer, e.HandIndex).Bought += e.Amount
+		}
+		if e.Action == "surrender" || e.Action == "rescue" {
+			g.handFor(SeatPlayer, e.HandIndex).Surrendered = true
+		}
+	case EventHandAdvanced:
+		g.CurrentHandIndex++
+	case EventHoleRevealed:
+		if !g.IsFinished() {
+			g.Status = StatusDealerTurn
+		}
+	case EventSideBetSettled:
+		g.SideBets = append([]SideBetResult(nil), e.SideBets...)
+	case EventSettled:
+		g.Status = e.Status
+	}
+}
+
+// handFor returns the hand a card event targets
+func (g *GameState) handFor(seat Seat, handIndex int) *Hand {
+	if seat == SeatDealer {
+		return &g.DealerHand
+	}
+	if handIndex > 0 && handIndex < len(g.Hands) {
+		return &g.Hands[handIndex]
+	}
+	return &g.Hands[0]
+}
+
+// topCard returns the card that the next deal will take
+func (g *GameState) topCard() Card {
+	if len(g.Deck) == 0 {
+		return Card{}
+	}
+	return g.Deck[0]
+}
+
+// Steps replays events and returns a copy of the state after each one.
+// The deck is left out of the copies and each copy only carries the events up to that point.
+func Steps(events []Event) []*GameState {
+	g := &GameState{}
+	steps := make([]*GameState, 0, len(events))
+	for i, e := range events {
+		g.apply(e)
+		step := g.Copy()
+		step.Deck = nil
+		step.Events = events[:i+1]
+		steps = append(steps, step)
+	}
+	return steps
+}
+
+// clone copies a hand so later deals don't change it
+func (h Hand) clone() Hand {
+	h.Cards = append([]Card(nil), h.Cards...)
+	return h
+}
@@ -0,0 +1,132 @@
+package game
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReplayRebuildsGameState(t *testing.T) {
+	// Eights for a split, then small cards so no hand busts
+	deck := []Card{
+		{Suit: Hearts, Rank: Eight}, {Suit: Spades, Rank: Eight},
+		{Suit: Clubs, Rank: Ten}, {Suit: Diamonds, Rank: Six},
+		{Suit: Hearts, Rank: Two}, {Suit: Clubs, Rank: Three},
+		{Suit: Spades, Rank: Ten},
+	}
+
+	g := &GameState{}
+	g.Record(Event{Type: EventBetPlaced, GameID: "g1", PlayerID: "p1", Amount: 10})
+	g.Record(Event{Type: EventDeckShuffled, Deck: deck})
+	g.Deal(SeatPlayer, 0, true)
+	g.Deal(SeatPlayer, 0, true)
+	g.Deal(SeatDealer, 0, true)
+	g.Deal(SeatDealer, 0, false)
+	g.Record(Event{Type: EventActionTaken, Action: "split"})
+	g.Deal(SeatPlayer, 0, true)
+	g.Deal(SeatPlayer, 1, true)
+	g.Record(Event{Type: EventActionTaken, Action: "stand"})
+	g.Record(Event{Type: EventHandAdvanced})
+	g.RevealHole()
+	g.DealerDraw()
+	g.Settle(StatusPush, 20)
+
+	if len(g.Hands) != 2 || g.Hands[0].Score != 10 || g.Hands[1].Score != 11 || !g.Hands[1].Split {
+		t.Fatalf("unexpected hands after split: %+v", g.Hands)
+	}
+	if g.DealerHand.Score != 26 || len(g.Deck) != 0 {
+		t.Fatalf("expected dealer to draw the last card, got %+v with %d cards left", g.DealerHand, len(g.Deck))
+	}
+
+	replayed := Replay(g.Events)
+	g.CreatedAt, g.UpdatedAt = replayed.CreatedAt, replayed.UpdatedAt
+	if !reflect.DeepEqual(g, replayed) {
+		t.Errorf("replayed state differs:\n got  %+v\n want %+v", replayed, g)
+	}
+}
+
+func TestRevealHoleOnlyOnce(t *testing.T) {
+	g := &GameState{}
+	g.Record(Event{Type: EventBetPlaced, GameID: "g1", PlayerID: "p1", Amount: 10})
+	g.Record(Event{Type: EventDeckShuffled, Deck: NewDeck()})
+	g.Deal(SeatDealer, 0, true)
+	g.Deal(SeatDealer, 0, false)
+
+	g.RevealHole()
+	if g.Status != StatusDealerTurn {
+		t.Errorf("expected reveal to start the dealer turn, got %s", g.Status)
+	}
+	g.Settle(StatusDealerWon, 0)
+
+	reveals := 0
+	for _, e := range g.Events {
+		if e.Type == EventHoleRevealed {
+			reveals++
+		}
+	}
+	if reveals != 1 {
+		t.Errorf("expected exactly one reveal, got %d", reveals)
+	}
+}
+
+func TestSwitchHands(t *testing.T) {
+	rules := SwitchRules
+	g := &GameState{}
+	g.Record(Event{Type: EventBetPlaced, GameID: "g1", PlayerID: "p1", Amount: 10, Rules: &rules})
+	g.Record(Event{Type: EventDeckShuffled, Deck: []Card{
+		{Suit: Hearts, Rank: Eight}, {Suit: Spades, Rank: Ace}, {Suit: Clubs, Rank: King}, {Suit: Clubs, Rank: Eight},
+		{Suit: Diamonds, Rank: Two}, {Suit: Diamonds, Rank: Three}, {Suit: Hearts, Rank: Nine}, {Suit: Hearts, Rank: Seven},
+	}})
+	g.Deal(SeatPlayer, 0, true)
+	g.Deal(SeatPlayer, 0, true)
+	g.Deal(SeatPlayer, 1, true)
+	g.Deal(SeatPlayer, 1, true)
+	g.Deal(SeatDealer, 0, true)
+	g.Deal(SeatDealer, 0, false)
+
+	if len(g.Hands) != 2 || g.Stake() != 20 {
+		t.Fatalf("expected two hands staking 20, got %+v", g.Hands)
+	}
+	g.Record(Event{Type: EventActionTaken, Action: "switch"})
+	if g.Hands[0].Cards[1].Rank != Eight || g.Hands[1].Cards[1].Rank != Ace || g.Hands[1].Score != 21 {
+		t.Fatalf("expected second cards swapped, got %+v", g.Hands)
+	}
+	if !g.Hands[0].Switched || !g.Hands[1].Switched {
+		t.Fatalf("expected both hands marked switched, got %+v", g.Hands)
+	}
+
+	// Splitting the first hand inserts the new hand before the second one
+	g.Record(Event{Type: EventActionTaken, Action: "split", HandIndex: 0})
+	g.Deal(SeatPlayer, 0, true)
+	g.Deal(SeatPlayer, 1, true)
+	if len(g.Hands) != 3 || !g.Hands[1].Split || g.Hands[2].Split {
+		t.Fatalf("expected the split hand in second place, got %+v", g.Hands)
+	}
+	if g.Hands[0].Score != 17 || g.Hands[1].Score != 15 || g.Hands[2].Score != 21 {
+		t.Errorf("expected scores 17, 15 and 21, got %+v", g.Hands)
+	}
+}
+
+func TestDealer22Push(t *testing.T) {
+	dealer := Hand{Cards: []Card{{Rank: King}, {Rank: Six}, {Rank: Six}}, Score: 22}
+	hand := Hand{Cards: []Card{{Rank: King}, {Rank: Nine}}, Score: 19}
+	natural := Hand{Cards: []Card{{Rank: King}, {Rank: Ace}}, Score: 21}
+	if got := SwitchRules.HandPayout(hand, dealer, 10); got != 10 {
+		t.Errorf("expected a dealer 22 to push, got %d", got)
+	}
+	if got := SwitchRules.HandPayout(natural, dealer, 10); got != 20 {
+		t.Errorf("expected blackjack to win 1:1 against a dealer 22, got %d", got)
+	}
+	if got := DefaultRules.HandPayout(hand, dealer, 10); got != 20 {
+		t.Errorf("expected a dealer 22 to bust under classic rules, got %d", got)
+	}
+
+	// A 21 made by switching is not a blackjack, so a dealer 22 pushes it too
+	switched := natural
+	switched.Switched = true
+	if got := SwitchRules.HandPayout(switched, dealer, 10); got != 10 {
+		t.Errorf("expected a switched 21 to push against a dealer 22, got %d", got)
+	}
+	if got := SwitchRules.HandPayout(switched, Hand{Cards: []Card{{Rank: King}, {Rank: Nine}}, Score: 19}, 10); got != 20 {
+		t.Errorf("expected a switched 21 to win 1:1, got %d", got)
+	}
+}
@@ -0,0 +1,74 @@
+package game
+
+import (
+	"sync"
+)
+
+// MaxHistoryPerPlayer bounds how many finished games are kept per player
+const MaxHistoryPerPlayer = 100
+
+// HistoryStore is a thread-safe in-memory store for finished games
+type HistoryStore struct {
+	mu       sync.RWMutex
+	games    map[string]*GameState
+	byPlayer map[string][]string // Game IDs per player, oldest first
+}
+
+// NewHistoryStore creates a new HistoryStore
+func NewHistoryStore() *HistoryStore {
+	return &HistoryStore{
+		games:    make(map[string]*GameState),
+		byPlayer: make(map[string][]string),
+	}
+}
+
+// Save archives a finished game, dropping the player's oldest game if needed
+func (s *HistoryStore) Save(game *GameState) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.games[game.ID]; exists {
+		s.games[game.ID] = game
+		return
+	}
+	s.games[game.ID] = game
+
+	ids := append(s.byPlayer[game.PlayerID], game.ID)
+	if len(ids) > MaxHistoryPerPlayer {
+		delete(s.games, ids[0])
+		ids = ids[1:]
+	}
+	s.byPlayer[game.PlayerID] = ids
+}
+
+// Get retrieves an archived game by ID
+func (s *HistoryStore) Get(id string) (*GameState, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	game, exists := s.games[id]
+	return game, exists
+}
+
+// ByPlayer returns a player's archived games, newest first
+func (s *HistoryStore) ByPlayer(playerID string) []*GameState {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	ids := s.byPlayer[playerID]
+	games := make([]*GameState, 0, len(ids))
+	for i := len(ids) - 1; i >= 0; i-- {
+		games = append(games, s.games[ids[i]])
+	}
+	return games
+}
+
+// All returns every archived game, oldest first per player
+func (s *HistoryStore) All() []*GameState {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	games := make([]*GameState, 0, len(s.games))
+	for _, ids := range s.byPlayer {
+		for _, id := range ids {
+			games = append(games, s.games[id])
+		}
+	}
+	return games
+}
@@ -0,0 +1,137 @@
+package game
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+// ExpiryPolicy decides what happens to the bet held by an abandoned game
+type ExpiryPolicy string
+
+const (
+	ExpiryRefund  ExpiryPolicy = "refund"  // Return the stake to the player
+	ExpiryForfeit ExpiryPolicy = "forfeit" // The house keeps the stake
+)
+
+// JanitorConfig configures the background cleanup of the GameStore
+type JanitorConfig struct {
+	Interval    time.Duration // How often to sweep
+	IdleTimeout time.Duration // Unfinished games idle for longer are expired
+	Policy      ExpiryPolicy
+}
+
+// Janitor moves finished games to history and expires abandoned ones
+type Janitor struct {
+	games   *GameStore
+	history *HistoryStore
+	wallets Wallets
+	config  JanitorConfig
+
+	// OnExpire, if set, is called for every game expired, still under the game's lock,
+	// with the index of the first event the expiry recorded
+	OnExpire func(g *GameState, from int)
+
+	stop    chan struct{}
+	done    chan struct{}
+	once    sync.Once
+	started bool
+}
+
+// NewJanitor creates a Janitor for the given stores
+func NewJanitor(games *GameStore, history *HistoryStore, wallets Wallets, config JanitorConfig) *Janitor {
+	return &Janitor{
+		games:   games,
+		history: history,
+		wallets: wallets,
+		config:  config,
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+}
+
+// Sweep archives finished games and expires games idle since before now-IdleTimeout.
+// It returns how many games were archived and how many of those were expired.
+func (j *Janitor) Sweep(now time.Time) (archived, expired int) {
+	for _, g := range j.games.All() {
+		archive, expire := j.sweep(g, now)
+		if archive {
+			archived++
+		}
+		if expire {
+			expired++
+		}
+	}
+	return archived, expired
+}
+
+// sweep archives or expires one game. It holds the game's lock, so an action in flight
+// either finishes first or finds the game over, and the stake is never paid out twice.
+func (j *Janitor) sweep(g *GameState, now time.Time) (archived, expired bool) {
+	g.Lock()
+	defer g.Unlock()
+	if current, ok := j.games.Get(g.ID); !ok || current != g {
+		return false, false // Archived meanwhile
+	}
+	if !g.IsFinished() {
+		if now.Sub(g.UpdatedAt) < j.config.IdleTimeout {
+			return false, false
+		}
+		j.expire(g)
+		expired = true
+	}
+	j.history.Save(g)
+	j.games.Delete(g.ID)
+	return true, expired
+}
+
+// expire closes an abandoned game and applies the policy to its held stake
+func (j *Janitor) expire(g *GameState) {
+	from := len(g.Events)
+	wallet := j.wallets.Of(g)
+	refund := 0
+	if j.config.Policy == ExpiryRefund {
+		// Side bets still waiting on the dealer are returned too
+		stake := g.Stake() + g.OpenSideBetStake()
+		if err := wallet.Credit(TxRefund, g.ID, stake, "expired game"); err != nil {
+			log.Printf("janitor: refund of game %s failed: %v", g.ID, err)
+		} else {
+			refund = stake
+		}
+	}
+	g.Settle(StatusExpired, refund)
+	wallet.Settled(g.ID)
+	if j.OnExpire != nil {
+		j.OnExpire(g, from)
+	}
+}
+
+// Start runs the sweep loop in the background
+func (j *Janitor) Start() {
+	j.started = true
+	go func() {
+		defer close(j.done)
+		ticker := time.NewTicker(j.config.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case now := <-ticker.C:
+				if archived, expired := j.Sweep(now); archived > 0 {
+					log.Printf("janitor: archived %d games (%d expired)", archived, expired)
+				}
+			case <-j.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop ends the sweep loop and waits for it to exit
+func (j *Janitor) Stop() {
+	j.once.Do(func() {
+		close(j.stop)
+		if j.started {
+			<-j.done
+		}
+	})
+}
@@ -0,0 +1,94 @@
+package game
+
+import (
+	"testing"
+	"time"
+)
+
+func TestJanitorSweep(t *testing.T) {
+	tests := []struct {
+		name            string
+		policy          ExpiryPolicy
+		expectedBalance int
+	}{
+		{"Refund", ExpiryRefund, 100},
+		{"Forfeit", ExpiryForfeit, 90},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			games := NewGameStore()
+			players := NewPlayerStore()
+			history := NewHistoryStore()
+			players.Save(&Player{ID: "p1", Balance: 90})
+
+			now := time.Now()
+			games.Restore(&GameState{ID: "idle", PlayerID: "p1", BetAmount: 10, Hands: []Hand{{}}, Status: StatusPlayerTurn, UpdatedAt: now.Add(-time.Hour)})
+			games.Restore(&GameState{ID: "active", PlayerID: "p1", BetAmount: 10, Status: StatusPlayerTurn, UpdatedAt: now})
+			games.Restore(&GameState{ID: "done", PlayerID: "p1", BetAmount: 10, Status: StatusPlayerWon, UpdatedAt: now})
+
+			janitor := NewJanitor(games, history, Wallets{Ledger: NewLedger(players)}, JanitorConfig{IdleTimeout: 30 * time.Minute, Policy: tt.policy})
+			var expiredEvents []Event
+			janitor.OnExpire = func(g *GameState, from int) { expiredEvents = append(expiredEvents, g.Events[from:]...) }
+			archived, expired := janitor.Sweep(now)
+			if archived != 2 || expired != 1 {
+				t.Errorf("expected 2 archived and 1 expired, got %d and %d", archived, expired)
+			}
+			if len(expiredEvents) != 1 || expiredEvents[0].Type != EventSettled {
+				t.Errorf("expected OnExpire with the settled event of the idle game, got %+v", expiredEvents)
+			}
+
+			if _, ok := games.Get("active"); !ok {
+				t.Error("expected active game to stay in the store")
+			}
+			if g, ok := history.Get("idle"); !ok || g.Status != StatusExpired {
+				t.Errorf("expected idle game to be archived as expired, got %+v", g)
+			}
+			if _, ok := history.Get("done"); !ok {
+				t.Error("expected finished game to be archived")
+			}
+			if p, _ := players.Get("p1"); p.Balance != tt.expectedBalance {
+				t.Errorf("expected balance %d, got %d", tt.expectedBalance, p.Balance)
+			}
+		})
+	}
+}
+
+func TestJanitorWaitsForActionInFlight(t *testing.T) {
+	games := NewGameStore()
+	players := NewPlayerStore()
+	players.Save(&Player{ID: "p1", Balance: 90})
+	ledger := NewLedger(players)
+	now := time.Now()
+	g := &GameState{ID: "idle", PlayerID: "p1", BetAmount: 10, Hands: []Hand{{}}, Status: StatusPlayerTurn, UpdatedAt: now.Add(-time.Hour)}
+	games.Restore(g)
+
+	// An action holding the game wins it while the janitor waits
+	g.Lock()
+	done := make(chan struct{})
+	janitor := NewJanitor(games, NewHistoryStore(), Wallets{Ledger: ledger}, JanitorConfig{IdleTimeout: 30 * time.Minute, Policy: ExpiryRefund})
+	go func() {
+		janitor.Sweep(now)
+		close(done)
+	}()
+	// The sweep must not get past the game while the action holds it
+	select {
+	case <-done:
+		t.Fatal("expected the sweep to wait for the game lock")
+	case <-time.After(50 * time.Millisecond):
+	}
+	if g.Status != StatusPlayerTurn {
+		t.Fatalf("expected the game to be untouched while locked, got %s", g.Status)
+	}
+	ledger.Credit("p1", TxPayout, "idle", 20, "win")
+	g.Settle(StatusPlayerWon, 20)
+	g.Unlock()
+	<-done
+
+	if g.Status != StatusPlayerWon {
+		t.Errorf("expected the game to stay won, got %s", g.Status)
+	}
+	if p, _ := players.Get("p1"); p.Balance != 110 {
+		t.Errorf("expected balance 110 without a refund, got %d", p.Balance)
+	}
+}
@@ -0,0 +1,243 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// LeaderboardMetric is what players are ranked by
+type LeaderboardMetric string
+
+const (
+	MetricNet        LeaderboardMetric = "net"         // Net winnings
+	MetricBiggestWin LeaderboardMetric = "biggest_win" // Largest net win in a single game
+	MetricWinRate    LeaderboardMetric = "win_rate"    // Share of games won
+	MetricBlackjacks LeaderboardMetric = "blackjacks"  // Naturals dealt
+)
+
+// LeaderboardWindow is the period a leaderboard covers
+type LeaderboardWindow string
+
+const (
+	WindowDaily   LeaderboardWindow = "daily"    // Current UTC day
+	WindowWeekly  LeaderboardWindow = "weekly"   // Current ISO week
+	WindowAllTime LeaderboardWindow = "all-time" // Since the first game
+)
+
+// MinGamesForWinRate keeps players with a handful of lucky games off the win rate leaderboard
+const MinGamesForWinRate = 10
+
+var (
+	ErrUnknownMetric = errors.New("unknown leaderboard metric")
+	ErrUnknownWindow = errors.New("unknown leaderboard window")
+)
+
+// PlayerAggregate accumulates a player's settlements over a window
+type PlayerAggregate struct {
+	PlayerID   string `json:"player_id"`
+	Games      int    `json:"games"`
+	Wins       int    `json:"wins"`
+	Net        int    `json:"net"`
+	BiggestWin int    `json:"biggest_win"`
+	Blackjacks int    `json:"blackjacks"`
+}
+
+// add counts one settlement
+func (a *PlayerAggregate) add(s Settlement) {
+	a.Games++
+	if s.Won() {
+		a.Wins++
+	}
+	a.Net += s.Net()
+	if s.Net() > a.BiggestWin {
+		a.BiggestWin = s.Net()
+	}
+	if s.Blackjack {
+		a.Blackjacks++
+	}
+}
+
+// value returns the aggregate's score for a metric and whether it qualifies
+func (a PlayerAggregate) value(metric LeaderboardMetric) (float64, bool) {
+	switch metric {
+	case MetricNet:
+		return float64(a.Net), true
+	case MetricBiggestWin:
+		return float64(a.BiggestWin), a.BiggestWin > 0
+	case MetricWinRate:
+		if a.Games < MinGamesForWinRate {
+			return 0, false
+		}
+		return float64(a.Wins) / float64(a.Games), true
+	case MetricBlackjacks:
+		return float64(a.Blackjacks), a.Blackjacks > 0
+	}
+	return 0, false
+}
+
+// LeaderboardEntry is a ranked player
+type LeaderboardEntry struct {
+	Rank     int     `json:"rank"` // Tied players share a rank
+	PlayerID string  `json:"player_id"`
+	Value    float64 `json:"value"`
+	Games    int     `json:"games"`
+}
+
+// LeaderboardState is the serializable state of the leaderboards.
+// Only the current day and week are kept; older ones are dropped when a new one starts.
+type LeaderboardState struct {
+	Day     string
+	Week    string
+	Daily   map[string]PlayerAggregate
+	Weekly  map[string]PlayerAggregate
+	AllTime map[string]PlayerAggregate
+}
+
+// Leaderboards ranks players from their settlements.
+// All methods are safe for concurrent use.
+type Leaderboards struct {
+	mu    sync.RWMutex
+	state LeaderboardState
+}
+
+// NewLeaderboards creates empty leaderboards
+func NewLeaderboards() *Leaderboards {
+	return &Leaderboards{state: LeaderboardState{
+		Daily:   make(map[string]PlayerAggregate),
+		Weekly:  make(map[string]PlayerAggregate),
+		AllTime: make(map[string]PlayerAggregate),
+	}}
+}
+
+// Record adds a settlement to every window it falls in.
+// A settlement from before the current day or week (e.g. recorded late) only counts all-time.
+func (l *Leaderboards) Record(s Settlement) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.roll(s.Time)
+	windows := []map[string]PlayerAggregate{l.state.AllTime}
+	if dayKey(s.Time) == l.state.Day {
+		windows = append(windows, l.state.Daily)
+	}
+	if weekKey(s.Time) == l.state.Week {
+		windows = append(windows, l.state.Weekly)
+	}
+	for _, window := range windows {
+		agg := window[s.PlayerID]
+		agg.PlayerID = s.PlayerID
+		agg.add(s)
+		window[s.PlayerID] = agg
+	}
+}
+
+// Rank returns every qualifying player for a metric and window, best first
+func (l *Leaderboards) Rank(metric LeaderboardMetric, window LeaderboardWindow, now time.Time) ([]LeaderboardEntry, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	var aggregates map[string]PlayerAggregate
+	switch window {
+	case WindowDaily:
+		if l.state.Day == dayKey(now) {
+			aggregates = l.state.Daily
+		}
+	case WindowWeekly:
+		if l.state.Week == weekKey(now) {
+			aggregates = l.state.Weekly
+		}
+	case WindowAllTime:
+		aggregates = l.state.AllTime
+	default:
+		return nil, ErrUnknownWindow
+	}
+	if !knownMetric(metric) {
+		return nil, ErrUnknownMetric
+	}
+
+	entries := []LeaderboardEntry{}
+	for _, agg := range aggregates {
+		if value, ok := agg.value(metric); ok {
+			entries = append(entries, LeaderboardEntry{PlayerID: agg.PlayerID, Value: value, Games: agg.Games})
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Value != entries[j].Value {
+			return entries[i].Value > entries[j].Value
+		}
+		return entries[i].PlayerID < entries[j].PlayerID
+	})
+	for i := range entries {
+		entries[i].Rank = i + 1
+		if i > 0 && entries[i].Value == entries[i-1].Value {
+			entries[i].Rank = entries[i-1].Rank
+		}
+	}
+	return entries, nil
+}
+
+// State returns a copy of the leaderboards for snapshots
+func (l *Leaderboards) State() LeaderboardState {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return LeaderboardState{
+		Day:     l.state.Day,
+		Week:    l.state.Week,
+		Daily:   copyAggregates(l.state.Daily),
+		Weekly:  copyAggregates(l.state.Weekly),
+		AllTime: copyAggregates(l.state.AllTime),
+	}
+}
+
+// Restore replaces the leaderboards with saved state
+func (l *Leaderboards) Restore(state LeaderboardState) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.state = LeaderboardState{
+		Day:     state.Day,
+		Week:    state.Week,
+		Daily:   copyAggregates(state.Daily),
+		Weekly:  copyAggregates(state.Weekly),
+		AllTime: copyAggregates(state.AllTime),
+	}
+}
+
+// roll starts a new day or week if t is past the current one; callers hold the lock.
+// It never goes back, and the keys sort in time order.
+func (l *Leaderboards) roll(t time.Time) {
+	if day := dayKey(t); day > l.state.Day {
+		l.state.Day = day
+		l.state.Daily = make(map[string]PlayerAggregate)
+	}
+	if week := weekKey(t); week > l.state.Week {
+		l.state.Week = week
+		l.state.Weekly = make(map[string]PlayerAggregate)
+	}
+}
+
+func knownMetric(metric LeaderboardMetric) bool {
+	switch metric {
+	case MetricNet, MetricBiggestWin, MetricWinRate, MetricBlackjacks:
+		return true
+	}
+	return false
+}
+
+func dayKey(t time.Time) string {
+	return t.UTC().Format(time.DateOnly)
+}
+
+func weekKey(t time.Time) string {
+	year, week := t.UTC().ISOWeek()
+	return fmt.Sprintf("%d-W%02d", year, week)
+}
+
+func copyAggregates(src map[string]PlayerAggregate) map[string]PlayerAggregate {
+	dst := make(map[string]PlayerAggregate, len(src))
+	for k, v := range src {
+		dst[k] = v
+	}
+	return dst
+}
@@ -0,0 +1,86 @@
+package game
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLeaderboards(t *testing.T) {
+	now := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC) // A Wednesday
+	l := NewLeaderboards()
+
+	// Last week: only counts all-time
+	l.Record(Settlement{PlayerID: "old", Time: now.AddDate(0, 0, -7), Result: StatusPlayerWon, Staked: 10, Payout: 1000})
+	// Earlier this week
+	l.Record(Settlement{PlayerID: "a", Time: now.AddDate(0, 0, -2), Result: StatusPlayerWon, Staked: 10, Payout: 25, Blackjack: true})
+	// Today
+	l.Record(Settlement{PlayerID: "a", Time: now, Result: StatusDealerWon, Staked: 10})
+	l.Record(Settlement{PlayerID: "b", Time: now, Result: StatusPlayerWon, Staked: 10, Payout: 20})
+	l.Record(Settlement{PlayerID: "c", Time: now, Result: StatusPush, Staked: 10, Payout: 10})
+
+	tests := []struct {
+		metric LeaderboardMetric
+		window LeaderboardWindow
+		want   []string
+		values []float64
+	}{
+		{MetricNet, WindowAllTime, []string{"old", "b", "a", "c"}, []float64{990, 10, 5, 0}},
+		{MetricNet, WindowWeekly, []string{"b", "a", "c"}, []float64{10, 5, 0}},
+		{MetricNet, WindowDaily, []string{"b", "c", "a"}, []float64{10, 0, -10}},
+		{MetricBiggestWin, WindowWeekly, []string{"a", "b"}, []float64{15, 10}},
+		{MetricBlackjacks, WindowAllTime, []string{"a"}, []float64{1}},
+		{MetricWinRate, WindowAllTime, []string{}, nil}, // Nobody has played enough games
+	}
+	for _, tt := range tests {
+		entries, err := l.Rank(tt.metric, tt.window, now)
+		if err != nil {
+			t.Fatalf("%s/%s: %v", tt.metric, tt.window, err)
+		}
+		if len(entries) != len(tt.want) {
+			t.Errorf("%s/%s: expected %v, got %+v", tt.metric, tt.window, tt.want, entries)
+			continue
+		}
+		for i, e := range entries {
+			if e.PlayerID != tt.want[i] || e.Value != tt.values[i] {
+				t.Errorf("%s/%s #%d: expected %s=%v, got %s=%v", tt.metric, tt.window, i+1, tt.want[i], tt.values[i], e.PlayerID, e.Value)
+			}
+		}
+	}
+
+	if _, err := l.Rank("luck", WindowDaily, now); !errors.Is(err, ErrUnknownMetric) {
+		t.Errorf("Expected ErrUnknownMetric, got %v", err)
+	}
+	if _, err := l.Rank(MetricNet, "monthly", now); !errors.Is(err, ErrUnknownWindow) {
+		t.Errorf("Expected ErrUnknownWindow, got %v", err)
+	}
+
+	// Nothing was played tomorrow yet
+	if entries, _ := l.Rank(MetricNet, WindowDaily, now.AddDate(0, 0, 1)); len(entries) != 0 {
+		t.Errorf("Expected an empty daily leaderboard tomorrow, got %+v", entries)
+	}
+
+	// A game settled yesterday but recorded late neither wipes today's boards nor joins them
+	l.Record(Settlement{PlayerID: "late", Time: now.AddDate(0, 0, -1), Result: StatusPlayerWon, Staked: 10, Payout: 20})
+	daily, _ := l.Rank(MetricNet, WindowDaily, now)
+	weekly, _ := l.Rank(MetricNet, WindowWeekly, now)
+	allTime, _ := l.Rank(MetricNet, WindowAllTime, now)
+	if len(daily) != 3 || len(weekly) != 4 || len(allTime) != 5 {
+		t.Errorf("Expected the late game in the weekly and all-time boards only, got %d daily, %d weekly and %d all-time", len(daily), len(weekly), len(allTime))
+	}
+}
+
+func TestLeaderboardTiesShareRank(t *testing.T) {
+	now := time.Now()
+	l := NewLeaderboards()
+	for _, id := range []string{"a", "b", "c"} {
+		l.Record(Settlement{PlayerID: id, Time: now, Staked: 10, Payout: 20})
+	}
+	l.Record(Settlement{PlayerID: "c", Time: now, Staked: 10, Payout: 20})
+
+	entries, _ := l.Rank(MetricNet, WindowAllTime, now)
+	ranks := []int{entries[0].Rank, entries[1].Rank, entries[2].Rank}
+	if ranks[0] != 1 || ranks[1] != 2 || ranks[2] != 2 {
+		t.Errorf("Expected ranks [1 2 2], got %v", ranks)
+	}
+}
@@ -0,0 +1,203 @@
+package game
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// TxKind describes why tokens moved
+type TxKind string
+
+const (
+	TxBet    TxKind = "bet"
+	TxPayout TxKind = "payout"
+	TxRefund TxKind = "refund"
+	TxTopUp  TxKind = "top_up"
+	TxBonus  TxKind = "bonus"
+	TxGrant  TxKind = "grant" // Free chips under the bankroll policy, see Bankroll
+)
+
+// HouseAccount is the counterparty of every player transaction
+const HouseAccount = "house"
+
+var (
+	ErrInsufficientFunds = errors.New("insufficient funds")
+	ErrUnknownPlayer     = errors.New("unknown player")
+	ErrInvalidAmount     = errors.New("amount must be positive")
+)
+
+// Transaction is a double-entry record: Amount leaves the Debit account and enters the Credit account
+type Transaction struct {
+	ID           int       `json:"id"`
+	Time         time.Time `json:"time"`
+	Kind         TxKind    `json:"kind"`
+	PlayerID     string    `json:"player_id"`
+	GameID       string    `json:"game_id,omitempty"`
+	Debit        string    `json:"debit"`
+	Credit       string    `json:"credit"`
+	Amount       int       `json:"amount"`
+	Reason       string    `json:"reason"`
+	BalanceAfter int       `json:"balance_after"` // Player balance once posted
+}
+
+// PlayerAccount returns the ledger account of a player
+func PlayerAccount(playerID string) string {
+	return "player:" + playerID
+}
+
+// Ledger records every token movement and is the only writer of Player.Balance
+type Ledger struct {
+	mu       sync.RWMutex
+	players  *PlayerStore
+	entries  []Transaction
+	byPlayer map[string][]int // Entry indexes per player
+	balances map[string]int   // Derived balance per account
+
+	// Notify, if set, is called after each transaction is posted
+	Notify func(Transaction)
+}
+
+// NewLedger creates a Ledger over the given players
+func NewLedger(players *PlayerStore) *Ledger {
+	return &Ledger{
+		players:  players,
+		byPlayer: make(map[string][]int),
+		balances: make(map[string]int),
+	}
+}
+
+// Debit takes tokens from a player (e.g. a bet).
+// It fails with ErrInsufficientFunds instead of letting the balance go negative.
+func (l *Ledger) Debit(playerID string, kind TxKind, gameID string, amount int, reason string) (Transaction, error) {
+	return l.post(playerID, kind, gameID, -amount, reason)
+}
+
+// Credit gives tokens to a player (e.g. a payout)
+func (l *Ledger) Credit(playerID string, kind TxKind, gameID string, amount int, reason string) (Transaction, error) {
+	return l.post(playerID, kind, gameID, amount, reason)
+}
+
+// post records a transfer between the house and a player; delta is signed from the player's side
+func (l *Ledger) post(playerID string, kind TxKind, gameID string, delta int, reason string) (Transaction, error) {
+	if delta == 0 {
+		return Transaction{}, ErrInvalidAmount
+	}
+	l.mu.Lock()
+	tx := Transaction{
+		ID:       len(l.entries) + 1,
+		Time:     time.Now(),
+		Kind:     kind,
+		PlayerID: playerID,
+		GameID:   gameID,
+		Reason:   reason,
+	}
+	if delta < 0 {
+		tx.Debit, tx.Credit, tx.Amount = PlayerAccount(playerID), HouseAccount, -delta
+	} else {
+		tx.Debit, tx.Credit, tx.Amount = HouseAccount, PlayerAccount(playerID), delta
+	}
+
+	// The store applies the change under its lock; holding ours keeps both in step
+	balance, err := l.players.adjust(playerID, delta)
+	if err != nil {
+		l.mu.Unlock()
+		return Transaction{}, err
+	}
+	tx.BalanceAfter = balance
+	l.record(tx)
+	l.mu.Unlock()
+
+	if l.Notify != nil {
+		l.Notify(tx)
+	}
+	return tx, nil
+}
+
+// record appends a transaction and updates the derived balances; callers hold the lock
+func (l *Ledger) record(tx Transaction) {
+	l.entries = append(l.entries, tx)
+	l.byPlayer[tx.PlayerID] = append(l.byPlayer[tx.PlayerID], len(l.entries)-1)
+	l.balances[tx.Debit] -= tx.Amount
+	l.balances[tx.Credit] += tx.Amount
+}
+
+// Transactions returns a player's transactions, newest first
+func (l *Ledger) Transactions(playerID string) []Transaction {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	idx := l.byPlayer[playerID]
+	txs := make([]Transaction, 0, len(idx))
+	for i := len(idx) - 1; i >= 0; i-- {
+		txs = append(txs, l.entries[idx[i]])
+	}
+	return txs
+}
+
+// Activity sums a player's betting since a time: the total wagered and the net result
+// of bets, payouts and refunds, negative for a loss. Grants and bonuses are left out.
+func (l *Ledger) Activity(playerID string, since time.Time) (wagered, net int) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	for _, i := range l.byPlayer[playerID] {
+		tx := l.entries[i]
+		if tx.Time.Before(since) {
+			continue
+		}
+		switch tx.Kind {
+		case TxBet:
+			wagered += tx.Amount
+			net -= tx.Amount
+		case TxPayout, TxRefund:
+			net += tx.Amount
+		}
+	}
+	return wagered, net
+}
+
+// Balance returns the balance of an account as derived from the entries
+func (l *Ledger) Balance(account string) int {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.balances[account]
+}
+
+// Reconcile compares a player's stored balance with the ledger.
+// Both are read at the same point, between postings. It returns them and whether they agree.
+func (l *Ledger) Reconcile(playerID string) (balance, ledgerBalance int, ok bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	balance = l.players.Balance(playerID)
+	ledgerBalance = l.balances[PlayerAccount(playerID)]
+	return balance, ledgerBalance, balance == ledgerBalance
+}
+
+// Capture returns the players and every transaction as of the same point, between postings,
+// so that the balances match the entries.
+func (l *Ledger) Capture() ([]*Player, []Transaction) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.players.All(), append([]Transaction(nil), l.entries...)
+}
+
+// Restore replaces the entries with previously saved ones.
+// It returns the IDs of players whose restored balance disagrees with the entries;
+// their balances are left as they are, see Reconcile.
+func (l *Ledger) Restore(txs []Transaction) []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = nil
+	l.byPlayer = make(map[string][]int)
+	l.balances = make(map[string]int)
+	for _, tx := range txs {
+		l.record(tx)
+	}
+
+	var mismatched []string
+	for _, player := range l.players.All() {
+		if player.Balance != l.balances[PlayerAccount(player.ID)] {
+			mismatched = append(mismatched, player.ID)
+		}
+	}
+	return mismatched
+}
@@ -0,0 +1,86 @@
+package game
+
+import "testing"
+
+func TestLedgerPostsAndReconciles(t *testing.T) {
+	players := NewPlayerStore()
+	players.Save(&Player{ID: "p1"})
+	ledger := NewLedger(players)
+
+	if _, err := ledger.Credit("p1", TxBonus, "", 100, "welcome bonus"); err != nil {
+		t.Fatalf("credit: %v", err)
+	}
+	if _, err := ledger.Debit("p1", TxBet, "g1", 30, "bet"); err != nil {
+		t.Fatalf("debit: %v", err)
+	}
+	if _, err := ledger.Credit("p1", TxPayout, "g1", 60, "hand 1 win"); err != nil {
+		t.Fatalf("credit: %v", err)
+	}
+
+	// The bet cannot overdraw the account
+	if _, err := ledger.Debit("p1", TxBet, "g2", 500, "bet"); err != ErrInsufficientFunds {
+		t.Errorf("expected ErrInsufficientFunds, got %v", err)
+	}
+
+	player, _ := players.Get("p1")
+	if player.Balance != 130 {
+		t.Errorf("expected balance 130, got %d", player.Balance)
+	}
+	if _, balance, ok := ledger.Reconcile("p1"); !ok || balance != 130 {
+		t.Errorf("expected ledger to reconcile at 130, got %d (%v)", balance, ok)
+	}
+	// Double entry: whatever the player gained the house lost
+	if house := ledger.Balance(HouseAccount); house != -130 {
+		t.Errorf("expected house balance -130, got %d", house)
+	}
+
+	txs := ledger.Transactions("p1")
+	if len(txs) != 3 || txs[0].Kind != TxPayout || txs[0].BalanceAfter != 130 {
+		t.Errorf("expected 3 transactions newest first, got %+v", txs)
+	}
+
+	// Drifting the cached balance is detected
+	players.Save(&Player{ID: "p1", Balance: 999})
+	if _, _, ok := ledger.Reconcile("p1"); ok {
+		t.Error("expected reconcile to flag a mismatch")
+	}
+}
+
+func TestLedgerRestoreReportsMismatches(t *testing.T) {
+	players := NewPlayerStore()
+	players.Save(&Player{ID: "legacy", Balance: 42})
+	players.Save(&Player{ID: "p1", Balance: 10})
+	ledger := NewLedger(players)
+	mismatched := ledger.Restore([]Transaction{{ID: 1, Kind: TxBonus, PlayerID: "p1", Debit: HouseAccount, Credit: PlayerAccount("p1"), Amount: 10}})
+
+	if len(mismatched) != 1 || mismatched[0] != "legacy" {
+		t.Errorf("expected only legacy to be reported, got %v", mismatched)
+	}
+	if balance, ledgerBalance, ok := ledger.Reconcile("legacy"); ok || balance != 42 || ledgerBalance != 0 {
+		t.Errorf("expected legacy to stay unreconciled at 42, got %d and %d", balance, ledgerBalance)
+	}
+}
+
+func TestLedgerPostsWhilePlayersAreRead(t *testing.T) {
+	players := NewPlayerStore()
+	players.Save(&Player{ID: "p1"})
+	ledger := NewLedger(players)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			ledger.Credit("p1", TxBonus, "", 1, "bonus")
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		players.All()
+		if balance, ledgerBalance, ok := ledger.Reconcile("p1"); !ok {
+			t.Fatalf("expected balances to agree between postings, got %d and %d", balance, ledgerBalance)
+		}
+	}
+	<-done
+	if p, _ := players.Get("p1"); p.Balance != 100 {
+		t.Errorf("expected balance 100, got %d", p.Balance)
+	}
+}
@@ -0,0 +1,110 @@
+package game
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Codes of violated table limits
+const (
+	LimitBetBelowMin    = "bet_below_min"
+	LimitBetAboveMax    = "bet_above_max"
+	LimitBetIncrement   = "bet_increment"
+	LimitGameExposure   = "game_exposure"
+	LimitPlayerExposure = "player_exposure"
+)
+
+// Names of the table profiles
+const (
+	DefaultTableProfile = "standard"
+	HighRollerProfile   = "high_roller"
+)
+
+// LimitError is a violated table or responsible gaming limit
+type LimitError struct {
+	Code    string    `json:"code"`
+	Limit   int       `json:"limit"`
+	Until   time.Time `json:"until,omitempty"` // When play may resume, for cool-offs, exclusions and sessions
+	Message string    `json:"error"`
+}
+
+func (e *LimitError) Error() string {
+	return e.Message
+}
+
+// TableProfile are the betting limits a game is dealt under. Limits left at zero do not apply.
+type TableProfile struct {
+	Name              string `json:"name"`
+	MinBet            int    `json:"min_bet"`
+	MaxBet            int    `json:"max_bet"`
+	Chips             []int  `json:"chips"`               // Denominations; bets are multiples of the smallest
+	MaxGameExposure   int    `json:"max_game_exposure"`   // Most riding on one game after splits, doubles and buys
+	MaxPlayerExposure int    `json:"max_player_exposure"` // Most riding on a player's unfinished games together, side bets included
+}
+
+// tableProfiles holds every table profile by name
+var tableProfiles = map[string]TableProfile{
+	DefaultTableProfile: {Name: DefaultTableProfile, MinBet: 1, MaxBet: 10, Chips: []int{1, 5}, MaxGameExposure: 40, MaxPlayerExposure: 100},
+	HighRollerProfile:   {Name: HighRollerProfile, MinBet: 25, MaxBet: 500, Chips: []int{25, 100, 500}, MaxGameExposure: 2000, MaxPlayerExposure: 5000},
+}
+
+// LookupTableProfile returns a table profile by name; the empty name is the standard table
+func LookupTableProfile(name string) (TableProfile, bool) {
+	if name == "" {
+		name = DefaultTableProfile
+	}
+	p, ok := tableProfiles[name]
+	return p, ok
+}
+
+// TableProfiles returns every table profile, by minimum bet
+func TableProfiles() []TableProfile {
+	profiles := make([]TableProfile, 0, len(tableProfiles))
+	for _, p := range tableProfiles {
+		profiles = append(profiles, p)
+	}
+	sort.Slice(profiles, func(i, j int) bool { return profiles[i].MinBet < profiles[j].MinBet })
+	return profiles
+}
+
+// CheckBet checks a main bet against the minimum, the maximum and the chip increment
+func (p TableProfile) CheckBet(bet int) error {
+	if p.MinBet > 0 && bet < p.MinBet {
+		return &LimitError{Code: LimitBetBelowMin, Limit: p.MinBet, Message: fmt.Sprintf("Bet must be at least %d", p.MinBet)}
+	}
+	if p.MaxBet > 0 && bet > p.MaxBet {
+		return &LimitError{Code: LimitBetAboveMax, Limit: p.MaxBet, Message: fmt.Sprintf("Bet must be at most %d", p.MaxBet)}
+	}
+	if chip := p.smallestChip(); chip > 1 && bet%chip != 0 {
+		return &LimitError{Code: LimitBetIncrement, Limit: chip, Message: fmt.Sprintf("Bet must be a multiple of %d", chip)}
+	}
+	return nil
+}
+
+// CheckGameExposure checks what a game would have riding after a split, double or buy
+func (p TableProfile) CheckGameExposure(stake int) error {
+	if p.MaxGameExposure > 0 && stake > p.MaxGameExposure {
+		return &LimitError{Code: LimitGameExposure, Limit: p.MaxGameExposure, Message: fmt.Sprintf("At most %d may ride on one game", p.MaxGameExposure)}
+	}
+	return nil
+}
+
+// CheckPlayerExposure checks what a player would have riding across unfinished games
+func (p TableProfile) CheckPlayerExposure(exposure int) error {
+	if p.MaxPlayerExposure > 0 && exposure > p.MaxPlayerExposure {
+		return &LimitError{Code: LimitPlayerExposure, Limit: p.MaxPlayerExposure, Message: fmt.Sprintf("At most %d may ride on your games together", p.MaxPlayerExposure)}
+	}
+	return nil
+}
+
+// smallestChip returns the smallest chip denomination, 1 if there are none
+func (p TableProfile) smallestChip() int {
+	chip := 0
+	for _, c := range p.Chips {
+		if chip == 0 || c < chip {
+			chip = c
+		}
+	}
+	return max(chip, 1)
+}
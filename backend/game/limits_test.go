@@ -0,0 +1,44 @@
+package game
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTableProfileCheckBet(t *testing.T) {
+	profile, _ := LookupTableProfile(HighRollerProfile)
+	tests := []struct {
+		bet  int
+		code string
+	}{
+		{25, ""},
+		{500, ""},
+		{10, LimitBetBelowMin},
+		{525, LimitBetAboveMax},
+		{60, LimitBetIncrement},
+	}
+	for _, tt := range tests {
+		err := profile.CheckBet(tt.bet)
+		var limit *LimitError
+		if tt.code == "" && err != nil {
+			t.Errorf("bet %d: unexpected %v", tt.bet, err)
+		}
+		if tt.code != "" && (!errors.As(err, &limit) || limit.Code != tt.code) {
+			t.Errorf("bet %d: expected %s, got %v", tt.bet, tt.code, err)
+		}
+	}
+
+	if err := (TableProfile{}).CheckBet(1000); err != nil {
+		t.Errorf("Expected no limits on an empty profile, got %v", err)
+	}
+}
+
+func TestGameStoreExposure(t *testing.T) {
+	store := NewGameStore()
+	store.Save(&GameState{ID: "open", PlayerID: "p1", BetAmount: 10, Hands: []Hand{{Doubled: true}}, SideBets: []SideBetResult{{Name: "bust_it", Amount: 5}}, Status: StatusPlayerTurn})
+	store.Save(&GameState{ID: "done", PlayerID: "p1", BetAmount: 10, Hands: []Hand{{}}, Status: StatusDealerWon})
+	store.Save(&GameState{ID: "chips", PlayerID: "p1", BetAmount: 10, Hands: []Hand{{}}, Status: StatusPlayerTurn, TournamentID: "t1"})
+	if got := store.Exposure("p1", nil); got != 25 {
+		t.Errorf("Expected exposure 25, got %d", got)
+	}
+}
@@ -1,5 +1,10 @@
 package game
 
+import (
+	"sync"
+	"time"
+)
+
 // Suit represents the suit of a card
 type Suit string
 
@@ -44,8 +49,15 @@ type Card struct {
 
 // Hand represents a player's or dealer's hand
 type Hand struct {
-	Cards []Card `json:"cards"`
-	Score int    `json:"score"` // Calculated score
+	Cards       []Card `json:"cards"`
+	Score       int    `json:"score"`                 // Calculated score
+	Doubled     bool   `json:"doubled,omitempty"`     // Bet doubled for exactly one more card
+	Surrendered bool   `json:"surrendered,omitempty"` // Given up for half of what was riding
+	Split       bool   `json:"split,omitempty"`       // Dealt from a split; cannot be split again
+	Switched    bool   `json:"switched,omitempty"`    // Second card swapped in Switch; a 21 is not a blackjack
+	FreeStake   bool   `json:"free_stake,omitempty"`  // Bet funded by the house on a free split
+	FreeDouble  bool   `json:"free_double,omitempty"` // Doubling funded by the house
+	Bought      int    `json:"bought,omitempty"`      // Stake added by buying cards
 }
 
 // GameStatus represents the current state of the game
@@ -57,17 +69,119 @@ const (
 	StatusPlayerWon  GameStatus = "PlayerWon"
 	StatusDealerWon  GameStatus = "DealerWon" // Dealer wins or Player busts
 	StatusPush       GameStatus = "Push"
+	StatusExpired    GameStatus = "Expired" // Abandoned and closed by the janitor
 )
 
 // GameState represents the entire state of a blackjack game
 type GameState struct {
-	ID         string     `json:"id"`
-	PlayerID   string     `json:"player_id"`
-	BetAmount  int        `json:"bet_amount"`
-	PlayerHand Hand       `json:"player_hand"`
-	SplitHand  *Hand      `json:"split_hand,omitempty"` // Second hand if split
-	CurrentHandIndex int  `json:"current_hand_index"`   // 0 for PlayerHand, 1 for SplitHand
-	DealerHand Hand       `json:"dealer_hand"`
-	Deck       []Card     `json:"-"` // Hide deck from JSON
-	Status     GameStatus `json:"status"`
+	ID               string          `json:"id"`
+	PlayerID         string          `json:"player_id"`
+	BetAmount        int             `json:"bet_amount"`
+	Hands            []Hand          `json:"hands"`              // Player hands, played in order; more than one after a split or in Switch
+	CurrentHandIndex int             `json:"current_hand_index"` // Index in Hands of the hand being played
+	DealerHand       Hand            `json:"dealer_hand"`
+	Deck             []Card          `json:"-"` // Hide deck from JSON
+	Status           GameStatus      `json:"status"`
+	CreatedAt        time.Time       `json:"created_at"`
+	UpdatedAt        time.Time       `json:"updated_at"`              // Last time the game was saved
+	Events           []Event         `json:"-"`                       // Ordered event log, see Replay
+	ShareToken       string          `json:"-"`                       // Lets others view the replay without the player ID
+	TournamentID     string          `json:"tournament_id,omitempty"` // Set if played with tournament chips
+	SideBets         []SideBetResult `json:"side_bets,omitempty"`
+	Rules            Rules           `json:"rules"`
+	Limits           TableProfile    `json:"limits"` // Table limits; zero for tournament games
+
+	mu sync.Mutex // See Lock
+}
+
+// Lock serializes changes to the game between requests, the janitor and snapshots
+func (g *GameState) Lock() {
+	g.mu.Lock()
+}
+
+// Unlock releases the lock taken by Lock
+func (g *GameState) Unlock() {
+	g.mu.Unlock()
+}
+
+// Copy returns a deep copy of the game, taken under its lock
+func (g *GameState) Copy() *GameState {
+	g.Lock()
+	defer g.Unlock()
+	hands := make([]Hand, len(g.Hands))
+	for i, h := range g.Hands {
+		hands[i] = h.clone()
+	}
+	return &GameState{
+		ID:               g.ID,
+		PlayerID:         g.PlayerID,
+		BetAmount:        g.BetAmount,
+		Hands:            hands,
+		CurrentHandIndex: g.CurrentHandIndex,
+		DealerHand:       g.DealerHand.clone(),
+		Deck:             append([]Card(nil), g.Deck...),
+		Status:           g.Status,
+		CreatedAt:        g.CreatedAt,
+		UpdatedAt:        g.UpdatedAt,
+		Events:           append([]Event(nil), g.Events...), // Recorded events are never changed
+		ShareToken:       g.ShareToken,
+		TournamentID:     g.TournamentID,
+		SideBets:         append([]SideBetResult(nil), g.SideBets...),
+		Rules:            g.Rules,
+		Limits:           g.Limits,
+	}
+}
+
+// IsFinished reports whether the game no longer accepts actions
+func (g *GameState) IsFinished() bool {
+	return g.Status != StatusPlayerTurn && g.Status != StatusDealerTurn
+}
+
+// Stake returns the total amount the player has on the table for this game.
+// Free bets funded by the house are not part of it.
+func (g *GameState) Stake() int {
+	stake := 0
+	for _, hand := range g.Hands {
+		real, _ := g.HandStakes(hand)
+		stake += real
+	}
+	return stake
+}
+
+// HandStakes returns the amount riding on one player hand, split into the player's
+// money and free bets funded by the house
+func (g *GameState) HandStakes(hand Hand) (real, free int) {
+	add := func(isFree bool) {
+		if isFree {
+			free += g.BetAmount
+		} else {
+			real += g.BetAmount
+		}
+	}
+	add(hand.FreeStake)
+	if hand.Doubled {
+		add(hand.FreeDouble)
+	}
+	real += hand.Bought
+	return real, free
+}
+
+// HandWinnings returns what one finished player hand pays back under the game's rules,
+// the stake included; free bets only pay their winnings
+func (g *GameState) HandWinnings(hand Hand) int {
+	bet, free := g.HandStakes(hand)
+	return g.Rules.HandPayout(hand, g.DealerHand, bet) + g.Rules.FreeStakeWinnings(hand, g.DealerHand, free)
+}
+
+// ActiveHand returns the player hand being played, or nil if the index is invalid
+func (g *GameState) ActiveHand() *Hand {
+	if g.CurrentHandIndex < 0 || g.CurrentHandIndex >= len(g.Hands) {
+		return nil
+	}
+	return &g.Hands[g.CurrentHandIndex]
+}
+
+// LastHand reports whether the hand being played is the last one
+func (g *GameState) LastHand() bool {
+	return g.CurrentHandIndex >= len(g.Hands)-1
 }
@@ -2,6 +2,17 @@ package game
 
 // Player represents a user in the system
 type Player struct {
-	ID      string `json:"id"`
-	Balance int    `json:"balance"`
+	ID      string  `json:"id"`
+	Balance int     `json:"balance"`
+	Badges  []Badge `json:"badges"` // Unlocked achievements, oldest first
+}
+
+// HasBadge reports whether the player has unlocked an achievement
+func (p *Player) HasBadge(id string) bool {
+	for _, b := range p.Badges {
+		if b.ID == id {
+			return true
+		}
+	}
+	return false
 }
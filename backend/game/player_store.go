@@ -4,7 +4,8 @@ import (
 	"sync"
 )
 
-// PlayerStore is a thread-safe in-memory store for players
+// PlayerStore is a thread-safe in-memory store for players.
+// It hands out copies; balances change only through the Ledger and badges through AwardBadge.
 type PlayerStore struct {
 	mu      sync.RWMutex
 	players map[string]*Player
@@ -24,10 +25,65 @@ func (s *PlayerStore) Save(player *Player) {
 	s.players[player.ID] = player
 }
 
-// Get retrieves a player by ID
+// Get retrieves a copy of a player by ID
 func (s *PlayerStore) Get(id string) (*Player, bool) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 	player, exists := s.players[id]
-	return player, exists
+	if !exists {
+		return nil, false
+	}
+	p := *player
+	return &p, true
+}
+
+// Balance returns a player's balance, zero for an unknown player
+func (s *PlayerStore) Balance(id string) int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if player, exists := s.players[id]; exists {
+		return player.Balance
+	}
+	return 0
+}
+
+// adjust moves a player's balance by delta and returns the new balance.
+// It refuses to go negative. Only the Ledger calls it, under its own lock.
+func (s *PlayerStore) adjust(id string, delta int) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	player, exists := s.players[id]
+	if !exists {
+		return 0, ErrUnknownPlayer
+	}
+	if player.Balance+delta < 0 {
+		return player.Balance, ErrInsufficientFunds
+	}
+	player.Balance += delta
+	return player.Balance, nil
+}
+
+// All returns a copy of every stored player
+func (s *PlayerStore) All() []*Player {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	players := make([]*Player, 0, len(s.players))
+	for _, player := range s.players {
+		p := *player
+		players = append(players, &p)
+	}
+	return players
+}
+
+// AwardBadge gives a player a badge unless they already have it, and reports whether it was added
+func (s *PlayerStore) AwardBadge(id string, badge Badge) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	player, exists := s.players[id]
+	if !exists || player.HasBadge(badge.ID) {
+		return false
+	}
+	// Copies handed out by All keep their own badges
+	player.Badges = append(player.Badges[:len(player.Badges):len(player.Badges)], badge)
+	return true
 }
@@ -0,0 +1,286 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// SafeguardKind names a self-set responsible gaming limit
+type SafeguardKind string
+
+const (
+	SafeguardDailyLoss  SafeguardKind = "daily_loss"      // Net loss over the last 24 hours
+	SafeguardWeeklyLoss SafeguardKind = "weekly_loss"     // Net loss over the last 7 days
+	SafeguardDailyWager SafeguardKind = "daily_wager"     // Total wagered over the last 24 hours
+	SafeguardTotalWager SafeguardKind = "total_wager"     // Total ever wagered
+	SafeguardSession    SafeguardKind = "session_minutes" // Longest session, see SessionBreak
+)
+
+// SafeguardKinds lists every self-set limit
+var SafeguardKinds = []SafeguardKind{SafeguardDailyLoss, SafeguardWeeklyLoss, SafeguardDailyWager, SafeguardTotalWager, SafeguardSession}
+
+// Codes of responsible gaming violations, see LimitError
+const (
+	LimitDailyLoss    = "daily_loss_limit"
+	LimitWeeklyLoss   = "weekly_loss_limit"
+	LimitDailyWager   = "daily_wager_limit"
+	LimitTotalWager   = "total_wager_limit"
+	LimitSession      = "session_limit"
+	LimitCoolOff      = "cool_off"
+	LimitSelfExcluded = "self_excluded"
+)
+
+const (
+	// SafeguardRaiseDelay is how long raising or removing a limit takes to apply; lowering applies at once
+	SafeguardRaiseDelay = 24 * time.Hour
+	// SessionBreak is the pause without starting a game that ends a session
+	SessionBreak = 30 * time.Minute
+)
+
+// Allowed lengths of cool-off and self-exclusion periods, in days
+const (
+	MinCoolOffDays   = 1
+	MaxCoolOffDays   = 42
+	MinExclusionDays = 180
+	MaxExclusionDays = 5 * 365
+)
+
+var (
+	ErrUnknownSafeguard = errors.New("unknown limit")
+	ErrInvalidSafeguard = errors.New("invalid limit")
+	ErrInvalidPeriod    = errors.New("invalid cool-off or self-exclusion period")
+)
+
+// Safeguard is one self-set limit with a raise that may be waiting for the delay
+type Safeguard struct {
+	Value       int       `json:"value"`                  // 0 for no limit
+	Pending     *int      `json:"pending,omitempty"`      // Raised or removed value, once the delay passed
+	EffectiveAt time.Time `json:"effective_at,omitempty"` // When Pending applies
+}
+
+// Safeguards are a player's responsible gaming settings
+type Safeguards struct {
+	PlayerID         string                      `json:"player_id"`
+	Limits           map[SafeguardKind]Safeguard `json:"limits"`
+	CoolOffUntil     time.Time                   `json:"cool_off_until,omitempty"`
+	ExcludedUntil    time.Time                   `json:"excluded_until,omitempty"`
+	SessionStartedAt time.Time                   `json:"session_started_at,omitempty"`
+	LastPlayedAt     time.Time                   `json:"last_played_at,omitempty"`
+}
+
+// settle applies the raises whose delay has passed
+func (s *Safeguards) settle(now time.Time) {
+	for kind, limit := range s.Limits {
+		if limit.Pending != nil && !now.Before(limit.EffectiveAt) {
+			s.Limits[kind] = Safeguard{Value: *limit.Pending}
+		}
+	}
+}
+
+// clone returns a deep copy
+func (s Safeguards) clone() Safeguards {
+	limits := make(map[SafeguardKind]Safeguard, len(s.Limits))
+	for kind, limit := range s.Limits {
+		limits[kind] = limit
+	}
+	s.Limits = limits
+	return s
+}
+
+// SafeguardStore keeps the responsible gaming settings of every player and enforces them.
+// All methods are safe for concurrent use.
+type SafeguardStore struct {
+	mu      sync.Mutex
+	ledger  *Ledger
+	players map[string]*Safeguards
+}
+
+// NewSafeguardStore creates a SafeguardStore measuring losses and wagers in the ledger
+func NewSafeguardStore(ledger *Ledger) *SafeguardStore {
+	return &SafeguardStore{ledger: ledger, players: make(map[string]*Safeguards)}
+}
+
+// get returns a player's settings, creating them; callers hold the lock
+func (s *SafeguardStore) get(playerID string, now time.Time) *Safeguards {
+	sg, ok := s.players[playerID]
+	if !ok {
+		sg = &Safeguards{PlayerID: playerID, Limits: map[SafeguardKind]Safeguard{}}
+		s.players[playerID] = sg
+	}
+	sg.settle(now)
+	return sg
+}
+
+// Get returns a player's settings as they apply at a time
+func (s *SafeguardStore) Get(playerID string, now time.Time) Safeguards {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.get(playerID, now).clone()
+}
+
+// SetLimit sets a limit; 0 removes it. A stricter limit applies at once,
+// a looser one only after SafeguardRaiseDelay.
+func (s *SafeguardStore) SetLimit(playerID string, kind SafeguardKind, value int, now time.Time) (Safeguards, error) {
+	known := false
+	for _, k := range SafeguardKinds {
+		known = known || k == kind
+	}
+	if !known {
+		return Safeguards{}, ErrUnknownSafeguard
+	}
+	if value < 0 {
+		return Safeguards{}, ErrInvalidSafeguard
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sg := s.get(playerID, now)
+	current := sg.Limits[kind].Value
+	if value != 0 && (current == 0 || value <= current) {
+		sg.Limits[kind] = Safeguard{Value: value}
+	} else if value != current {
+		sg.Limits[kind] = Safeguard{Value: current, Pending: &value, EffectiveAt: now.Add(SafeguardRaiseDelay)}
+	}
+	return sg.clone(), nil
+}
+
+// CoolOff blocks play for a number of days. An ongoing period can be extended but not shortened.
+func (s *SafeguardStore) CoolOff(playerID string, days int, now time.Time) (Safeguards, error) {
+	return s.block(playerID, days, MinCoolOffDays, MaxCoolOffDays, now, func(sg *Safeguards) *time.Time { return &sg.CoolOffUntil })
+}
+
+// SelfExclude blocks play for a number of days. An ongoing period can be extended but not shortened.
+func (s *SafeguardStore) SelfExclude(playerID string, days int, now time.Time) (Safeguards, error) {
+	return s.block(playerID, days, MinExclusionDays, MaxExclusionDays, now, func(sg *Safeguards) *time.Time { return &sg.ExcludedUntil })
+}
+
+// block sets a cool-off or self-exclusion period, keeping a later end that is already set
+func (s *SafeguardStore) block(playerID string, days, minDays, maxDays int, now time.Time, until func(*Safeguards) *time.Time) (Safeguards, error) {
+	if days < minDays || days > maxDays {
+		return Safeguards{}, fmt.Errorf("%w: between %d and %d days", ErrInvalidPeriod, minDays, maxDays)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sg := s.get(playerID, now)
+	if end := now.AddDate(0, 0, days); end.After(*until(sg)) {
+		*until(sg) = end
+	}
+	return sg.clone(), nil
+}
+
+// CheckStart checks whether a player may start a game staking stake.
+// Violations are returned as a *LimitError.
+func (s *SafeguardStore) CheckStart(playerID string, stake int, now time.Time) error {
+	s.mu.Lock()
+	sg := s.get(playerID, now).clone()
+	s.mu.Unlock()
+
+	if err := checkPlay(sg, now); err != nil {
+		return err
+	}
+	return s.checkStake(playerID, sg, stake, now)
+}
+
+// CheckPlay checks the self-exclusion, cool-off and session limits only. It guards play
+// that stakes no real money but can win some, such as tournaments.
+func (s *SafeguardStore) CheckPlay(playerID string, now time.Time) error {
+	s.mu.Lock()
+	sg := s.get(playerID, now).clone()
+	s.mu.Unlock()
+	return checkPlay(sg, now)
+}
+
+// checkPlay checks whether a player may play at all right now
+func checkPlay(sg Safeguards, now time.Time) error {
+	if now.Before(sg.ExcludedUntil) {
+		return &LimitError{Code: LimitSelfExcluded, Until: sg.ExcludedUntil, Message: "You are self-excluded until " + sg.ExcludedUntil.Format(time.RFC3339)}
+	}
+	if now.Before(sg.CoolOffUntil) {
+		return &LimitError{Code: LimitCoolOff, Until: sg.CoolOffUntil, Message: "You are cooling off until " + sg.CoolOffUntil.Format(time.RFC3339)}
+	}
+
+	if limit := sg.Limits[SafeguardSession].Value; limit > 0 && !sg.SessionStartedAt.IsZero() && now.Sub(sg.LastPlayedAt) < SessionBreak {
+		if end := sg.SessionStartedAt.Add(time.Duration(limit) * time.Minute); !now.Before(end) {
+			resume := sg.LastPlayedAt.Add(SessionBreak)
+			return &LimitError{Code: LimitSession, Limit: limit, Until: resume, Message: fmt.Sprintf("Session limit of %d minutes reached; take a break until %s", limit, resume.Format(time.RFC3339))}
+		}
+	}
+	return nil
+}
+
+// CheckStake checks a stake added to a game already under way, e.g. by a split or double,
+// against the wager and loss limits. Cool-offs and sessions only stop new games.
+func (s *SafeguardStore) CheckStake(playerID string, stake int, now time.Time) error {
+	s.mu.Lock()
+	sg := s.get(playerID, now).clone()
+	s.mu.Unlock()
+	return s.checkStake(playerID, sg, stake, now)
+}
+
+// checkStake checks the wager and loss limits
+func (s *SafeguardStore) checkStake(playerID string, sg Safeguards, stake int, now time.Time) error {
+	wagered, _ := s.ledger.Activity(playerID, now.Add(-24*time.Hour))
+	if limit := sg.Limits[SafeguardDailyWager].Value; limit > 0 && wagered+stake > limit {
+		return &LimitError{Code: LimitDailyWager, Limit: limit, Message: fmt.Sprintf("Daily wager limit of %d reached", limit)}
+	}
+	if limit := sg.Limits[SafeguardTotalWager].Value; limit > 0 {
+		if total, _ := s.ledger.Activity(playerID, time.Time{}); total+stake > limit {
+			return &LimitError{Code: LimitTotalWager, Limit: limit, Message: fmt.Sprintf("Total wager limit of %d reached", limit)}
+		}
+	}
+	// The whole stake could be lost
+	losses := []struct {
+		kind   SafeguardKind
+		code   string
+		window time.Duration
+		name   string
+	}{
+		{SafeguardDailyLoss, LimitDailyLoss, 24 * time.Hour, "Daily"},
+		{SafeguardWeeklyLoss, LimitWeeklyLoss, 7 * 24 * time.Hour, "Weekly"},
+	}
+	for _, l := range losses {
+		limit := sg.Limits[l.kind].Value
+		if limit == 0 {
+			continue
+		}
+		_, net := s.ledger.Activity(playerID, now.Add(-l.window))
+		if max(0, -net)+stake > limit {
+			return &LimitError{Code: l.code, Limit: limit, Message: fmt.Sprintf("%s loss limit of %d reached", l.name, limit)}
+		}
+	}
+	return nil
+}
+
+// Played records that a player started a game, for the session length
+func (s *SafeguardStore) Played(playerID string, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sg := s.get(playerID, now)
+	if sg.SessionStartedAt.IsZero() || now.Sub(sg.LastPlayedAt) >= SessionBreak {
+		sg.SessionStartedAt = now
+	}
+	sg.LastPlayedAt = now
+}
+
+// All returns the settings of every player, by player ID
+func (s *SafeguardStore) All() []Safeguards {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	all := make([]Safeguards, 0, len(s.players))
+	for _, sg := range s.players {
+		all = append(all, sg.clone())
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].PlayerID < all[j].PlayerID })
+	return all
+}
+
+// Restore stores settings saved with All
+func (s *SafeguardStore) Restore(sg Safeguards) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sg = sg.clone()
+	s.players[sg.PlayerID] = &sg
+}
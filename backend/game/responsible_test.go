@@ -0,0 +1,121 @@
+package game
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSafeguardLimitChanges(t *testing.T) {
+	store := NewSafeguardStore(NewLedger(NewPlayerStore()))
+	now := time.Now()
+
+	store.SetLimit("p1", SafeguardDailyLoss, 100, now)
+	sg, _ := store.SetLimit("p1", SafeguardDailyLoss, 50, now)
+	if limit := sg.Limits[SafeguardDailyLoss]; limit.Value != 50 || limit.Pending != nil {
+		t.Errorf("Expected a lower limit to apply at once, got %+v", limit)
+	}
+
+	sg, _ = store.SetLimit("p1", SafeguardDailyLoss, 200, now)
+	if limit := sg.Limits[SafeguardDailyLoss]; limit.Value != 50 || limit.Pending == nil || *limit.Pending != 200 {
+		t.Errorf("Expected a higher limit to wait, got %+v", limit)
+	}
+	if limit := store.Get("p1", now.Add(SafeguardRaiseDelay)).Limits[SafeguardDailyLoss]; limit.Value != 200 || limit.Pending != nil {
+		t.Errorf("Expected the raise to apply after the delay, got %+v", limit)
+	}
+
+	store.SetLimit("p1", SafeguardDailyWager, 30, now)
+	if limit := store.Get("p1", now.Add(time.Hour)).Limits[SafeguardDailyWager]; limit.Value != 30 {
+		t.Errorf("Expected removing a limit to wait as well, got %+v", limit)
+	}
+	store.SetLimit("p1", SafeguardDailyWager, 0, now)
+	if limit := store.Get("p1", now.Add(time.Hour)).Limits[SafeguardDailyWager]; limit.Value != 30 {
+		t.Errorf("Expected removing a limit to wait as well, got %+v", limit)
+	}
+
+	if _, err := store.SetLimit("p1", "max_hands", 10, now); !errors.Is(err, ErrUnknownSafeguard) {
+		t.Errorf("Expected ErrUnknownSafeguard, got %v", err)
+	}
+}
+
+func TestSafeguardChecks(t *testing.T) {
+	players := NewPlayerStore()
+	players.Save(&Player{ID: "p1", Balance: 100})
+	ledger := NewLedger(players)
+	store := NewSafeguardStore(ledger)
+	now := time.Now()
+
+	code := func(err error) string {
+		var limit *LimitError
+		if errors.As(err, &limit) {
+			return limit.Code
+		}
+		return ""
+	}
+
+	// Lost 20 of 30 wagered
+	ledger.Debit("p1", TxBet, "g1", 30, "bet")
+	ledger.Credit("p1", TxPayout, "g1", 10, "win")
+
+	store.SetLimit("p1", SafeguardDailyLoss, 25, now)
+	if err := store.CheckStart("p1", 5, now); err != nil {
+		t.Errorf("Expected a bet within the loss limit, got %v", err)
+	}
+	if err := store.CheckStart("p1", 6, now); code(err) != LimitDailyLoss {
+		t.Errorf("Expected %s, got %v", LimitDailyLoss, err)
+	}
+
+	store.SetLimit("p1", SafeguardDailyWager, 35, now)
+	if err := store.CheckStart("p1", 10, now); code(err) != LimitDailyWager {
+		t.Errorf("Expected %s, got %v", LimitDailyWager, err)
+	}
+
+	store.SetLimit("p1", SafeguardTotalWager, 49, now)
+	ledger.Debit("p1", TxBet, "old", 15, "bet")
+	ledger.entries[len(ledger.entries)-1].Time = now.AddDate(0, -1, 0)
+	if err := store.CheckStart("p1", 1, now); code(err) == LimitDailyWager {
+		t.Errorf("Expected last month's bet to leave the daily wager alone, got %v", err)
+	}
+	if err := store.CheckStart("p1", 5, now); code(err) != LimitTotalWager {
+		t.Errorf("Expected %s, got %v", LimitTotalWager, err)
+	}
+
+	store.SetLimit("p1", SafeguardSession, 60, now)
+	store.Played("p1", now)
+	store.Played("p1", now.Add(25*time.Minute))
+	store.Played("p1", now.Add(50*time.Minute))
+	if err := store.CheckStart("p1", 1, now.Add(70*time.Minute)); code(err) != LimitSession {
+		t.Errorf("Expected %s, got %v", LimitSession, err)
+	}
+	if err := store.CheckStart("p1", 1, now.Add(80*time.Minute)); code(err) == LimitSession {
+		t.Errorf("Expected a break to end the session, got %v", err)
+	}
+
+	if _, err := store.CoolOff("p1", 60, now); !errors.Is(err, ErrInvalidPeriod) {
+		t.Errorf("Expected ErrInvalidPeriod, got %v", err)
+	}
+	store.CoolOff("p1", 7, now)
+	if sg, _ := store.CoolOff("p1", 1, now); !sg.CoolOffUntil.Equal(now.AddDate(0, 0, 7)) {
+		t.Errorf("Expected a cool-off not to be shortened, got %v", sg.CoolOffUntil)
+	}
+	if err := store.CheckStake("p1", 1, now); err != nil {
+		t.Errorf("Expected a cool-off to allow raising a game under way, got %v", err)
+	}
+	if err := store.CheckStake("p1", 10, now); code(err) != LimitDailyWager {
+		t.Errorf("Expected raises to count against %s, got %v", LimitDailyWager, err)
+	}
+	err := store.CheckStart("p1", 1, now)
+	var limit *LimitError
+	if !errors.As(err, &limit) || limit.Code != LimitCoolOff || limit.Until.IsZero() {
+		t.Errorf("Expected %s with an end, got %v", LimitCoolOff, err)
+	}
+
+	if err := store.CheckPlay("p1", now); code(err) != LimitCoolOff {
+		t.Errorf("Expected %s for play without a stake, got %v", LimitCoolOff, err)
+	}
+
+	store.SelfExclude("p1", 180, now)
+	if err := store.CheckStart("p1", 1, now.AddDate(0, 0, 30)); code(err) != LimitSelfExcluded {
+		t.Errorf("Expected %s, got %v", LimitSelfExcluded, err)
+	}
+}
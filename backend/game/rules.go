@@ -0,0 +1,380 @@
+package game
+
+import "fmt"
+
+// Variant names a rule set games can be dealt under
+type Variant string
+
+const (
+	VariantClassic   Variant = "classic"
+	VariantSpanish21 Variant = "spanish21"
+	VariantSwitch    Variant = "switch"
+	VariantFreeBet   Variant = "freebet"
+	VariantDoubleExp Variant = "double_exposure"
+	VariantENHC      Variant = "enhc"
+	VariantENHCOBO   Variant = "enhc_obo"
+	VariantPontoon   Variant = "pontoon"
+)
+
+// Rules are the table rules games are dealt under
+type Rules struct {
+	Variant          Variant `json:"variant"`
+	DealerHitsSoft17 bool    `json:"dealer_hits_soft_17"`
+	DoubleAfterSplit bool    `json:"double_after_split"`
+	Surrender        bool    `json:"surrender"`         // Late surrender
+	NoTens           bool    `json:"no_tens"`           // Deal from decks without the pip 10s
+	Player21Wins     bool    `json:"player_21_wins"`    // A player 21 beats any dealer hand
+	DoubleAnyCards   bool    `json:"double_any_cards"`  // Double on any number of cards, not just two
+	DoubleRescue     bool    `json:"double_rescue"`     // Surrender the original bet after doubling
+	BonusPayouts     bool    `json:"bonus_payouts"`     // Bonus 21s, see Bonus21
+	Switch           bool    `json:"switch"`            // Two hands whose second cards may be swapped
+	Dealer22Push     bool    `json:"dealer_22_push"`    // A dealer 22 pushes every hand but a blackjack
+	BlackjackEven    bool    `json:"blackjack_even"`    // Blackjack pays 1:1 instead of 3:2
+	FreeDoubles      bool    `json:"free_doubles"`      // The house funds doubles on hard 9, 10 and 11
+	FreeSplits       bool    `json:"free_splits"`       // The house funds splits of every pair but tens
+	HoleCardUp       bool    `json:"hole_card_up"`      // Both dealer cards are dealt face up
+	DealerWinsTies   bool    `json:"dealer_wins_ties"`  // Equal totals lose instead of pushing, blackjacks included
+	NoHoleCard       bool    `json:"no_hole_card"`      // The dealer takes the second card after the player acts
+	OriginalBetOnly  bool    `json:"original_bet_only"` // Without a hole card, a dealer blackjack only takes the original bet
+	DealerCardsDown  bool    `json:"dealer_cards_down"` // Both dealer cards are dealt face down
+	MinStand         int     `json:"min_stand"`         // Lowest total the player may stand on; 0 for any
+	FiveCardTrick    bool    `json:"five_card_trick"`   // Five cards without busting beat every hand but a natural
+	BuyCards         bool    `json:"buy_cards"`         // Cards are bought by raising the stake, instead of doubling
+	Pays2To1         bool    `json:"pays_2_to_1"`       // Naturals and five-card tricks pay 2:1
+	Charlie          int     `json:"charlie"`           // A hand of this many cards that did not bust wins at even money; 0 for none
+
+	// Terms maps the rule set's own action words to the actions they stand for
+	Terms map[string]string `json:"terms,omitempty"`
+	// Bonuses are the bonus rules in force, see BonusHand; promotions are copied in when a game starts
+	Bonuses []string `json:"bonuses,omitempty"`
+}
+
+// DefaultRules are the rules the single-player game implements
+var DefaultRules = Rules{Variant: VariantClassic, DealerHitsSoft17: true, DoubleAfterSplit: true}
+
+// Spanish21Rules deal from Spanish decks with the player-friendly rules that make up for the missing 10s
+var Spanish21Rules = Rules{
+	Variant:          VariantSpanish21,
+	DealerHitsSoft17: true,
+	DoubleAfterSplit: true,
+	Surrender:        true,
+	NoTens:           true,
+	Player21Wins:     true,
+	DoubleAnyCards:   true,
+	DoubleRescue:     true,
+	BonusPayouts:     true,
+}
+
+// SwitchRules deal two hands that may swap their second cards, paid back with a dealer 22 push and even money blackjacks
+var SwitchRules = Rules{
+	Variant:          VariantSwitch,
+	DealerHitsSoft17: true,
+	DoubleAfterSplit: true,
+	Switch:           true,
+	Dealer22Push:     true,
+	BlackjackEven:    true,
+}
+
+// FreeBetRules let the house fund doubles and splits, paid back with a dealer 22 push
+var FreeBetRules = Rules{
+	Variant:          VariantFreeBet,
+	DealerHitsSoft17: true,
+	DoubleAfterSplit: true,
+	Dealer22Push:     true,
+	FreeDoubles:      true,
+	FreeSplits:       true,
+}
+
+// DoubleExposureRules show both dealer cards but let the dealer win ties and pay blackjack even money
+var DoubleExposureRules = Rules{
+	Variant:          VariantDoubleExp,
+	DealerHitsSoft17: true,
+	DoubleAfterSplit: true,
+	HoleCardUp:       true,
+	DealerWinsTies:   true,
+	BlackjackEven:    true,
+}
+
+// ENHCRules deal the European way: no hole card, and a dealer blackjack takes doubles and splits too
+var ENHCRules = Rules{
+	Variant:          VariantENHC,
+	DealerHitsSoft17: true,
+	DoubleAfterSplit: true,
+	NoHoleCard:       true,
+}
+
+// ENHCOBORules deal without a hole card, but a dealer blackjack only takes the original bet
+var ENHCOBORules = Rules{
+	Variant:          VariantENHCOBO,
+	DealerHitsSoft17: true,
+	DoubleAfterSplit: true,
+	NoHoleCard:       true,
+	OriginalBetOnly:  true,
+}
+
+// PontoonRules deal British Pontoon: both dealer cards down, twist or stick from 15, buy cards
+// for up to the original bet, and a five-card trick; the dealer wins ties
+var PontoonRules = Rules{
+	Variant:          VariantPontoon,
+	DealerHitsSoft17: true,
+	DoubleAfterSplit: true,
+	DealerWinsTies:   true,
+	DealerCardsDown:  true,
+	MinStand:         15,
+	FiveCardTrick:    true,
+	BuyCards:         true,
+	Pays2To1:         true,
+	Terms:            map[string]string{"twist": "hit", "stick": "stand", "buy": "buy", "split": "split"},
+}
+
+// variants holds the rule set of every variant
+var variants = map[Variant]Rules{
+	VariantClassic:   DefaultRules,
+	VariantSpanish21: Spanish21Rules,
+	VariantSwitch:    SwitchRules,
+	VariantFreeBet:   FreeBetRules,
+	VariantDoubleExp: DoubleExposureRules,
+	VariantENHC:      ENHCRules,
+	VariantENHCOBO:   ENHCOBORules,
+	VariantPontoon:   PontoonRules,
+}
+
+// RulesFor returns the rule set of a variant; the empty variant is classic
+func RulesFor(v Variant) (Rules, bool) {
+	if v == "" {
+		return DefaultRules, true
+	}
+	rules, ok := variants[v]
+	return rules, ok
+}
+
+// Actions lists the actions these rules allow, in the rule set's own words
+func (r Rules) Actions() []string {
+	actions := []string{"hit", "stand", "split"}
+	if r.BuyCards {
+		actions = append(actions, "buy")
+	} else {
+		actions = append(actions, "double")
+	}
+	if r.Surrender {
+		actions = append(actions, "surrender")
+	}
+	if r.DoubleRescue {
+		actions = append(actions, "rescue")
+	}
+	if r.Switch {
+		actions = append(actions, "switch")
+	}
+
+	for i, action := range actions {
+		for term, meaning := range r.Terms {
+			if meaning == action {
+				actions[i] = term
+			}
+		}
+	}
+	return actions
+}
+
+// Action returns the action a word stands for under these rules; unknown words are returned as is
+func (r Rules) Action(word string) string {
+	if action, ok := r.Terms[word]; ok {
+		return action
+	}
+	return word
+}
+
+// Deck returns an unshuffled deck for these rules
+func (r Rules) Deck() []Card {
+	if r.NoTens {
+		return NewSpanishDeck()
+	}
+	return NewDeck()
+}
+
+// GameDecks is how many decks a single-player game is dealt from, see Rules.Deck
+const GameDecks = 1
+
+// StartingHands returns how many hands the player is dealt, each with its own bet
+func (r Rules) StartingHands() int {
+	if r.Switch {
+		return 2
+	}
+	return 1
+}
+
+// BlackjackPayout returns what a natural pays back, including the bet
+func (r Rules) BlackjackPayout(bet int) int {
+	if r.BlackjackEven {
+		return bet * 2
+	}
+	if r.Pays2To1 {
+		return bet * 3
+	}
+	return BlackjackPayout(bet)
+}
+
+// DoubleRefusal returns why the rules do not let a hand be doubled, empty if they do.
+// The engine and the strategy scorer both go by it.
+func (r Rules) DoubleRefusal(hand Hand) string {
+	switch {
+	case r.BuyCards:
+		return "Double is not allowed under these rules"
+	case hand.Doubled:
+		return "Hand is already doubled"
+	case len(hand.Cards) != 2 && !r.DoubleAnyCards:
+		return "Can only double with 2 cards"
+	case hand.Split && !r.DoubleAfterSplit:
+		return "Cannot double after a split under these rules"
+	}
+	return ""
+}
+
+// FreeDouble reports whether doubling a hand is funded by the house
+func (r Rules) FreeDouble(hand Hand) bool {
+	return r.FreeDoubles && len(hand.Cards) == 2 && !IsSoft(hand.Cards) && hand.Score >= 9 && hand.Score <= 11
+}
+
+// FreeSplit reports whether splitting a pair is funded by the house
+func (r Rules) FreeSplit(hand Hand) bool {
+	return r.FreeSplits && len(hand.Cards) == 2 && hand.Cards[0].Rank == hand.Cards[1].Rank && cardValue(hand.Cards[0]) != 10
+}
+
+// FreeStakeWinnings returns what a free bet on a finished hand pays: the winnings only, never the stake
+func (r Rules) FreeStakeWinnings(hand Hand, dealer Hand, free int) int {
+	if free == 0 {
+		return 0
+	}
+	return max(0, r.HandPayout(hand, dealer, free)-free)
+}
+
+// HandPayout returns what a finished player hand pays back against the dealer, including the returned bet
+func (r Rules) HandPayout(hand Hand, dealer Hand, bet int) int {
+	if hand.Surrendered {
+		// Half of what is riding: the original bet on a surrender, the doubling on a rescue
+		return bet / 2
+	}
+	if IsBust(hand.Score) {
+		return 0
+	}
+	if IsBlackjack(hand) && !hand.Split && !hand.Switched {
+		// Naturals are usually settled on the deal; Switch hands are played first.
+		// A 21 made by splitting or switching is an ordinary 21.
+		if IsBlackjack(dealer) {
+			if r.DealerWinsTies {
+				return 0
+			}
+			return bet
+		}
+		return r.BlackjackPayout(bet)
+	}
+	if r.IsFiveCardTrick(hand) {
+		// Beats every dealer hand but a natural
+		if IsBlackjack(dealer) {
+			return 0
+		}
+		if r.Pays2To1 {
+			return bet * 3
+		}
+		return bet * 2
+	}
+	if bonus, ok := r.BonusFor(hand); ok {
+		// Wins regardless of the dealer's total, but not against a natural
+		if IsBlackjack(dealer) {
+			return 0
+		}
+		return bonus.Payout(bet)
+	}
+	if r.Dealer22Push && dealer.Score == 22 {
+		return bet
+	}
+	if r.DealerWinsTies && hand.Score == dealer.Score {
+		return 0
+	}
+	if r.Player21Wins && hand.Score == 21 {
+		return bet * 2
+	}
+	return Payout(hand, dealer, bet)
+}
+
+// IsFiveCardTrick reports whether a hand is a five-card trick
+func (r Rules) IsFiveCardTrick(hand Hand) bool {
+	return r.FiveCardTrick && len(hand.Cards) >= 5 && !IsBust(hand.Score)
+}
+
+// HandComplete reports whether a hand that did not bust takes no more cards: a five-card trick or a Charlie
+func (r Rules) HandComplete(hand Hand) bool {
+	return r.IsFiveCardTrick(hand) || (r.Charlie > 0 && len(hand.Cards) >= r.Charlie && !IsBust(hand.Score))
+}
+
+// BonusFor returns the best-paying bonus a hand earns under these rules, the Charlie and the bonus 21s included
+func (r Rules) BonusFor(hand Hand) (BonusHand, bool) {
+	var best BonusHand
+	found := false
+	if r.Charlie > 0 && len(hand.Cards) >= r.Charlie && !IsBust(hand.Score) && !hand.Surrendered {
+		best, found = BonusHand{Rule: fmt.Sprintf("%d-card charlie", r.Charlie), Pays: 1}, true
+	}
+	// A winning 21 is only paid its bonus where 21 always wins
+	if payline, ok := r.Bonus21(hand); ok && r.Player21Wins && (!found || payline.Pays > best.Pays) {
+		best, found = BonusHand{Rule: payline.Name, Pays: payline.Pays}, true
+	}
+	for _, rule := range r.Bonuses {
+		bonus, err := ParseBonus(rule)
+		if err != nil || !bonus.Matches(hand) {
+			continue
+		}
+		if !found || bonus.Pays > best.Pays {
+			best, found = bonus, true
+		}
+	}
+	return best, found
+}
+
+// Bonus21Payline is a bonus paid on a winning 21, Pays to 1
+type Bonus21Payline struct {
+	Name string  `json:"name"`
+	Pays float64 `json:"pays"`
+}
+
+// Bonus21 returns the bonus a 21 pays under these rules: 5, 6 and 7+ card 21s, and 6-7-8 or 7-7-7
+// paying more when suited and most in spades. Doubled hands are paid at even money.
+func (r Rules) Bonus21(hand Hand) (Bonus21Payline, bool) {
+	if !r.BonusPayouts || hand.Doubled || hand.Score != 21 {
+		return Bonus21Payline{}, false
+	}
+
+	if len(hand.Cards) == 3 {
+		ranks := map[Rank]int{}
+		suited, spades := true, true
+		for _, c := range hand.Cards {
+			ranks[c.Rank]++
+			suited = suited && c.Suit == hand.Cards[0].Suit
+			spades = spades && c.Suit == Spades
+		}
+		name := ""
+		if ranks[Six] == 1 && ranks[Seven] == 1 && ranks[Eight] == 1 {
+			name = "6-7-8"
+		} else if ranks[Seven] == 3 {
+			name = "7-7-7"
+		}
+		switch {
+		case name == "":
+		case spades:
+			return Bonus21Payline{name + " spades", 3}, true
+		case suited:
+			return Bonus21Payline{name + " suited", 2}, true
+		default:
+			return Bonus21Payline{name, 1.5}, true
+		}
+	}
+
+	switch n := len(hand.Cards); {
+	case n >= 7:
+		return Bonus21Payline{"7+ card 21", 3}, true
+	case n == 6:
+		return Bonus21Payline{"6 card 21", 2}, true
+	case n == 5:
+		return Bonus21Payline{"5 card 21", 1.5}, true
+	}
+	return Bonus21Payline{}, false
+}
@@ -0,0 +1,153 @@
+package game
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSpanish21Payouts(t *testing.T) {
+	hand := func(doubled bool, ranks ...Rank) Hand {
+		h := Hand{Doubled: doubled}
+		for _, r := range ranks {
+			h.Cards = append(h.Cards, Card{Suit: Hearts, Rank: r})
+		}
+		h.Score = CalculateScore(h.Cards)
+		return h
+	}
+	dealer21 := hand(false, King, Five, Six)
+	dealer18 := hand(false, King, Eight)
+
+	tests := []struct {
+		name   string
+		rules  Rules
+		hand   Hand
+		dealer Hand
+		bet    int
+		want   int
+	}{
+		{"classic 21 pushes dealer 21", DefaultRules, hand(false, Nine, Five, Seven), dealer21, 10, 10},
+		{"player 21 beats dealer 21", Spanish21Rules, hand(false, Nine, Five, Seven), dealer21, 10, 20},
+		{"suited 6-7-8 pays 2:1", Spanish21Rules, hand(false, Six, Seven, Eight), dealer18, 10, 30},
+		{"5 card 21 pays 3:2", Spanish21Rules, hand(false, Two, Three, Four, Five, Seven), dealer18, 10, 25},
+		{"doubled 5 card 21 pays even money", Spanish21Rules, hand(true, Two, Three, Four, Five, Seven), dealer18, 20, 40},
+		{"surrender returns half", Spanish21Rules, Hand{Surrendered: true, Score: 16}, dealer18, 10, 5},
+		{"rescue returns the doubling", Spanish21Rules, Hand{Surrendered: true, Doubled: true, Score: 14}, dealer18, 20, 10},
+	}
+	for _, tt := range tests {
+		if got := tt.rules.HandPayout(tt.hand, tt.dealer, tt.bet); got != tt.want {
+			t.Errorf("%s: expected %d, got %d", tt.name, tt.want, got)
+		}
+	}
+
+	spades := Hand{Cards: []Card{{Suit: Spades, Rank: Seven}, {Suit: Spades, Rank: Seven}, {Suit: Spades, Rank: Seven}}, Score: 21}
+	if bonus, ok := Spanish21Rules.Bonus21(spades); !ok || bonus.Pays != 3 {
+		t.Errorf("Expected 7-7-7 in spades to pay 3:1, got %+v", bonus)
+	}
+	if _, ok := DefaultRules.Bonus21(spades); ok {
+		t.Error("Expected no bonus under classic rules")
+	}
+}
+
+func TestFreeBetStakes(t *testing.T) {
+	hand := func(ranks ...Rank) Hand {
+		h := Hand{}
+		for _, r := range ranks {
+			h.Cards = append(h.Cards, Card{Suit: Clubs, Rank: r})
+		}
+		h.Score = CalculateScore(h.Cards)
+		return h
+	}
+	if !FreeBetRules.FreeDouble(hand(Six, Four)) || FreeBetRules.FreeDouble(hand(Ace, Nine)) || FreeBetRules.FreeDouble(hand(Six, Six)) {
+		t.Error("Expected free doubles on hard 9 to 11 only")
+	}
+	if !FreeBetRules.FreeSplit(hand(Eight, Eight)) || FreeBetRules.FreeSplit(hand(King, King)) || DefaultRules.FreeSplit(hand(Eight, Eight)) {
+		t.Error("Expected free splits on every pair but tens under Free Bet rules only")
+	}
+
+	g := &GameState{BetAmount: 10, Hands: []Hand{{Doubled: true, FreeDouble: true}, {Split: true, FreeStake: true}}}
+	if g.Stake() != 10 {
+		t.Errorf("Expected free bets to stay out of the stake, got %d", g.Stake())
+	}
+	if real, free := g.HandStakes(g.Hands[0]); real != 10 || free != 10 {
+		t.Errorf("Expected 10 real and 10 free, got %d and %d", real, free)
+	}
+
+	win, dealer := hand(King, Queen), hand(King, Eight)
+	if got := FreeBetRules.FreeStakeWinnings(win, dealer, 10); got != 10 {
+		t.Errorf("Expected a winning free bet to pay only its winnings, got %d", got)
+	}
+	if got := FreeBetRules.FreeStakeWinnings(hand(King, Eight), dealer, 10); got != 0 {
+		t.Errorf("Expected a pushing free bet to pay nothing, got %d", got)
+	}
+}
+
+func TestDealerWinsTies(t *testing.T) {
+	twenty := Hand{Cards: []Card{{Rank: King}, {Rank: Queen}}, Score: 20}
+	natural := Hand{Cards: []Card{{Rank: King}, {Rank: Ace}}, Score: 21}
+	if got := DoubleExposureRules.HandPayout(twenty, twenty, 10); got != 0 {
+		t.Errorf("Expected the dealer to win a tie, got %d", got)
+	}
+	if got := DoubleExposureRules.HandPayout(natural, natural, 10); got != 0 {
+		t.Errorf("Expected the dealer to win tied blackjacks, got %d", got)
+	}
+	if got := DoubleExposureRules.BlackjackPayout(10); got != 20 {
+		t.Errorf("Expected blackjack to pay even money, got %d", got)
+	}
+	if got := DefaultRules.HandPayout(twenty, twenty, 10); got != 10 {
+		t.Errorf("Expected a classic tie to push, got %d", got)
+	}
+}
+
+func TestPontoonRanking(t *testing.T) {
+	trick := Hand{Cards: []Card{{Rank: Two}, {Rank: Three}, {Rank: Two}, {Rank: Four}, {Rank: Five}}, Score: 16}
+	pontoon := Hand{Cards: []Card{{Rank: Ace}, {Rank: King}}, Score: 21}
+	twentyOne := Hand{Cards: []Card{{Rank: Seven}, {Rank: Seven}, {Rank: Seven}}, Score: 21}
+	if got := PontoonRules.HandPayout(trick, twentyOne, 10); got != 30 {
+		t.Errorf("Expected a five-card trick to beat 21 at 2:1, got %d", got)
+	}
+	if got := PontoonRules.HandPayout(trick, pontoon, 10); got != 0 {
+		t.Errorf("Expected a pontoon to beat a five-card trick, got %d", got)
+	}
+	if got := PontoonRules.HandPayout(pontoon, twentyOne, 10); got != 30 {
+		t.Errorf("Expected a pontoon to pay 2:1, got %d", got)
+	}
+	if got := PontoonRules.HandPayout(twentyOne, twentyOne, 10); got != 0 {
+		t.Errorf("Expected the dealer to win a tie, got %d", got)
+	}
+	if got := DefaultRules.HandPayout(trick, twentyOne, 10); got != 0 {
+		t.Errorf("Expected five cards to be an ordinary hand in classic, got %d", got)
+	}
+	if CalculateScore(trick.Cards) != 16 {
+		t.Error("Expected the score of a five-card trick to be its total")
+	}
+}
+
+func TestActionTerms(t *testing.T) {
+	if got := PontoonRules.Actions(); !reflect.DeepEqual(got, []string{"twist", "stick", "split", "buy"}) {
+		t.Errorf("Expected Pontoon terms, got %v", got)
+	}
+	if got := DefaultRules.Actions(); !reflect.DeepEqual(got, []string{"hit", "stand", "split", "double"}) {
+		t.Errorf("Expected classic actions, got %v", got)
+	}
+	if PontoonRules.Action("twist") != "hit" || PontoonRules.Action("stick") != "stand" || DefaultRules.Action("twist") != "twist" {
+		t.Error("Expected Pontoon terms to stand for hit and stand only under Pontoon")
+	}
+}
+
+func TestDoubleRefusal(t *testing.T) {
+	split := Hand{Cards: []Card{{Rank: Five}, {Rank: Six}}, Split: true}
+	noDAS := DefaultRules
+	noDAS.DoubleAfterSplit = false
+	if refusal := noDAS.DoubleRefusal(split); refusal == "" {
+		t.Error("Expected doubling after a split to be refused without DAS")
+	}
+	if refusal := DefaultRules.DoubleRefusal(split); refusal != "" {
+		t.Errorf("Expected doubling after a split with DAS, got %q", refusal)
+	}
+
+	// The strategy scorer offers double only where the engine allows it
+	g := &GameState{Hands: []Hand{split, {Cards: []Card{{Rank: Five}, {Rank: Two}}, Split: true}}, DealerHand: Hand{Cards: []Card{{Rank: Six}}}}
+	if d, ok := ScoreDecision(g, ActionHit, noDAS); !ok || d.Best == ActionDouble || d.EV[ActionDouble] != 0 {
+		t.Errorf("Expected the scorer not to recommend doubling without DAS, got %+v", d)
+	}
+}
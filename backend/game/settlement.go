@@ -0,0 +1,74 @@
+package game
+
+import "time"
+
+// Settlement summarizes a finished game or table seat for the player aggregates
+type Settlement struct {
+	PlayerID  string     `json:"player_id"`
+	GameID    string     `json:"game_id"`
+	Time      time.Time  `json:"time"`
+	Result    GameStatus `json:"result"`
+	Staked    int        `json:"staked"` // Total bet, including splits
+	Payout    int        `json:"payout"` // Total paid back, including the stake
+	Blackjack bool       `json:"blackjack"`
+	Start     []Card     `json:"start"`     // First two player cards, before any split
+	Hands     []Hand     `json:"hands"`     // Final player hands, more than one after a split or in Switch
+	HandsWon  []bool     `json:"hands_won"` // Per hand: whether it paid back more than its stake
+	Dealer    Hand       `json:"dealer"`
+	Balance   int        `json:"balance"` // Player balance once paid out
+}
+
+// Net returns what the player won (positive) or lost (negative)
+func (s Settlement) Net() int {
+	return s.Payout - s.Staked
+}
+
+// GameSettlement summarizes a settled single-player game
+func GameSettlement(g *GameState) Settlement {
+	s := Settlement{
+		PlayerID:  g.PlayerID,
+		GameID:    g.ID,
+		Time:      g.UpdatedAt,
+		Result:    g.Status,
+		Staked:    g.Stake(),
+		Blackjack: len(g.Hands) == 1 && IsBlackjack(g.Hands[0]),
+		Dealer:    g.DealerHand.clone(),
+	}
+	for _, hand := range g.Hands {
+		s.Hands = append(s.Hands, hand.clone())
+	}
+	for _, e := range g.Events {
+		if e.Type == EventCardDealt && e.Seat == SeatPlayer && len(s.Start) < 2 {
+			s.Start = append(s.Start, *e.Card)
+		}
+	}
+	for i := len(g.Events) - 1; i >= 0; i-- {
+		if g.Events[i].Type == EventSettled {
+			s.Payout = g.Events[i].Amount
+			s.Time = g.Events[i].Time
+			break
+		}
+	}
+	// An expired game's refund includes open side bets, which are not part of the stake
+	if g.Status == StatusExpired {
+		s.Payout = min(s.Payout, s.Staked)
+	}
+	// A single hand was paid the whole payout, naturals on the deal included.
+	// Without a hole card a dealer blackjack beats every hand, whatever they hold.
+	dealerNatural := g.Rules.NoHoleCard && IsBlackjack(g.DealerHand)
+	for _, hand := range g.Hands {
+		bet, _ := g.HandStakes(hand)
+		switch {
+		case len(g.Hands) == 1:
+			s.HandsWon = append(s.HandsWon, s.Won())
+		default:
+			s.HandsWon = append(s.HandsWon, !dealerNatural && g.HandWinnings(hand) > bet)
+		}
+	}
+	return s
+}
+
+// Won reports whether the player came out ahead
+func (s Settlement) Won() bool {
+	return s.Net() > 0
+}
@@ -0,0 +1,41 @@
+package game
+
+// Shoe is a multi-deck shoe that lasts across rounds until the cut card comes out
+type Shoe struct {
+	Decks   int
+	Cards   []Card // Cards left, top first
+	CutCard int    // Reshuffle once this few cards are left
+}
+
+// NewShoe creates a shuffled shoe; the cut card leaves a quarter of it unplayed
+func NewShoe(decks int) *Shoe {
+	s := &Shoe{Decks: decks}
+	s.Shuffle()
+	return s
+}
+
+// Shuffle puts every card back and shuffles the shoe
+func (s *Shoe) Shuffle() {
+	s.Cards = Shuffle(NewDecks(s.Decks))
+	s.CutCard = len(s.Cards) / 4
+}
+
+// Draw takes the top card
+func (s *Shoe) Draw() Card {
+	return DealCard(&s.Cards)
+}
+
+// Remaining returns how many cards are left
+func (s *Shoe) Remaining() int {
+	return len(s.Cards)
+}
+
+// DecksRemaining returns the cards left in decks, as used for the true count
+func (s *Shoe) DecksRemaining() float64 {
+	return float64(len(s.Cards)) / 52
+}
+
+// NeedsShuffle reports whether the cut card has come out
+func (s *Shoe) NeedsShuffle() bool {
+	return len(s.Cards) <= s.CutCard
+}
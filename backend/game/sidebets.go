@@ -0,0 +1,319 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+var (
+	ErrUnknownSideBet    = errors.New("unknown side bet")
+	ErrSideBetNotOffered = errors.New("side bet is not offered under these rules")
+)
+
+// Payline is one winning outcome of a side bet, paying Pays to 1
+type Payline struct {
+	Outcome string `json:"outcome"`
+	Pays    int    `json:"pays"`
+}
+
+// SideBet is a wager on the cards that is paid independently of the main hand.
+// Register implementations with RegisterSideBet.
+type SideBet interface {
+	Name() string
+	Paytable() []Payline
+	// AtDeal reports whether the bet only needs the player's first two cards and the dealer upcard
+	AtDeal() bool
+	// NeedsUpcard reports whether the bet reads the dealer upcard, so it cannot be offered when both dealer cards are down
+	NeedsUpcard() bool
+	// MinDecks is the smallest shoe every payline can come from, e.g. 2 for a pair of identical cards
+	MinDecks() int
+	// Evaluate returns the winning payline, if any. player holds the first two player cards;
+	// dealer holds the upcard for bets resolved at the deal and the final hand otherwise.
+	Evaluate(player []Card, dealer []Card) (Payline, bool)
+}
+
+// SideBetResult is a side bet placed on a game and, once settled, its outcome
+type SideBetResult struct {
+	Name    string `json:"name"`
+	Amount  int    `json:"amount"`
+	Settled bool   `json:"settled"`
+	Outcome string `json:"outcome,omitempty"` // Winning payline; empty if lost
+	Payout  int    `json:"payout"`            // Including the wager; 0 if lost
+}
+
+var sideBets = map[string]SideBet{}
+
+// RegisterSideBet makes a side bet available to games under its name
+func RegisterSideBet(b SideBet) {
+	sideBets[b.Name()] = b
+}
+
+// LookupSideBet returns the registered side bet with the given name
+func LookupSideBet(name string) (SideBet, bool) {
+	b, ok := sideBets[name]
+	return b, ok
+}
+
+// SideBets returns every registered side bet, sorted by name
+func SideBets() []SideBet {
+	bets := make([]SideBet, 0, len(sideBets))
+	for _, b := range sideBets {
+		bets = append(bets, b)
+	}
+	sort.Slice(bets, func(i, j int) bool { return bets[i].Name() < bets[j].Name() })
+	return bets
+}
+
+func init() {
+	RegisterSideBet(TwentyOnePlusThree{})
+	RegisterSideBet(PerfectPairs{})
+	RegisterSideBet(LuckyLadies{})
+	RegisterSideBet(BustIt{})
+	RegisterSideBet(Push22{})
+}
+
+// PlaceSideBets turns requested wagers into unsettled results, sorted by name
+func PlaceSideBets(wagers map[string]int) ([]SideBetResult, error) {
+	var placed []SideBetResult
+	for name, amount := range wagers {
+		if _, ok := LookupSideBet(name); !ok {
+			return nil, ErrUnknownSideBet
+		}
+		if amount < 1 {
+			return nil, ErrInvalidAmount
+		}
+		placed = append(placed, SideBetResult{Name: name, Amount: amount})
+	}
+	sort.Slice(placed, func(i, j int) bool { return placed[i].Name < placed[j].Name })
+	return placed, nil
+}
+
+// CheckSideBets reports whether placed side bets can be offered on a game under rules dealt from decks decks
+func CheckSideBets(placed []SideBetResult, rules Rules, decks int) error {
+	for _, b := range placed {
+		bet, ok := LookupSideBet(b.Name)
+		if !ok {
+			return ErrUnknownSideBet
+		}
+		if bet.NeedsUpcard() && rules.DealerCardsDown {
+			return fmt.Errorf("%w: %s needs a dealer upcard", ErrSideBetNotOffered, b.Name)
+		}
+		if bet.MinDecks() > decks {
+			return fmt.Errorf("%w: %s needs at least %d decks", ErrSideBetNotOffered, b.Name, bet.MinDecks())
+		}
+	}
+	return nil
+}
+
+// OpenSideBetStake returns the total wagered on side bets that are not settled yet
+func (g *GameState) OpenSideBetStake() int {
+	stake := 0
+	for _, b := range g.SideBets {
+		if !b.Settled {
+			stake += b.Amount
+		}
+	}
+	return stake
+}
+
+// SideBetsNeedDealer reports whether an open side bet depends on the dealer's final hand
+func (g *GameState) SideBetsNeedDealer() bool {
+	for _, b := range g.SideBets {
+		if bet, ok := LookupSideBet(b.Name); ok && !b.Settled && !bet.AtDeal() {
+			return true
+		}
+	}
+	return false
+}
+
+// ResolveSideBets settles the open side bets that can be decided now and records the results.
+// Until the dealer hand is final only bets resolved at the deal are settled.
+// It returns the bets it settled.
+func (g *GameState) ResolveSideBets(final bool) []SideBetResult {
+	var start []Card
+	for _, e := range g.Events {
+		if e.Type == EventCardDealt && e.Seat == SeatPlayer && len(start) < 2 {
+			start = append(start, *e.Card)
+		}
+	}
+	if len(start) < 2 || len(g.DealerHand.Cards) == 0 {
+		return nil
+	}
+
+	results := append([]SideBetResult(nil), g.SideBets...)
+	var settled []SideBetResult
+	for i, b := range results {
+		bet, ok := LookupSideBet(b.Name)
+		if !ok || b.Settled || (!final && !bet.AtDeal()) {
+			continue
+		}
+		dealer := g.DealerHand.Cards
+		if bet.AtDeal() {
+			dealer = dealer[:1]
+		}
+		b.Settled = true
+		if line, won := bet.Evaluate(start, dealer); won {
+			b.Outcome = line.Outcome
+			b.Payout = b.Amount * (line.Pays + 1)
+		}
+		results[i] = b
+		settled = append(settled, b)
+	}
+	if len(settled) > 0 {
+		g.Record(Event{Type: EventSideBetSettled, SideBets: results})
+	}
+	return settled
+}
+
+// rankOrder is the position of each rank for straights, Ace low
+var rankOrder = map[Rank]int{
+	Ace: 1, Two: 2, Three: 3, Four: 4, Five: 5, Six: 6, Seven: 7,
+	Eight: 8, Nine: 9, Ten: 10, Jack: 11, Queen: 12, King: 13,
+}
+
+// red reports whether a card is a heart or a diamond
+func red(c Card) bool {
+	return c.Suit == Hearts || c.Suit == Diamonds
+}
+
+// bestPayline returns the first payline of a table whose outcome is in hits
+func bestPayline(table []Payline, hits map[string]bool) (Payline, bool) {
+	for _, line := range table {
+		if hits[line.Outcome] {
+			return line, true
+		}
+	}
+	return Payline{}, false
+}
+
+// TwentyOnePlusThree pays on the poker hand of the two player cards and the dealer upcard
+type TwentyOnePlusThree struct{}
+
+func (TwentyOnePlusThree) Name() string      { return "21+3" }
+func (TwentyOnePlusThree) AtDeal() bool      { return true }
+func (TwentyOnePlusThree) NeedsUpcard() bool { return true }
+func (TwentyOnePlusThree) MinDecks() int     { return 3 } // Suited trips
+
+func (TwentyOnePlusThree) Paytable() []Payline {
+	return []Payline{
+		{"suited_trips", 100}, {"straight_flush", 40}, {"three_of_a_kind", 30}, {"straight", 10}, {"flush", 5},
+	}
+}
+
+func (b TwentyOnePlusThree) Evaluate(player []Card, dealer []Card) (Payline, bool) {
+	cards := []Card{player[0], player[1], dealer[0]}
+	flush := cards[0].Suit == cards[1].Suit && cards[1].Suit == cards[2].Suit
+	trips := cards[0].Rank == cards[1].Rank && cards[1].Rank == cards[2].Rank
+
+	ranks := []int{rankOrder[cards[0].Rank], rankOrder[cards[1].Rank], rankOrder[cards[2].Rank]}
+	sort.Ints(ranks)
+	straight := ranks[1] == ranks[0]+1 && ranks[2] == ranks[1]+1
+	if ranks[0] == 1 && ranks[1] == 12 && ranks[2] == 13 {
+		straight = true // Q-K-A
+	}
+
+	return bestPayline(b.Paytable(), map[string]bool{
+		"suited_trips":    trips && flush,
+		"straight_flush":  straight && flush,
+		"three_of_a_kind": trips,
+		"straight":        straight,
+		"flush":           flush,
+	})
+}
+
+// PerfectPairs pays when the first two player cards are a pair
+type PerfectPairs struct{}
+
+func (PerfectPairs) Name() string      { return "perfect_pairs" }
+func (PerfectPairs) AtDeal() bool      { return true }
+func (PerfectPairs) NeedsUpcard() bool { return false }
+func (PerfectPairs) MinDecks() int     { return 2 } // Perfect pairs
+
+func (PerfectPairs) Paytable() []Payline {
+	return []Payline{{"perfect_pair", 25}, {"colored_pair", 12}, {"mixed_pair", 6}}
+}
+
+func (b PerfectPairs) Evaluate(player []Card, dealer []Card) (Payline, bool) {
+	pair := player[0].Rank == player[1].Rank
+	return bestPayline(b.Paytable(), map[string]bool{
+		"perfect_pair": pair && player[0].Suit == player[1].Suit,
+		"colored_pair": pair && red(player[0]) == red(player[1]),
+		"mixed_pair":   pair,
+	})
+}
+
+// LuckyLadies pays when the first two player cards total 20; the top award needs a dealer blackjack
+type LuckyLadies struct{}
+
+func (LuckyLadies) Name() string      { return "lucky_ladies" }
+func (LuckyLadies) AtDeal() bool      { return false }
+func (LuckyLadies) NeedsUpcard() bool { return false }
+func (LuckyLadies) MinDecks() int     { return 2 } // Queen of hearts pairs and matched 20s
+
+func (LuckyLadies) Paytable() []Payline {
+	return []Payline{
+		{"queen_hearts_pair_dealer_blackjack", 1000}, {"queen_hearts_pair", 200},
+		{"matched_20", 25}, {"suited_20", 10}, {"any_20", 4},
+	}
+}
+
+func (b LuckyLadies) Evaluate(player []Card, dealer []Card) (Payline, bool) {
+	twenty := CalculateScore(player) == 20
+	queenHearts := Card{Suit: Hearts, Rank: Queen}
+	qhPair := player[0] == queenHearts && player[1] == queenHearts
+	suited := player[0].Suit == player[1].Suit
+	dealerBlackjack := len(dealer) >= 2 && CalculateScore(dealer[:2]) == 21
+
+	return bestPayline(b.Paytable(), map[string]bool{
+		"queen_hearts_pair_dealer_blackjack": qhPair && dealerBlackjack,
+		"queen_hearts_pair":                  qhPair,
+		"matched_20":                         twenty && suited && player[0].Rank == player[1].Rank,
+		"suited_20":                          twenty && suited,
+		"any_20":                             twenty,
+	})
+}
+
+// BustIt pays when the dealer busts, more the more cards it took
+type BustIt struct{}
+
+func (BustIt) Name() string      { return "bust_it" }
+func (BustIt) AtDeal() bool      { return false }
+func (BustIt) NeedsUpcard() bool { return true }
+func (BustIt) MinDecks() int     { return 1 }
+
+func (BustIt) Paytable() []Payline {
+	return []Payline{
+		{"bust_8_cards", 250}, {"bust_7_cards", 100}, {"bust_6_cards", 50},
+		{"bust_5_cards", 9}, {"bust_4_cards", 2}, {"bust_3_cards", 1},
+	}
+}
+
+func (b BustIt) Evaluate(player []Card, dealer []Card) (Payline, bool) {
+	busted := IsBust(CalculateScore(dealer))
+	n := len(dealer)
+	return bestPayline(b.Paytable(), map[string]bool{
+		"bust_8_cards": busted && n >= 8,
+		"bust_7_cards": busted && n == 7,
+		"bust_6_cards": busted && n == 6,
+		"bust_5_cards": busted && n == 5,
+		"bust_4_cards": busted && n == 4,
+		"bust_3_cards": busted && n == 3,
+	})
+}
+
+// Push22 pays when the dealer ends on 22, the total that pushes under Free Bet rules
+type Push22 struct{}
+
+func (Push22) Name() string      { return "push_22" }
+func (Push22) AtDeal() bool      { return false }
+func (Push22) NeedsUpcard() bool { return false }
+func (Push22) MinDecks() int     { return 1 }
+
+func (Push22) Paytable() []Payline {
+	return []Payline{{"dealer_22", 11}}
+}
+
+func (b Push22) Evaluate(player []Card, dealer []Card) (Payline, bool) {
+	return bestPayline(b.Paytable(), map[string]bool{"dealer_22": CalculateScore(dealer) == 22})
+}
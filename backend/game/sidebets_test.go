@@ -0,0 +1,111 @@
+package game
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSideBetPaytables(t *testing.T) {
+	c := func(rank Rank, suit Suit) Card { return Card{Suit: suit, Rank: rank} }
+	tests := []struct {
+		name   string
+		bet    SideBet
+		player []Card
+		dealer []Card
+		want   string // Empty if the bet loses
+	}{
+		{"suited trips", TwentyOnePlusThree{}, []Card{c(Seven, Clubs), c(Seven, Clubs)}, []Card{c(Seven, Clubs)}, "suited_trips"},
+		{"straight flush", TwentyOnePlusThree{}, []Card{c(Nine, Hearts), c(Jack, Hearts)}, []Card{c(Ten, Hearts)}, "straight_flush"},
+		{"ace high straight", TwentyOnePlusThree{}, []Card{c(King, Spades), c(Ace, Hearts)}, []Card{c(Queen, Clubs)}, "straight"},
+		{"flush", TwentyOnePlusThree{}, []Card{c(Two, Diamonds), c(King, Diamonds)}, []Card{c(Six, Diamonds)}, "flush"},
+		{"no poker hand", TwentyOnePlusThree{}, []Card{c(Two, Diamonds), c(King, Spades)}, []Card{c(Six, Diamonds)}, ""},
+		{"perfect pair", PerfectPairs{}, []Card{c(Eight, Spades), c(Eight, Spades)}, nil, "perfect_pair"},
+		{"colored pair", PerfectPairs{}, []Card{c(Eight, Hearts), c(Eight, Diamonds)}, nil, "colored_pair"},
+		{"mixed pair", PerfectPairs{}, []Card{c(Eight, Hearts), c(Eight, Clubs)}, nil, "mixed_pair"},
+		{"queen of hearts pair with dealer blackjack", LuckyLadies{}, []Card{c(Queen, Hearts), c(Queen, Hearts)}, []Card{c(Ace, Clubs), c(King, Clubs)}, "queen_hearts_pair_dealer_blackjack"},
+		{"suited 20", LuckyLadies{}, []Card{c(Jack, Clubs), c(Ten, Clubs)}, []Card{c(Two, Clubs), c(King, Clubs)}, "suited_20"},
+		{"any 20", LuckyLadies{}, []Card{c(Ace, Clubs), c(Nine, Hearts)}, []Card{c(Two, Clubs), c(King, Clubs)}, "any_20"},
+		{"dealer busts with 4 cards", BustIt{}, nil, []Card{c(Two, Clubs), c(Four, Clubs), c(Ten, Clubs), c(Nine, Clubs)}, "bust_4_cards"},
+		{"dealer stands", BustIt{}, nil, []Card{c(Ten, Clubs), c(Eight, Clubs)}, ""},
+	}
+	for _, tt := range tests {
+		line, won := tt.bet.Evaluate(tt.player, tt.dealer)
+		if won != (tt.want != "") || line.Outcome != tt.want {
+			t.Errorf("%s: expected %q, got %q (won %v)", tt.name, tt.want, line.Outcome, won)
+		}
+	}
+}
+
+func TestResolveSideBets(t *testing.T) {
+	placed, err := PlaceSideBets(map[string]int{"perfect_pairs": 5, "bust_it": 2})
+	if err != nil {
+		t.Fatalf("place side bets: %v", err)
+	}
+	if _, err := PlaceSideBets(map[string]int{"insurance": 5}); err != ErrUnknownSideBet {
+		t.Errorf("expected ErrUnknownSideBet, got %v", err)
+	}
+
+	g := &GameState{}
+	g.Record(Event{Type: EventBetPlaced, GameID: "g1", PlayerID: "p1", Amount: 10, SideBets: placed})
+	g.Record(Event{Type: EventDeckShuffled, Deck: []Card{
+		{Suit: Hearts, Rank: Eight}, {Suit: Hearts, Rank: Eight}, {Suit: Clubs, Rank: Six}, {Suit: Clubs, Rank: Ten},
+		{Suit: Spades, Rank: King},
+	}})
+	g.Deal(SeatPlayer, 0, true)
+	g.Deal(SeatPlayer, 0, true)
+	g.Deal(SeatDealer, 0, true)
+	g.Deal(SeatDealer, 0, false)
+
+	settled := g.ResolveSideBets(false)
+	if len(settled) != 1 || settled[0].Name != "perfect_pairs" || settled[0].Payout != 5*26 {
+		t.Fatalf("expected only the perfect pair to settle at the deal, got %+v", settled)
+	}
+	if !g.SideBetsNeedDealer() || g.OpenSideBetStake() != 2 {
+		t.Fatalf("expected bust_it to wait for the dealer, got %+v", g.SideBets)
+	}
+
+	g.DealerDraw()
+	settled = g.ResolveSideBets(true)
+	if len(settled) != 1 || settled[0].Outcome != "bust_3_cards" || settled[0].Payout != 4 {
+		t.Errorf("expected bust_it to pay 1:1 on a 3 card bust, got %+v", settled)
+	}
+
+	// Side bet results are part of the event log
+	replayed := Replay(g.Events)
+	if len(replayed.SideBets) != 2 || !replayed.SideBets[0].Settled || !replayed.SideBets[1].Settled {
+		t.Errorf("expected settled side bets after replay, got %+v", replayed.SideBets)
+	}
+}
+
+func TestCheckSideBets(t *testing.T) {
+	place := func(names ...string) []SideBetResult {
+		var placed []SideBetResult
+		for _, name := range names {
+			placed = append(placed, SideBetResult{Name: name, Amount: 5})
+		}
+		return placed
+	}
+	tests := []struct {
+		name   string
+		placed []SideBetResult
+		rules  Rules
+		decks  int
+		ok     bool
+	}{
+		{"one deck", place("bust_it", "push_22"), DefaultRules, 1, true},
+		{"identical cards from one deck", place("perfect_pairs"), DefaultRules, 1, false},
+		{"suited trips from two decks", place("21+3"), DefaultRules, 2, false},
+		{"shoe", place("21+3", "perfect_pairs", "lucky_ladies"), DefaultRules, 6, true},
+		{"no upcard", place("bust_it"), PontoonRules, 1, false},
+		{"player cards only without an upcard", place("perfect_pairs", "lucky_ladies"), PontoonRules, 6, true},
+	}
+	for _, tt := range tests {
+		err := CheckSideBets(tt.placed, tt.rules, tt.decks)
+		if tt.ok && err != nil {
+			t.Errorf("%s: expected the side bets to be offered, got %v", tt.name, err)
+		}
+		if !tt.ok && !errors.Is(err, ErrSideBetNotOffered) {
+			t.Errorf("%s: expected ErrSideBetNotOffered, got %v", tt.name, err)
+		}
+	}
+}
@@ -0,0 +1,264 @@
+package game
+
+import (
+	"encoding/gob"
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// SnapshotFile is the name of the snapshot inside the data directory
+const SnapshotFile = "snapshot.gob"
+
+// Stores groups the in-memory stores that are persisted in a snapshot
+type Stores struct {
+	Games        *GameStore
+	Players      *PlayerStore
+	History      *HistoryStore
+	Ledger       *Ledger
+	Tables       *TableStore
+	Tournaments  *TournamentStore
+	Leaderboards *Leaderboards
+	Achievements *Achievements
+	Stats        *StatsStore
+	Decisions    *DecisionStore
+	Trainers     *TrainerStore
+	Promotions   *PromotionStore
+	Bankroll     *Bankroll
+	Safeguards   *SafeguardStore
+}
+
+// Snapshot is the serialized form of the in-memory stores.
+// It is encoded with gob instead of JSON so that fields hidden from the API
+// (e.g. GameState.Deck) survive a restart.
+type Snapshot struct {
+	TakenAt      time.Time
+	Games        []*GameState
+	Players      []*Player
+	History      []*GameState
+	Transactions []Transaction
+	Tables       []TableState
+	Tournaments  []TournamentState
+	Leaderboards LeaderboardState
+	Achievements AchievementProgress
+	Stats        []PlayerStats
+	Decisions    []PlayerDecisions
+	Trainers     []TrainerState
+	Promotions   []Promotion
+	Grants       []Grant
+	Safeguards   []Safeguards
+}
+
+// TakeSnapshot copies the current contents of the stores.
+// The stores are copied one after the other while requests are still handled, so the
+// snapshot is not a single cut: settlements post their payouts under the game or table
+// lock before the status changes, and the ledger is captured last, so a game copied as
+// settled always has its transactions. A game that moved on after it was copied can
+// have transactions its copy does not show yet, such as the bets of a game started
+// while the stores were copied or the payout of a game copied in play.
+func TakeSnapshot(stores Stores) *Snapshot {
+	var tables []TableState
+	for _, t := range stores.Tables.All() {
+		tables = append(tables, t.persistentState())
+	}
+	var trainers []TrainerState
+	for _, t := range stores.Trainers.All() {
+		trainers = append(trainers, t.persistentState())
+	}
+	var tournaments []TournamentState
+	for _, t := range stores.Tournaments.All() {
+		tournaments = append(tournaments, t.State())
+	}
+	// Games change while requests are handled, so each is copied under its lock
+	copyGames := func(games []*GameState) []*GameState {
+		copies := make([]*GameState, 0, len(games))
+		for _, g := range games {
+			copies = append(copies, g.Copy())
+		}
+		return copies
+	}
+	snap := &Snapshot{
+		TakenAt:      time.Now(),
+		Games:        copyGames(stores.Games.All()),
+		History:      copyGames(stores.History.All()),
+		Tables:       tables,
+		Tournaments:  tournaments,
+		Leaderboards: stores.Leaderboards.State(),
+		Achievements: stores.Achievements.Progress(),
+		Stats:        stores.Stats.All(),
+		Decisions:    stores.Decisions.All(),
+		Trainers:     trainers,
+		Promotions:   stores.Promotions.All(),
+		Grants:       stores.Bankroll.All(),
+		Safeguards:   stores.Safeguards.All(),
+	}
+	// Balances and transactions are taken together, and last, so that they reconcile on load
+	// and every payout of a game copied as settled is included
+	snap.Players, snap.Transactions = stores.Ledger.Capture()
+	return snap
+}
+
+// Restore loads the snapshot contents into the stores.
+// It returns the IDs of players whose balance does not match their transactions.
+func (s *Snapshot) Restore(stores Stores) []string {
+	for _, g := range s.Games {
+		stores.Games.Restore(g)
+	}
+	for _, p := range s.Players {
+		stores.Players.Save(p)
+	}
+	for _, g := range s.History {
+		stores.History.Save(g)
+	}
+	mismatched := stores.Ledger.Restore(s.Transactions)
+	for _, state := range s.Tables {
+		stores.Tables.Restore(state)
+	}
+	for _, state := range s.Tournaments {
+		stores.Tournaments.Restore(state)
+	}
+	stores.Leaderboards.Restore(s.Leaderboards)
+	stores.Achievements.Restore(s.Achievements)
+	for _, stats := range s.Stats {
+		stores.Stats.Restore(stats)
+	}
+	for _, decisions := range s.Decisions {
+		stores.Decisions.Restore(decisions)
+	}
+	for _, state := range s.Trainers {
+		stores.Trainers.Restore(state)
+	}
+	for _, p := range s.Promotions {
+		stores.Promotions.Save(p)
+	}
+	stores.Bankroll.Restore(s.Grants)
+	for _, sg := range s.Safeguards {
+		stores.Safeguards.Restore(sg)
+	}
+	return mismatched
+}
+
+// WriteSnapshot atomically writes a snapshot to dir.
+// The data is written to a temporary file first and renamed over the previous
+// snapshot, so a crash mid-write never leaves a truncated file behind.
+func WriteSnapshot(dir string, snap *Snapshot) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, SnapshotFile+".*.tmp")
+	if err != nil {
+		return err
+	}
+	// Cleanup is a no-op once the rename succeeded
+	defer os.Remove(tmp.Name())
+
+	if err := gob.NewEncoder(tmp).Encode(snap); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), filepath.Join(dir, SnapshotFile))
+}
+
+// ReadSnapshot reads the latest snapshot from dir.
+// It returns an error satisfying errors.Is(err, os.ErrNotExist) if there is none.
+func ReadSnapshot(dir string) (*Snapshot, error) {
+	f, err := os.Open(filepath.Join(dir, SnapshotFile))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var snap Snapshot
+	if err := gob.NewDecoder(f).Decode(&snap); err != nil {
+		return nil, err
+	}
+	return &snap, nil
+}
+
+// Snapshotter periodically writes snapshots of the stores to a data directory
+type Snapshotter struct {
+	dir      string
+	interval time.Duration
+	stores   Stores
+
+	stop    chan struct{}
+	done    chan struct{}
+	once    sync.Once
+	started bool
+}
+
+// NewSnapshotter creates a Snapshotter writing to dir every interval
+func NewSnapshotter(dir string, interval time.Duration, stores Stores) *Snapshotter {
+	return &Snapshotter{
+		dir:      dir,
+		interval: interval,
+		stores:   stores,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// Load restores the latest snapshot, if any, into the stores
+func (s *Snapshotter) Load() error {
+	snap, err := ReadSnapshot(s.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, playerID := range snap.Restore(s.stores) {
+		balance, ledgerBalance, _ := s.stores.Ledger.Reconcile(playerID)
+		log.Printf("snapshot: player %s has balance %d but their transactions add up to %d", playerID, balance, ledgerBalance)
+	}
+	log.Printf("snapshot: restored %d games and %d players from %s", len(snap.Games), len(snap.Players), snap.TakenAt.Format(time.RFC3339))
+	return nil
+}
+
+// Save writes a snapshot immediately
+func (s *Snapshotter) Save() error {
+	return WriteSnapshot(s.dir, TakeSnapshot(s.stores))
+}
+
+// Start runs the periodic snapshot loop in the background
+func (s *Snapshotter) Start() {
+	s.started = true
+	go func() {
+		defer close(s.done)
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := s.Save(); err != nil {
+					log.Printf("snapshot: %v", err)
+				}
+			case <-s.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop ends the periodic loop and writes a final snapshot
+func (s *Snapshotter) Stop() error {
+	s.once.Do(func() {
+		close(s.stop)
+		if s.started {
+			<-s.done
+		}
+	})
+	return s.Save()
+}
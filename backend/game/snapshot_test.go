@@ -0,0 +1,97 @@
+package game
+
+import "testing"
+
+// newStores creates empty stores around the given games and players
+func newStores(games *GameStore, players *PlayerStore) Stores {
+	return Stores{
+		Games:        games,
+		Players:      players,
+		History:      NewHistoryStore(),
+		Ledger:       NewLedger(players),
+		Tables:       NewTableStore(nil, nil),
+		Tournaments:  NewTournamentStore(nil),
+		Leaderboards: NewLeaderboards(),
+		Achievements: NewAchievements(players, nil),
+		Stats:        NewStatsStore(),
+		Decisions:    NewDecisionStore(),
+		Trainers:     NewTrainerStore(),
+		Promotions:   NewPromotionStore(),
+		Bankroll:     NewBankroll(NewLedger(players), BankrollConfig{Policy: BankrollAdmin}),
+		Safeguards:   NewSafeguardStore(NewLedger(players)),
+	}
+}
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+
+	games := NewGameStore()
+	players := NewPlayerStore()
+	deck := Shuffle(NewDeck())
+	games.Save(&GameState{ID: "g1", PlayerID: "p1", BetAmount: 5, Deck: deck, Status: StatusPlayerTurn})
+	players.Save(&Player{ID: "p1"})
+	stores := newStores(games, players)
+	stores.Ledger.Credit("p1", TxBonus, "", 100, "welcome bonus")
+	stores.Ledger.Debit("p1", TxBet, "g1", 5, "bet")
+
+	if err := WriteSnapshot(dir, TakeSnapshot(stores)); err != nil {
+		t.Fatalf("write snapshot: %v", err)
+	}
+
+	snap, err := ReadSnapshot(dir)
+	if err != nil {
+		t.Fatalf("read snapshot: %v", err)
+	}
+
+	restoredGames := NewGameStore()
+	restoredPlayers := NewPlayerStore()
+	if mismatched := snap.Restore(newStores(restoredGames, restoredPlayers)); len(mismatched) != 0 {
+		t.Errorf("expected balances to match the transactions, got mismatches for %v", mismatched)
+	}
+
+	g, ok := restoredGames.Get("g1")
+	if !ok {
+		t.Fatal("expected game g1 to be restored")
+	}
+	// The deck is hidden from JSON but must survive a snapshot
+	if len(g.Deck) != len(deck) || g.Deck[0] != deck[0] {
+		t.Errorf("expected deck to be restored, got %d cards", len(g.Deck))
+	}
+	if p, ok := restoredPlayers.Get("p1"); !ok || p.Balance != 95 {
+		t.Errorf("expected player p1 with balance 95, got %+v", p)
+	}
+
+	// A balance that does not match the transactions is reported, not papered over
+	snap.Players[0].Balance = 500
+	restoredPlayers = NewPlayerStore()
+	restored := newStores(NewGameStore(), restoredPlayers)
+	if mismatched := snap.Restore(restored); len(mismatched) != 1 || mismatched[0] != "p1" {
+		t.Errorf("expected p1 to be reported, got %v", mismatched)
+	}
+	if len(restored.Ledger.Transactions("p1")) != 2 {
+		t.Errorf("expected no entries to be added, got %+v", restored.Ledger.Transactions("p1"))
+	}
+}
+
+func TestSnapshotterLoadWithoutSnapshot(t *testing.T) {
+	players := NewPlayerStore()
+	s := NewSnapshotter(t.TempDir(), 0, newStores(NewGameStore(), players))
+	if err := s.Load(); err != nil {
+		t.Errorf("expected no error when no snapshot exists, got %v", err)
+	}
+}
+
+func TestSnapshotCopiesGames(t *testing.T) {
+	games := NewGameStore()
+	g := &GameState{}
+	g.Record(Event{Type: EventBetPlaced, GameID: "g1", PlayerID: "p1", Amount: 5})
+	g.Record(Event{Type: EventDeckShuffled, Deck: Shuffle(NewDeck())})
+	g.Deal(SeatPlayer, 0, true)
+	games.Save(g)
+
+	snap := TakeSnapshot(newStores(games, NewPlayerStore()))
+	g.Deal(SeatPlayer, 0, true)
+	if saved := snap.Games[0]; saved == g || len(saved.Events) != 3 || len(saved.Hands[0].Cards) != 1 {
+		t.Errorf("expected the snapshot to hold a copy of the game as it was, got %+v", saved)
+	}
+}
@@ -0,0 +1,194 @@
+package game
+
+import (
+	"fmt"
+	"sync"
+)
+
+// ResultBucket counts results for one starting hand against one dealer upcard
+type ResultBucket struct {
+	Hands  int `json:"hands"`
+	Wins   int `json:"wins"`
+	Losses int `json:"losses"`
+	Pushes int `json:"pushes"`
+	Net    int `json:"net"`
+}
+
+func (b *ResultBucket) add(s Settlement) {
+	b.Hands++
+	switch {
+	case s.Net() > 0:
+		b.Wins++
+	case s.Net() < 0:
+		b.Losses++
+	default:
+		b.Pushes++
+	}
+	b.Net += s.Net()
+}
+
+// PlayerStats aggregates every settlement of a player
+type PlayerStats struct {
+	PlayerID          string  `json:"player_id"`
+	HandsPlayed       int     `json:"hands_played"`
+	Wins              int     `json:"wins"`
+	Losses            int     `json:"losses"`
+	Pushes            int     `json:"pushes"`
+	Blackjacks        int     `json:"blackjacks"`
+	Busts             int     `json:"busts"`  // Hands over 21, counted per hand after a split
+	Splits            int     `json:"splits"` // Pairs split; Switch hands are not splits
+	Doubles           int     `json:"doubles"`
+	TotalBet          int     `json:"total_bet"`
+	AverageBet        float64 `json:"average_bet"`
+	Net               int     `json:"net"`
+	CurrentStreak     int     `json:"current_streak"` // Positive for wins in a row, negative for losses
+	LongestWinStreak  int     `json:"longest_win_streak"`
+	LongestLossStreak int     `json:"longest_loss_streak"`
+
+	// ByStartingHand buckets results by starting hand (e.g. "hard 16") and dealer upcard (e.g. "10")
+	ByStartingHand map[string]map[string]*ResultBucket `json:"by_starting_hand"`
+}
+
+// add updates the aggregates with one settlement
+func (p *PlayerStats) add(s Settlement) {
+	p.HandsPlayed++
+	switch {
+	case s.Net() > 0:
+		p.Wins++
+		p.CurrentStreak = max(p.CurrentStreak, 0) + 1
+	case s.Net() < 0:
+		p.Losses++
+		p.CurrentStreak = min(p.CurrentStreak, 0) - 1
+	default:
+		// A push neither extends nor breaks a streak
+		p.Pushes++
+	}
+	p.LongestWinStreak = max(p.LongestWinStreak, p.CurrentStreak)
+	p.LongestLossStreak = max(p.LongestLossStreak, -p.CurrentStreak)
+
+	if s.Blackjack {
+		p.Blackjacks++
+	}
+	// Each split turns one hand into two split hands, which cannot be split again
+	splitHands := 0
+	for _, hand := range s.Hands {
+		if hand.Split {
+			splitHands++
+		}
+	}
+	p.Splits += splitHands / 2
+	for _, hand := range s.Hands {
+		if IsBust(hand.Score) {
+			p.Busts++
+		}
+		if hand.Doubled {
+			p.Doubles++
+		}
+	}
+	p.TotalBet += s.Staked
+	p.AverageBet = float64(p.TotalBet) / float64(p.HandsPlayed)
+	p.Net += s.Net()
+
+	if len(s.Start) == 2 && len(s.Dealer.Cards) > 0 {
+		start, upcard := StartingHandLabel(s.Start), UpcardLabel(s.Dealer.Cards[0])
+		if p.ByStartingHand == nil {
+			p.ByStartingHand = make(map[string]map[string]*ResultBucket)
+		}
+		if p.ByStartingHand[start] == nil {
+			p.ByStartingHand[start] = make(map[string]*ResultBucket)
+		}
+		if p.ByStartingHand[start][upcard] == nil {
+			p.ByStartingHand[start][upcard] = &ResultBucket{}
+		}
+		p.ByStartingHand[start][upcard].add(s)
+	}
+}
+
+// clone deep-copies the stats so callers can't race with later updates
+func (p *PlayerStats) clone() PlayerStats {
+	c := *p
+	c.ByStartingHand = make(map[string]map[string]*ResultBucket, len(p.ByStartingHand))
+	for start, byUpcard := range p.ByStartingHand {
+		c.ByStartingHand[start] = make(map[string]*ResultBucket, len(byUpcard))
+		for upcard, bucket := range byUpcard {
+			b := *bucket
+			c.ByStartingHand[start][upcard] = &b
+		}
+	}
+	return c
+}
+
+// StartingHandLabel names a two-card starting hand, e.g. "pair 8", "soft 17", "hard 12" or "blackjack"
+func StartingHandLabel(cards []Card) string {
+	score := CalculateScore(cards)
+	switch {
+	case score == 21:
+		return "blackjack"
+	case cards[0].Rank == cards[1].Rank:
+		return "pair " + string(cards[0].Rank)
+	case IsSoft(cards):
+		return fmt.Sprintf("soft %d", score)
+	}
+	return fmt.Sprintf("hard %d", score)
+}
+
+// UpcardLabel names a dealer upcard; every ten-valued card is "10"
+func UpcardLabel(card Card) string {
+	if card.Rank == Ace {
+		return string(Ace)
+	}
+	return fmt.Sprint(CalculateScore([]Card{card}))
+}
+
+// StatsStore keeps the statistics of every player, updated on each settlement.
+// All methods are safe for concurrent use.
+type StatsStore struct {
+	mu    sync.RWMutex
+	stats map[string]*PlayerStats
+}
+
+// NewStatsStore creates an empty StatsStore
+func NewStatsStore() *StatsStore {
+	return &StatsStore{stats: make(map[string]*PlayerStats)}
+}
+
+// Record adds a settlement to the player's statistics
+func (s *StatsStore) Record(settlement Settlement) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stats, ok := s.stats[settlement.PlayerID]
+	if !ok {
+		stats = &PlayerStats{PlayerID: settlement.PlayerID}
+		s.stats[settlement.PlayerID] = stats
+	}
+	stats.add(settlement)
+}
+
+// Get returns a copy of a player's statistics
+func (s *StatsStore) Get(playerID string) (PlayerStats, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	stats, ok := s.stats[playerID]
+	if !ok {
+		return PlayerStats{}, false
+	}
+	return stats.clone(), true
+}
+
+// All returns a copy of every player's statistics
+func (s *StatsStore) All() []PlayerStats {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	all := make([]PlayerStats, 0, len(s.stats))
+	for _, stats := range s.stats {
+		all = append(all, stats.clone())
+	}
+	return all
+}
+
+// Restore stores previously saved statistics
+func (s *StatsStore) Restore(stats PlayerStats) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stats[stats.PlayerID] = &stats
+}
@@ -0,0 +1,67 @@
+package game
+
+import "testing"
+
+func TestStartingHandLabel(t *testing.T) {
+	tests := []struct {
+		cards []Card
+		want  string
+	}{
+		{[]Card{{Rank: Ten}, {Rank: Six}}, "hard 16"},
+		{[]Card{{Rank: Ace}, {Rank: Six}}, "soft 17"},
+		{[]Card{{Rank: Eight}, {Rank: Eight}}, "pair 8"},
+		{[]Card{{Rank: Ace}, {Rank: Ace}}, "pair A"},
+		{[]Card{{Rank: Ace}, {Rank: King}}, "blackjack"},
+	}
+	for _, tt := range tests {
+		if got := StartingHandLabel(tt.cards); got != tt.want {
+			t.Errorf("%v: expected %q, got %q", tt.cards, tt.want, got)
+		}
+	}
+	if got := UpcardLabel(Card{Rank: Queen}); got != "10" {
+		t.Errorf("Expected a queen upcard to be \"10\", got %q", got)
+	}
+}
+
+func TestStatsStore(t *testing.T) {
+	s := NewStatsStore()
+	start := []Card{{Rank: Ten}, {Rank: Six}}
+	dealer := Hand{Cards: []Card{{Rank: King}, {Rank: Seven}}, Score: 17}
+	win := Settlement{PlayerID: "p1", Staked: 10, Payout: 20, Start: start, Dealer: dealer, Hands: []Hand{{Score: 20}}}
+	loss := Settlement{PlayerID: "p1", Staked: 20, Start: start, Dealer: dealer, Hands: []Hand{{Score: 26, Doubled: true}}}
+	push := Settlement{PlayerID: "p1", Staked: 10, Payout: 10, Start: start, Dealer: dealer, Hands: []Hand{{Score: 17}}}
+
+	for _, settlement := range []Settlement{win, win, push, win, loss, loss} {
+		s.Record(settlement)
+	}
+
+	stats, ok := s.Get("p1")
+	if !ok {
+		t.Fatal("Expected stats for p1")
+	}
+	if stats.HandsPlayed != 6 || stats.Wins != 3 || stats.Losses != 2 || stats.Pushes != 1 {
+		t.Errorf("Unexpected counts: %+v", stats)
+	}
+	if stats.LongestWinStreak != 3 || stats.LongestLossStreak != 2 || stats.CurrentStreak != -2 {
+		t.Errorf("Unexpected streaks: win %d, loss %d, current %d", stats.LongestWinStreak, stats.LongestLossStreak, stats.CurrentStreak)
+	}
+	if stats.Busts != 2 || stats.Doubles != 2 || stats.Net != 30-40 || stats.AverageBet != 80.0/6 {
+		t.Errorf("Unexpected totals: %+v", stats)
+	}
+	if b := stats.ByStartingHand["hard 16"]["10"]; b == nil || b.Hands != 6 || b.Net != -10 {
+		t.Errorf("Expected every hand in the hard 16 vs 10 bucket, got %+v", b)
+	}
+
+	// Blackjack Switch deals two hands without a split; splitting both counts twice
+	s.Record(Settlement{PlayerID: "p2", Staked: 20, Hands: []Hand{{Score: 18}, {Score: 19}}})
+	s.Record(Settlement{PlayerID: "p2", Staked: 40, Hands: []Hand{{Score: 18, Split: true}, {Score: 19, Split: true}, {Score: 20, Split: true}, {Score: 17, Split: true}}})
+	if switched, _ := s.Get("p2"); switched.Splits != 2 {
+		t.Errorf("Expected 2 splits, got %d", switched.Splits)
+	}
+
+	// Copies are not affected by later settlements
+	s.Record(win)
+	if stats.ByStartingHand["hard 16"]["10"].Hands != 6 {
+		t.Errorf("Expected Get to return a copy")
+	}
+}
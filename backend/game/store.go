@@ -2,6 +2,7 @@ package game
 
 import (
 	"sync"
+	"time"
 )
 
 // GameStore is a thread-safe in-memory store for games
@@ -17,8 +18,19 @@ func NewGameStore() *GameStore {
 	}
 }
 
-// Save stores a game state
+// Save stores a game state and marks it as recently active
 func (s *GameStore) Save(game *GameState) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	game.UpdatedAt = time.Now()
+	if game.CreatedAt.IsZero() {
+		game.CreatedAt = game.UpdatedAt
+	}
+	s.games[game.ID] = game
+}
+
+// Restore stores a game state without touching its timestamps
+func (s *GameStore) Restore(game *GameState) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	s.games[game.ID] = game
@@ -38,3 +50,35 @@ func (s *GameStore) Delete(id string) {
 	defer s.mu.Unlock()
 	delete(s.games, id)
 }
+
+// All returns every stored game
+func (s *GameStore) All() []*GameState {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	games := make([]*GameState, 0, len(s.games))
+	for _, game := range s.games {
+		games = append(games, game)
+	}
+	return games
+}
+
+// Exposure returns what a player has riding on unfinished cash games, side bets included.
+// Each game is read under its lock, except held: a game the caller has locked already.
+func (s *GameStore) Exposure(playerID string, held *GameState) int {
+	exposure := 0
+	for _, g := range s.All() {
+		if g.PlayerID != playerID {
+			continue
+		}
+		if g != held {
+			g.Lock()
+		}
+		if g.TournamentID == "" && !g.IsFinished() {
+			exposure += g.Stake() + g.OpenSideBetStake()
+		}
+		if g != held {
+			g.Unlock()
+		}
+	}
+	return exposure
+}
@@ -0,0 +1,350 @@
+package game
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Decision actions scored against basic strategy
+const (
+	ActionHit       = "hit"
+	ActionStand     = "stand"
+	ActionDouble    = "double"
+	ActionSplit     = "split"
+	ActionSurrender = "surrender"
+)
+
+// HandCategory groups decisions for the accuracy report
+type HandCategory string
+
+const (
+	CategoryHard HandCategory = "hard"
+	CategorySoft HandCategory = "soft"
+	CategoryPair HandCategory = "pairs"
+)
+
+// Decision is a player action compared with the basic strategy move.
+// EVs are in units of the initial bet, drawing from the game's decks less the cards in view.
+type Decision struct {
+	PlayerID  string             `json:"player_id"`
+	GameID    string             `json:"game_id"`
+	Time      time.Time          `json:"time"`
+	HandIndex int                `json:"hand_index"`
+	Category  HandCategory       `json:"category"`
+	Hand      string             `json:"hand"`   // e.g. "hard 16"
+	Upcard    string             `json:"upcard"` // e.g. "10"
+	Action    string             `json:"action"`
+	Best      string             `json:"best"`
+	EV        map[string]float64 `json:"ev"`   // EV of every allowed action
+	Cost      float64            `json:"cost"` // EV given up, in units of the bet; zero when correct
+	Bet       int                `json:"bet"`
+}
+
+// Correct reports whether the player made the basic strategy move
+func (d Decision) Correct() bool {
+	return d.Cost < 1e-9
+}
+
+// UnscoredReason returns why decisions under rules are not scored, or "" if they are.
+// The calculator models the deck count, H17, DAS, surrender, a missing hole card and the Charlie;
+// rules that change the hands or their payouts otherwise are not scored.
+func UnscoredReason(rules Rules) string {
+	switch {
+	case rules.NoTens || rules.Player21Wins || rules.DoubleAnyCards || rules.DoubleRescue || rules.BonusPayouts:
+		return "basic strategy is not worked out for Spanish decks and their payouts"
+	case rules.Switch || rules.Dealer22Push:
+		return "basic strategy is not worked out for switched hands or a dealer 22 push"
+	case rules.FreeDoubles || rules.FreeSplits:
+		return "basic strategy is not worked out for free doubles and splits"
+	case rules.HoleCardUp || rules.DealerCardsDown || rules.DealerWinsTies:
+		return "basic strategy is not worked out unless one dealer card is up and ties push"
+	case rules.MinStand > 0 || rules.FiveCardTrick || rules.BuyCards || rules.Pays2To1:
+		return "basic strategy is not worked out for five-card tricks and bought cards"
+	case len(rules.Bonuses) > 0:
+		return "basic strategy is not worked out for bonus hands"
+	}
+	return ""
+}
+
+// ScoreDecision compares an action on the game's active hand with basic strategy.
+// It reports false if the action is not one the hand allows, or the rules are not scored (see UnscoredReason).
+func ScoreDecision(g *GameState, action string, rules Rules) (Decision, bool) {
+	if UnscoredReason(rules) != "" {
+		return Decision{}, false
+	}
+	hand := g.ActiveHand()
+	if hand == nil || len(hand.Cards) < 2 || len(g.DealerHand.Cards) == 0 {
+		return Decision{}, false
+	}
+
+	pair := len(hand.Cards) == 2 && hand.Cards[0].Rank == hand.Cards[1].Rank
+	canSplit := pair && !hand.Split
+	canDouble := len(hand.Cards) == 2 && rules.DoubleRefusal(*hand) == ""
+	canSurrender := rules.Surrender && len(hand.Cards) == 2 && len(g.Hands) == 1
+
+	// The player's cards and the upcard are out of the shoe
+	upcard := g.DealerHand.Cards[0]
+	seen := []Card{upcard}
+	for _, h := range g.Hands {
+		seen = append(seen, h.Cards...)
+	}
+	ev := newEVCalculator(rules, GameDecks, seen, cardValue(upcard))
+	hard, ace := hardTotal(hand.Cards)
+	evs := map[string]float64{
+		ActionHit:   ev.withDealerBlackjack(ev.hit(hard, ace, len(hand.Cards)), 1),
+		ActionStand: ev.withDealerBlackjack(ev.stand(softScore(hard, ace)), 1),
+	}
+	if canDouble {
+		evs[ActionDouble] = ev.withDealerBlackjack(ev.double(hard, ace), ev.doubledLoss())
+	}
+	if canSplit {
+		evs[ActionSplit] = ev.withDealerBlackjack(ev.split(cardValue(hand.Cards[0])), ev.doubledLoss())
+	}
+	if canSurrender {
+		evs[ActionSurrender] = -0.5
+	}
+	if _, allowed := evs[action]; !allowed {
+		return Decision{}, false
+	}
+
+	// Ties go to the simpler move, in this order
+	best := ActionStand
+	for _, a := range []string{ActionHit, ActionDouble, ActionSplit, ActionSurrender} {
+		if v, ok := evs[a]; ok && v > evs[best]+1e-12 {
+			best = a
+		}
+	}
+
+	category, label := CategoryHard, fmt.Sprintf("hard %d", hand.Score)
+	switch {
+	case pair:
+		category, label = CategoryPair, "pair "+string(hand.Cards[0].Rank)
+	case IsSoft(hand.Cards):
+		category, label = CategorySoft, fmt.Sprintf("soft %d", hand.Score)
+	}
+
+	return Decision{
+		PlayerID:  g.PlayerID,
+		GameID:    g.ID,
+		Time:      time.Now(),
+		HandIndex: g.CurrentHandIndex,
+		Category:  category,
+		Hand:      label,
+		Upcard:    UpcardLabel(g.DealerHand.Cards[0]),
+		Action:    action,
+		Best:      best,
+		EV:        evs,
+		Cost:      math.Max(0, evs[best]-evs[action]),
+		Bet:       g.BetAmount,
+	}, true
+}
+
+// cardProbabilities is the chance of drawing each value (ace = 1) from decks of 52 cards less the seen cards.
+// Zero decks is an infinite deck. Later draws are taken from the same shoe.
+func cardProbabilities(decks int, seen []Card) [11]float64 {
+	var p [11]float64
+	if decks <= 0 {
+		for v := 1; v <= 10; v++ {
+			p[v] = 1.0 / 13
+		}
+		p[10] = 4.0 / 13
+		return p
+	}
+	var counts [11]int
+	for v := 1; v <= 10; v++ {
+		counts[v] = 4 * decks
+	}
+	counts[10] = 16 * decks
+	total := 52 * decks
+	for _, card := range seen {
+		if v := cardValue(card); counts[v] > 0 {
+			counts[v]--
+			total--
+		}
+	}
+	for v := 1; v <= 10; v++ {
+		p[v] = float64(counts[v]) / float64(total)
+	}
+	return p
+}
+
+// dealerBust indexes busts in dealer outcomes; 0 to 4 are totals 17 to 21
+const dealerBust = 5
+
+// evCalculator computes player EVs against one dealer upcard
+type evCalculator struct {
+	rules     Rules
+	probs     [11]float64 // See cardProbabilities
+	blackjack float64     // Chance the dealer's second card makes a blackjack
+	dealer    [6]float64  // Final dealer totals, given the dealer has no blackjack
+	hits      map[[3]int]float64
+}
+
+func newEVCalculator(rules Rules, decks int, seen []Card, upcard int) *evCalculator {
+	c := &evCalculator{rules: rules, probs: cardProbabilities(decks, seen), hits: make(map[[3]int]float64)}
+	switch upcard {
+	case 1:
+		c.blackjack = c.probs[10]
+	case 10:
+		c.blackjack = c.probs[1]
+	}
+	c.dealer = c.dealerOutcomes(upcard)
+	return c
+}
+
+// withDealerBlackjack folds the dealer blackjack back into an EV given there is none.
+// With a hole card the dealer has already checked, so the EV stands; without one a blackjack
+// takes loss bets from the hand. Doubles after a split are not counted in the loss.
+func (c *evCalculator) withDealerBlackjack(ev, loss float64) float64 {
+	if !c.rules.NoHoleCard {
+		return ev
+	}
+	return (1-c.blackjack)*ev - c.blackjack*loss
+}
+
+// doubledLoss is what a dealer blackjack without a hole card takes from a doubled or split hand
+func (c *evCalculator) doubledLoss() float64 {
+	if c.rules.OriginalBetOnly {
+		return 1
+	}
+	return 2
+}
+
+// dealerOutcomes draws the hole card (never completing a blackjack) and plays the dealer hand out
+func (c *evCalculator) dealerOutcomes(upcard int) [6]float64 {
+	completesBlackjack := func(v int) bool { return (upcard == 1 && v == 10) || (upcard == 10 && v == 1) }
+
+	total := 0.0
+	for v := 1; v <= 10; v++ {
+		if !completesBlackjack(v) {
+			total += c.probs[v]
+		}
+	}
+	var outcomes [6]float64
+	for v := 1; v <= 10; v++ {
+		if completesBlackjack(v) {
+			continue
+		}
+		from := c.dealerFrom(upcard+v, upcard == 1 || v == 1)
+		for i := range outcomes {
+			outcomes[i] += c.probs[v] / total * from[i]
+		}
+	}
+	return outcomes
+}
+
+// dealerFrom returns the final outcome distribution of a dealer hand
+func (c *evCalculator) dealerFrom(hard int, ace bool) [6]float64 {
+	var outcomes [6]float64
+	score := softScore(hard, ace)
+	if score > 21 {
+		outcomes[dealerBust] = 1
+		return outcomes
+	}
+	soft := score != hard
+	if score >= 18 || (score == 17 && !(soft && c.rules.DealerHitsSoft17)) {
+		outcomes[score-17] = 1
+		return outcomes
+	}
+	for v := 1; v <= 10; v++ {
+		next := c.dealerFrom(hard+v, ace || v == 1)
+		for i := range outcomes {
+			outcomes[i] += c.probs[v] * next[i]
+		}
+	}
+	return outcomes
+}
+
+// stand is the EV of standing on score
+func (c *evCalculator) stand(score int) float64 {
+	if score > 21 {
+		return -1
+	}
+	ev := c.dealer[dealerBust]
+	for i := 0; i < dealerBust; i++ {
+		switch dealerScore := 17 + i; {
+		case score > dealerScore:
+			ev += c.dealer[i]
+		case score < dealerScore:
+			ev -= c.dealer[i]
+		}
+	}
+	return ev
+}
+
+// hit is the EV of taking a card on a hand of n cards and then playing on optimally
+func (c *evCalculator) hit(hard int, ace bool, n int) float64 {
+	key := [3]int{hard, 0, n}
+	if ace {
+		key[1] = 1
+	}
+	if ev, ok := c.hits[key]; ok {
+		return ev
+	}
+	ev := 0.0
+	for v := 1; v <= 10; v++ {
+		nextHard, nextAce := hard+v, ace || v == 1
+		if nextHard > 21 {
+			ev -= c.probs[v]
+			continue
+		}
+		// A Charlie wins without drawing again
+		if c.rules.Charlie > 0 && n+1 >= c.rules.Charlie {
+			ev += c.probs[v]
+			continue
+		}
+		ev += c.probs[v] * math.Max(c.stand(softScore(nextHard, nextAce)), c.hit(nextHard, nextAce, n+1))
+	}
+	c.hits[key] = ev
+	return ev
+}
+
+// double is the EV of doubling the bet for exactly one card
+func (c *evCalculator) double(hard int, ace bool) float64 {
+	ev := 0.0
+	for v := 1; v <= 10; v++ {
+		ev += c.probs[v] * c.stand(softScore(hard+v, ace || v == 1))
+	}
+	return 2 * ev
+}
+
+// split is the EV of splitting a pair of value into two hands played on optimally
+func (c *evCalculator) split(value int) float64 {
+	ev := 0.0
+	for v := 1; v <= 10; v++ {
+		hard, ace := value+v, value == 1 || v == 1
+		best := math.Max(c.stand(softScore(hard, ace)), c.hit(hard, ace, 2))
+		if c.rules.DoubleAfterSplit {
+			best = math.Max(best, c.double(hard, ace))
+		}
+		ev += c.probs[v] * best
+	}
+	return 2 * ev
+}
+
+// cardValue returns a card's value with aces counted as 1
+func cardValue(card Card) int {
+	if card.Rank == Ace {
+		return 1
+	}
+	return CalculateScore([]Card{card})
+}
+
+// hardTotal counts aces as 1 and reports whether there is one
+func hardTotal(cards []Card) (int, bool) {
+	hard, ace := 0, false
+	for _, card := range cards {
+		hard += cardValue(card)
+		ace = ace || card.Rank == Ace
+	}
+	return hard, ace
+}
+
+// softScore counts one ace as 11 if that does not bust
+func softScore(hard int, ace bool) int {
+	if ace && hard+10 <= 21 {
+		return hard + 10
+	}
+	return hard
+}
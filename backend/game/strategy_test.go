@@ -0,0 +1,132 @@
+package game
+
+import (
+	"math"
+	"testing"
+)
+
+// decisionGame deals the player two cards against a dealer upcard (and a harmless hole card)
+func decisionGame(p1, p2, up Rank) *GameState {
+	g := &GameState{}
+	g.Record(Event{Type: EventBetPlaced, GameID: "g1", PlayerID: "p1", Amount: 10})
+	g.Record(Event{Type: EventDeckShuffled, Deck: []Card{{Rank: p1}, {Rank: p2}, {Rank: up}, {Rank: Six}}})
+	g.Deal(SeatPlayer, 0, true)
+	g.Deal(SeatPlayer, 0, true)
+	g.Deal(SeatDealer, 0, true)
+	g.Deal(SeatDealer, 0, false)
+	return g
+}
+
+func TestScoreDecision(t *testing.T) {
+	tests := []struct {
+		p1, p2, up Rank
+		category   HandCategory
+		hand       string
+		best       string
+	}{
+		{Ten, Six, King, CategoryHard, "hard 16", ActionHit},
+		{Nine, Three, Four, CategoryHard, "hard 12", ActionStand},
+		{Ten, Two, Four, CategoryHard, "hard 12", ActionHit}, // The single deck is short a ten
+		{Six, Five, Six, CategoryHard, "hard 11", ActionDouble},
+		{Ace, Seven, Nine, CategorySoft, "soft 18", ActionHit},
+		{Ace, Seven, Three, CategorySoft, "soft 18", ActionDouble},
+		{Eight, Eight, Ace, CategoryPair, "pair 8", ActionSplit},
+		{Ten, Ten, Six, CategoryPair, "pair 10", ActionStand},
+		{Nine, Nine, Seven, CategoryPair, "pair 9", ActionStand},
+	}
+	for _, tt := range tests {
+		d, ok := ScoreDecision(decisionGame(tt.p1, tt.p2, tt.up), ActionStand, DefaultRules)
+		if !ok {
+			t.Fatalf("%s vs %s: expected the decision to be scored", tt.hand, tt.up)
+		}
+		if d.Category != tt.category || d.Hand != tt.hand || d.Best != tt.best {
+			t.Errorf("%s vs %s: expected %s/%s/%s, got %s/%s/%s", tt.p1+tt.p2, tt.up, tt.category, tt.hand, tt.best, d.Category, d.Hand, d.Best)
+		}
+		if d.Correct() != (tt.best == ActionStand) {
+			t.Errorf("%s vs %s: standing scored as correct=%v with cost %f", tt.hand, tt.up, d.Correct(), d.Cost)
+		}
+	}
+}
+
+func TestScoreDecisionRejectsUnavailableActions(t *testing.T) {
+	g := decisionGame(Ten, Six, King)
+	if _, ok := ScoreDecision(g, ActionSplit, DefaultRules); ok {
+		t.Errorf("Expected split to be unavailable without a pair")
+	}
+	if _, ok := ScoreDecision(g, ActionSurrender, DefaultRules); ok {
+		t.Errorf("Expected surrender to be unavailable under the default rules")
+	}
+	rules := DefaultRules
+	rules.Surrender = true
+	if d, ok := ScoreDecision(g, ActionSurrender, rules); !ok || d.Best != ActionSurrender {
+		t.Errorf("Expected surrender to be best for hard 16 vs 10 when offered, got %+v", d)
+	}
+}
+
+func TestUnscoredReason(t *testing.T) {
+	if reason := UnscoredReason(DefaultRules); reason != "" {
+		t.Errorf("Expected the classic game to be scored, got %q", reason)
+	}
+	charlie := DefaultRules
+	charlie.Charlie = 6
+	for _, rules := range []Rules{ENHCRules, ENHCOBORules, charlie} {
+		if reason := UnscoredReason(rules); reason != "" {
+			t.Errorf("Expected %s with charlie %d to be scored, got %q", rules.Variant, rules.Charlie, reason)
+		}
+	}
+	bonus := DefaultRules
+	bonus.Bonuses = []string{"7-7-7 pays 3:1"}
+	for _, rules := range []Rules{Spanish21Rules, SwitchRules, FreeBetRules, DoubleExposureRules, PontoonRules, bonus} {
+		if UnscoredReason(rules) == "" {
+			t.Errorf("Expected %s with bonuses %v not to be scored", rules.Variant, rules.Bonuses)
+		}
+		if _, ok := ScoreDecision(decisionGame(Ten, Six, King), ActionStand, rules); ok {
+			t.Errorf("Expected no score under %+v", rules)
+		}
+	}
+}
+
+func TestScoreDecisionWithoutHoleCard(t *testing.T) {
+	// Doubling 11 against a 10 risks losing twice to a blackjack behind it
+	g := decisionGame(Six, Five, King)
+	if d, _ := ScoreDecision(g, ActionHit, DefaultRules); d.Best != ActionDouble {
+		t.Errorf("Expected a double on hard 11 vs 10 with a hole card, got %+v", d)
+	}
+	d, ok := ScoreDecision(g, ActionHit, ENHCRules)
+	if !ok || d.Best != ActionHit {
+		t.Errorf("Expected a hit on hard 11 vs 10 without a hole card, got %+v", d)
+	}
+	if obo, _ := ScoreDecision(g, ActionHit, ENHCOBORules); obo.Best != ActionDouble || obo.EV[ActionDouble] <= d.EV[ActionDouble] {
+		t.Errorf("Expected a double when a blackjack takes the original bet only, got %+v", obo)
+	}
+}
+
+func TestScoreDecisionWithCharlie(t *testing.T) {
+	// Any ace to 5 makes a five-card Charlie of 2-3-4-7; 17 of the 47 unseen cards
+	g := &GameState{
+		Hands:      []Hand{{Cards: []Card{{Rank: Two}, {Rank: Three}, {Rank: Four}, {Rank: Seven}}, Score: 16}},
+		DealerHand: Hand{Cards: []Card{{Rank: King}}},
+	}
+	charlie := DefaultRules
+	charlie.Charlie = 5
+	d, ok := ScoreDecision(g, ActionStand, charlie)
+	if want := (17.0 - 30.0) / 47; !ok || d.Best != ActionHit || math.Abs(d.EV[ActionHit]-want) > 1e-9 {
+		t.Errorf("Expected a hit worth %f toward the Charlie, got %+v", want, d)
+	}
+}
+
+func TestDecisionStore(t *testing.T) {
+	s := NewDecisionStore()
+	s.Record(Decision{PlayerID: "p1", Category: CategoryHard, Cost: 0})
+	s.Record(Decision{PlayerID: "p1", Category: CategoryHard, Cost: 0.1, Bet: 10, Hand: "small"})
+	s.Record(Decision{PlayerID: "p1", Category: CategorySoft, Cost: 0.2, Bet: 50, Hand: "big"})
+
+	p, ok := s.Get("p1")
+	if !ok || p.Overall.Decisions != 3 || p.Overall.Correct != 1 || p.ByCategory[CategoryHard].Rate != 0.5 {
+		t.Errorf("Unexpected accuracy: %+v", p)
+	}
+	mistakes := s.Mistakes("p1", 10)
+	if len(mistakes) != 2 || mistakes[0].Hand != "big" {
+		t.Errorf("Expected the costliest mistake first, got %+v", mistakes)
+	}
+}
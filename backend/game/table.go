@@ -0,0 +1,568 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// MaxSeats is the number of seats at a table
+const MaxSeats = 7
+
+// MaxTableDecks is the largest shoe a table can be created with
+const MaxTableDecks = 8
+
+// TablePhase represents where a table is in its betting round
+type TablePhase string
+
+const (
+	PhaseBetting TablePhase = "Betting" // Waiting for bets
+	PhasePlaying TablePhase = "Playing" // Seats act one after the other
+	PhaseSettled TablePhase = "Settled" // Round paid out; the next bet opens a new round
+)
+
+var (
+	ErrTableFull     = errors.New("table is full")
+	ErrInvalidSeat   = errors.New("invalid seat")
+	ErrSeatTaken     = errors.New("seat is taken")
+	ErrAlreadySeated = errors.New("player is already seated")
+	ErrNotSeated     = errors.New("player is not seated")
+	ErrBettingClosed = errors.New("betting is closed for this round")
+	ErrAlreadyBet    = errors.New("bet already placed this round")
+	ErrNotYourTurn   = errors.New("not your turn")
+	ErrInvalidAction = errors.New("invalid action")
+	ErrTableClosed   = errors.New("table is closed")
+)
+
+// TableConfig configures a multi-seat table
+type TableConfig struct {
+	Decks       int           `json:"decks"`
+	BetTimeout  time.Duration `json:"bet_timeout"`  // Round is dealt this long after the first bet
+	TurnTimeout time.Duration `json:"turn_timeout"` // A seat that does not act in time stands
+}
+
+// DefaultTableConfig is used for tables created without explicit settings
+var DefaultTableConfig = TableConfig{Decks: 6, BetTimeout: 15 * time.Second, TurnTimeout: 30 * time.Second}
+
+// TableSeat is one player position at a table
+type TableSeat struct {
+	Number   int        `json:"number"`
+	PlayerID string     `json:"player_id,omitempty"` // Empty if the seat is free
+	Bet      int        `json:"bet"`                 // Zero if sitting out this round
+	Hand     Hand       `json:"hand"`
+	Done     bool       `json:"done"`              // Finished acting this round
+	Leaving  bool       `json:"leaving,omitempty"` // Seat is freed once the round settles
+	Result   GameStatus `json:"result,omitempty"`
+	Payout   int        `json:"payout"`
+}
+
+func (s *TableSeat) occupied() bool { return s.PlayerID != "" }
+
+func (s *TableSeat) inRound() bool { return s.occupied() && s.Bet > 0 }
+
+// TableState is the serializable state of a table
+type TableState struct {
+	ID           string              `json:"id"`
+	Config       TableConfig         `json:"config"`
+	Seats        [MaxSeats]TableSeat `json:"seats"`
+	Shoe         *Shoe               `json:"-"`
+	DealerHand   Hand                `json:"dealer_hand"`
+	Phase        TablePhase          `json:"phase"`
+	Round        int                 `json:"round"`
+	CurrentSeat  int                 `json:"current_seat"` // -1 when no seat is acting
+	BetDeadline  time.Time           `json:"bet_deadline,omitempty"`
+	TurnDeadline time.Time           `json:"turn_deadline,omitempty"`
+	EmptySince   time.Time           `json:"-"` // When the last player left, see CloseIfIdle
+	Closed       bool                `json:"-"` // Taken out of the store; nobody may sit down
+}
+
+// Table is a shared shoe and dealer hand played by up to MaxSeats players.
+// All methods are safe for concurrent use.
+type Table struct {
+	mu     sync.Mutex
+	ledger *Ledger
+	bus    *Bus // Optional; notified after every change
+
+	// onSettle, if set, is called for every seat paid out
+	onSettle func(Settlement)
+	state    TableState
+	settled  []Settlement // Of the round settled while the lock is held, see unlock
+}
+
+// NewTable creates an empty table in the betting phase
+func NewTable(id string, config TableConfig, ledger *Ledger, bus *Bus) *Table {
+	t := &Table{ledger: ledger, bus: bus, state: TableState{
+		ID:          id,
+		Config:      config,
+		Phase:       PhaseBetting,
+		Round:       1,
+		CurrentSeat: -1,
+	}}
+	for i := range t.state.Seats {
+		t.state.Seats[i].Number = i
+	}
+	return t
+}
+
+// RestoreTable recreates a table from saved state
+func RestoreTable(state TableState, ledger *Ledger, bus *Bus) *Table {
+	return &Table{ledger: ledger, bus: bus, state: state}
+}
+
+// ID returns the table ID
+func (t *Table) ID() string {
+	return t.state.ID
+}
+
+// State returns a copy of the table state
+func (t *Table) State() TableState {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	state := t.state
+	state.Shoe = nil
+	state.DealerHand = state.DealerHand.clone()
+	for i := range state.Seats {
+		state.Seats[i].Hand = state.Seats[i].Hand.clone()
+	}
+	return state
+}
+
+// Join seats a player. A negative seat number takes the first free seat.
+func (t *Table) Join(playerID string, seat int) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.state.Closed {
+		return 0, ErrTableClosed
+	}
+	if t.seatOf(playerID) != nil {
+		return 0, ErrAlreadySeated
+	}
+	if seat < 0 {
+		for i := range t.state.Seats {
+			if !t.state.Seats[i].occupied() {
+				seat = i
+				break
+			}
+		}
+		if seat < 0 {
+			return 0, ErrTableFull
+		}
+	}
+	if seat >= MaxSeats {
+		return 0, ErrInvalidSeat
+	}
+	if t.state.Seats[seat].occupied() {
+		return 0, ErrSeatTaken
+	}
+
+	t.state.Seats[seat] = TableSeat{Number: seat, PlayerID: playerID}
+	t.changed()
+	return seat, nil
+}
+
+// Leave frees a player's seat. A bet placed during betting is refunded;
+// a hand in play is stood and the seat is freed once the round settles.
+func (t *Table) Leave(playerID string, now time.Time) error {
+	t.mu.Lock()
+	defer t.unlock()
+
+	s := t.seatOf(playerID)
+	if s == nil {
+		return ErrNotSeated
+	}
+
+	if t.state.Phase == PhasePlaying && s.inRound() {
+		s.Leaving = true
+		s.Done = true
+		if t.state.CurrentSeat == s.Number {
+			t.advance(now)
+		}
+		t.changed()
+		return nil
+	}
+
+	if t.state.Phase == PhaseBetting && s.Bet > 0 {
+		t.ledger.Credit(playerID, TxRefund, t.roundID(), s.Bet, "left table before the deal")
+	}
+	t.state.Seats[s.Number] = TableSeat{Number: s.Number}
+	t.dealIfAllBet(now)
+	t.changed()
+	return nil
+}
+
+// IsEmpty reports whether no seat is occupied
+func (t *Table) IsEmpty() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.isEmpty()
+}
+
+// isEmpty is IsEmpty for callers holding the lock
+func (t *Table) isEmpty() bool {
+	for i := range t.state.Seats {
+		if t.state.Seats[i].occupied() {
+			return false
+		}
+	}
+	return true
+}
+
+// CloseIfIdle closes the table once nobody has sat at it for timeout, and reports whether it is closed.
+// The idle time counts from the first call that finds the table empty.
+func (t *Table) CloseIfIdle(now time.Time, timeout time.Duration) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	switch {
+	case t.state.Closed:
+	case !t.isEmpty():
+		t.state.EmptySince = time.Time{}
+	case t.state.EmptySince.IsZero():
+		t.state.EmptySince = now
+	case now.Sub(t.state.EmptySince) >= timeout:
+		t.state.Closed = true
+	}
+	return t.state.Closed
+}
+
+// PlaceBet takes a seated player's bet for the round.
+// The round is dealt once every seated player has bet, or when the betting timeout expires.
+func (t *Table) PlaceBet(playerID string, amount int, now time.Time) error {
+	t.mu.Lock()
+	defer t.unlock()
+
+	s := t.seatOf(playerID)
+	if s == nil {
+		return ErrNotSeated
+	}
+	if t.state.Phase == PhaseSettled {
+		t.newRound()
+	}
+	if t.state.Phase != PhaseBetting {
+		return ErrBettingClosed
+	}
+	if s.Bet > 0 {
+		return ErrAlreadyBet
+	}
+	if amount < 1 {
+		return ErrInvalidAmount
+	}
+	if _, err := t.ledger.Debit(playerID, TxBet, t.roundID(), amount, fmt.Sprintf("table seat %d", s.Number+1)); err != nil {
+		return err
+	}
+
+	s.Bet = amount
+	if t.state.BetDeadline.IsZero() {
+		t.state.BetDeadline = now.Add(t.state.Config.BetTimeout)
+	}
+	t.dealIfAllBet(now)
+	t.changed()
+	return nil
+}
+
+// Exposure returns the player's bet on the round, until it is settled
+func (t *Table) Exposure(playerID string) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	s := t.seatOf(playerID)
+	if s == nil || t.state.Phase == PhaseSettled {
+		return 0
+	}
+	return s.Bet
+}
+
+// Act applies "hit" or "stand" for the seat whose turn it is
+func (t *Table) Act(playerID, action string, now time.Time) error {
+	t.mu.Lock()
+	defer t.unlock()
+
+	if t.state.Phase != PhasePlaying || t.state.CurrentSeat < 0 {
+		return ErrNotYourTurn
+	}
+	s := &t.state.Seats[t.state.CurrentSeat]
+	if s.PlayerID != playerID {
+		return ErrNotYourTurn
+	}
+
+	switch action {
+	case "hit":
+		s.Hand.Cards = append(s.Hand.Cards, t.draw())
+		s.Hand.Score = CalculateScore(s.Hand.Cards)
+		if s.Hand.Score >= 21 {
+			s.Done = true
+			t.advance(now)
+		} else {
+			t.state.TurnDeadline = now.Add(t.state.Config.TurnTimeout)
+		}
+	case "stand":
+		s.Done = true
+		t.advance(now)
+	default:
+		return ErrInvalidAction
+	}
+	t.changed()
+	return nil
+}
+
+// Tick applies the betting and turn timeouts
+func (t *Table) Tick(now time.Time) {
+	t.mu.Lock()
+	defer t.unlock()
+
+	switch t.state.Phase {
+	case PhaseBetting:
+		if t.state.BetDeadline.IsZero() || !now.After(t.state.BetDeadline) {
+			return
+		}
+		// Everyone who bet left again: keep betting open, the next bet restarts the clock
+		t.state.BetDeadline = time.Time{}
+		for i := range t.state.Seats {
+			if t.state.Seats[i].inRound() {
+				t.deal(now)
+				break
+			}
+		}
+		t.changed()
+	case PhasePlaying:
+		if t.state.CurrentSeat >= 0 && now.After(t.state.TurnDeadline) {
+			t.state.Seats[t.state.CurrentSeat].Done = true
+			t.advance(now)
+			t.changed()
+		}
+	}
+}
+
+// unlock releases the lock, then reports the seats of a round settled while it was held.
+// onSettle is called without the lock so that it never waits on the table.
+func (t *Table) unlock() {
+	settled := t.settled
+	t.settled = nil
+	t.mu.Unlock()
+
+	if t.onSettle == nil {
+		return
+	}
+	for _, s := range settled {
+		s.Balance = t.balance(s.PlayerID)
+		t.onSettle(s)
+	}
+}
+
+// changed tells bus subscribers the table state moved on; callers hold the lock
+func (t *Table) changed() {
+	if t.bus == nil {
+		return
+	}
+	n := Notification{Type: NotifyTable, TableID: t.state.ID}
+	for _, s := range t.state.Seats {
+		if s.occupied() {
+			n.Seated = append(n.Seated, s.PlayerID)
+		}
+	}
+	t.bus.Publish(n)
+}
+
+// seatOf returns the seat of a player, or nil; callers hold the lock
+func (t *Table) seatOf(playerID string) *TableSeat {
+	for i := range t.state.Seats {
+		if t.state.Seats[i].PlayerID == playerID {
+			return &t.state.Seats[i]
+		}
+	}
+	return nil
+}
+
+// roundID identifies the current round in the ledger
+func (t *Table) roundID() string {
+	return fmt.Sprintf("%s/%d", t.state.ID, t.state.Round)
+}
+
+// newRound clears the previous round's hands
+func (t *Table) newRound() {
+	t.state.Round++
+	t.state.Phase = PhaseBetting
+	t.state.DealerHand = Hand{Cards: []Card{}}
+	t.state.BetDeadline = time.Time{}
+	t.state.TurnDeadline = time.Time{}
+	t.state.CurrentSeat = -1
+	for i := range t.state.Seats {
+		s := &t.state.Seats[i]
+		*s = TableSeat{Number: s.Number, PlayerID: s.PlayerID}
+	}
+}
+
+// dealIfAllBet deals as soon as every seated player has bet
+func (t *Table) dealIfAllBet(now time.Time) {
+	if t.state.Phase != PhaseBetting {
+		return
+	}
+	bets := 0
+	for i := range t.state.Seats {
+		s := &t.state.Seats[i]
+		if s.occupied() && s.Bet == 0 {
+			return
+		}
+		if s.inRound() {
+			bets++
+		}
+	}
+	if bets > 0 {
+		t.deal(now)
+	}
+}
+
+// draw takes the top card of the shoe, opening a fresh shoe if a round used it up
+func (t *Table) draw() Card {
+	if t.state.Shoe == nil || t.state.Shoe.Remaining() == 0 {
+		t.state.Shoe = NewShoe(t.decks())
+	}
+	return t.state.Shoe.Draw()
+}
+
+// decks returns how many decks the shoe holds
+func (t *Table) decks() int {
+	return max(t.state.Config.Decks, 1)
+}
+
+// deal starts play: one card to each betting seat, one to the dealer, then a second round with the hole card
+func (t *Table) deal(now time.Time) {
+	// Reshuffle once the cut card has come out
+	if t.state.Shoe == nil || t.state.Shoe.NeedsShuffle() {
+		t.state.Shoe = NewShoe(t.decks())
+	}
+
+	t.state.Phase = PhasePlaying
+	t.state.DealerHand = Hand{Cards: []Card{}}
+	for pass := 0; pass < 2; pass++ {
+		for i := range t.state.Seats {
+			s := &t.state.Seats[i]
+			if s.inRound() {
+				s.Hand.Cards = append(s.Hand.Cards, t.draw())
+				s.Hand.Score = CalculateScore(s.Hand.Cards)
+			}
+		}
+		t.state.DealerHand.Cards = append(t.state.DealerHand.Cards, t.draw())
+	}
+	t.state.DealerHand.Score = CalculateScore(t.state.DealerHand.Cards)
+
+	// A dealer blackjack ends the round; player naturals need no decision
+	dealerBlackjack := IsBlackjack(t.state.DealerHand)
+	for i := range t.state.Seats {
+		s := &t.state.Seats[i]
+		if s.inRound() && (dealerBlackjack || IsBlackjack(s.Hand)) {
+			s.Done = true
+		}
+	}
+
+	t.state.CurrentSeat = -1
+	t.advance(now)
+}
+
+// advance passes the turn to the next seat, or plays the dealer once every seat is done
+func (t *Table) advance(now time.Time) {
+	for i := t.state.CurrentSeat + 1; i < MaxSeats; i++ {
+		s := &t.state.Seats[i]
+		if s.inRound() && !s.Done {
+			t.state.CurrentSeat = i
+			t.state.TurnDeadline = now.Add(t.state.Config.TurnTimeout)
+			return
+		}
+	}
+	t.state.CurrentSeat = -1
+	t.state.TurnDeadline = time.Time{}
+	t.settle()
+}
+
+// settle plays the dealer hand once and pays every seat with the standard rules.
+// The payouts are posted before the round shows as settled, so that a snapshot never has one without
+// the other; the settlements are queued for unlock.
+func (t *Table) settle() {
+	dealer := &t.state.DealerHand
+	dealerBlackjack := IsBlackjack(*dealer)
+
+	// The dealer only draws if some hand still needs to be beaten
+	needsDealer := false
+	for i := range t.state.Seats {
+		s := &t.state.Seats[i]
+		if s.inRound() && !IsBust(s.Hand.Score) && !IsBlackjack(s.Hand) {
+			needsDealer = true
+		}
+	}
+	if needsDealer && !dealerBlackjack {
+		for ShouldDealerHit(*dealer) {
+			dealer.Cards = append(dealer.Cards, t.draw())
+			dealer.Score = CalculateScore(dealer.Cards)
+		}
+	}
+
+	for i := range t.state.Seats {
+		s := &t.state.Seats[i]
+		if !s.inRound() {
+			continue
+		}
+
+		// Seats are paid like single-player hands under the classic rules
+		s.Payout = DefaultRules.HandPayout(s.Hand, *dealer, s.Bet)
+
+		kind, reason := TxPayout, fmt.Sprintf("table seat %d win", s.Number+1)
+		switch {
+		case s.Payout > s.Bet:
+			s.Result = StatusPlayerWon
+		case s.Payout == s.Bet:
+			s.Result = StatusPush
+			kind, reason = TxRefund, fmt.Sprintf("table seat %d push", s.Number+1)
+		default:
+			s.Result = StatusDealerWon
+		}
+		if s.Payout > 0 {
+			if _, err := t.ledger.Credit(s.PlayerID, kind, t.roundID(), s.Payout, reason); err != nil {
+				log.Printf("table %s: payout of %d to seat %d failed: %v", t.state.ID, s.Payout, s.Number+1, err)
+			}
+		}
+
+		// Reported by unlock
+		t.settled = append(t.settled, Settlement{
+			PlayerID:  s.PlayerID,
+			GameID:    t.roundID(),
+			Time:      time.Now(),
+			Result:    s.Result,
+			Staked:    s.Bet,
+			Payout:    s.Payout,
+			Blackjack: IsBlackjack(s.Hand),
+			Start:     append([]Card(nil), s.Hand.Cards[:2]...),
+			Hands:     []Hand{s.Hand.clone()},
+			HandsWon:  []bool{s.Result == StatusPlayerWon},
+			Dealer:    dealer.clone(),
+		})
+
+		if s.Leaving {
+			*s = TableSeat{Number: s.Number}
+		}
+	}
+
+	t.state.Phase = PhaseSettled
+	t.state.BetDeadline = time.Time{}
+}
+
+// balance returns a player's balance, or zero without a ledger
+func (t *Table) balance(playerID string) int {
+	if t.ledger == nil {
+		return 0
+	}
+	return ledgerWallet{ledger: t.ledger, playerID: playerID}.Balance()
+}
+
+// persistentState returns a copy of the state including the shoe, for snapshots
+func (t *Table) persistentState() TableState {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	state := t.state
+	if t.state.Shoe != nil {
+		shoe := *t.state.Shoe
+		shoe.Cards = append([]Card(nil), shoe.Cards...)
+		state.Shoe = &shoe
+	}
+	return state
+}
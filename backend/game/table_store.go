@@ -0,0 +1,142 @@
+package game
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultTableIdleTimeout is how long a table may stay empty before it is removed
+const DefaultTableIdleTimeout = 10 * time.Minute
+
+// TableStore is a thread-safe in-memory store for tables.
+// Once started it also applies the betting and turn timeouts of every table, and removes idle ones.
+type TableStore struct {
+	mu     sync.RWMutex
+	tables map[string]*Table
+	ledger *Ledger
+	bus    *Bus
+
+	// IdleTimeout is how long a table may stay empty before Tick removes it
+	IdleTimeout time.Duration
+
+	// OnSettle, if set, is called for every seat paid out at any table
+	OnSettle func(Settlement)
+
+	stop    chan struct{}
+	done    chan struct{}
+	once    sync.Once
+	started bool
+}
+
+// NewTableStore creates a new TableStore whose tables settle through ledger and notify bus
+func NewTableStore(ledger *Ledger, bus *Bus) *TableStore {
+	return &TableStore{
+		tables:      make(map[string]*Table),
+		ledger:      ledger,
+		bus:         bus,
+		IdleTimeout: DefaultTableIdleTimeout,
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+}
+
+// Create creates and stores an empty table
+func (s *TableStore) Create(id string, config TableConfig) *Table {
+	table := NewTable(id, config, s.ledger, s.bus)
+	table.onSettle = s.settled
+	s.save(table)
+	return table
+}
+
+// Restore stores a table recreated from saved state
+func (s *TableStore) Restore(state TableState) *Table {
+	table := RestoreTable(state, s.ledger, s.bus)
+	table.onSettle = s.settled
+	s.save(table)
+	return table
+}
+
+func (s *TableStore) settled(settlement Settlement) {
+	if s.OnSettle != nil {
+		s.OnSettle(settlement)
+	}
+}
+
+func (s *TableStore) save(table *Table) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tables[table.ID()] = table
+}
+
+// Get retrieves a table by ID
+func (s *TableStore) Get(id string) (*Table, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	table, exists := s.tables[id]
+	return table, exists
+}
+
+// Delete removes a table from the store
+func (s *TableStore) Delete(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.tables, id)
+}
+
+// All returns every stored table
+func (s *TableStore) All() []*Table {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	tables := make([]*Table, 0, len(s.tables))
+	for _, table := range s.tables {
+		tables = append(tables, table)
+	}
+	return tables
+}
+
+// Exposure returns what a player has riding on tables whose round is not settled yet
+func (s *TableStore) Exposure(playerID string) int {
+	exposure := 0
+	for _, table := range s.All() {
+		exposure += table.Exposure(playerID)
+	}
+	return exposure
+}
+
+// Tick applies the timeouts of every table and removes the tables left empty for IdleTimeout
+func (s *TableStore) Tick(now time.Time) {
+	for _, table := range s.All() {
+		table.Tick(now)
+		if table.CloseIfIdle(now, s.IdleTimeout) {
+			s.Delete(table.ID())
+		}
+	}
+}
+
+// Start ticks every table at the given interval in the background
+func (s *TableStore) Start(interval time.Duration) {
+	s.started = true
+	go func() {
+		defer close(s.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case now := <-ticker.C:
+				s.Tick(now)
+			case <-s.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop ends the background ticking and waits for it to exit
+func (s *TableStore) Stop() {
+	s.once.Do(func() {
+		close(s.stop)
+		if s.started {
+			<-s.done
+		}
+	})
+}
@@ -0,0 +1,228 @@
+package game
+
+import (
+	"testing"
+	"time"
+)
+
+func newTestTable(t *testing.T, playerIDs ...string) (*Table, *PlayerStore) {
+	t.Helper()
+	players := NewPlayerStore()
+	ledger := NewLedger(players)
+	for _, id := range playerIDs {
+		players.Save(&Player{ID: id})
+		ledger.Credit(id, TxBonus, "", 100, "welcome bonus")
+	}
+	return NewTable("t1", TableConfig{Decks: 1, BetTimeout: 10 * time.Second, TurnTimeout: 10 * time.Second}, ledger, nil), players
+}
+
+func TestTableRoundSeatBySeat(t *testing.T) {
+	table, players := newTestTable(t, "alice", "bob")
+	now := time.Now()
+
+	if _, err := table.Join("alice", 2); err != nil {
+		t.Fatalf("join: %v", err)
+	}
+	if _, err := table.Join("bob", 2); err != ErrSeatTaken {
+		t.Errorf("expected ErrSeatTaken, got %v", err)
+	}
+	if seat, err := table.Join("bob", -1); err != nil || seat != 0 {
+		t.Fatalf("expected bob in the first free seat, got %d (%v)", seat, err)
+	}
+
+	table.PlaceBet("alice", 10, now)
+	if state := table.State(); state.Phase != PhaseBetting {
+		t.Fatalf("expected to wait for bob's bet, got %s", state.Phase)
+	}
+	table.PlaceBet("bob", 20, now)
+
+	state := table.State()
+	if state.Phase == PhasePlaying {
+		// Seats act in seat order
+		if state.CurrentSeat == 0 {
+			if err := table.Act("alice", "stand", now); err != ErrNotYourTurn {
+				t.Errorf("expected ErrNotYourTurn, got %v", err)
+			}
+		}
+		for table.State().Phase == PhasePlaying {
+			current := table.State().Seats[table.State().CurrentSeat]
+			if err := table.Act(current.PlayerID, "stand", now); err != nil {
+				t.Fatalf("stand: %v", err)
+			}
+		}
+		state = table.State()
+	}
+
+	if state.Phase != PhaseSettled {
+		t.Fatalf("expected round to be settled, got %s", state.Phase)
+	}
+	for _, seat := range []TableSeat{state.Seats[0], state.Seats[2]} {
+		player, _ := players.Get(seat.PlayerID)
+		if player.Balance != 100-seat.Bet+seat.Payout {
+			t.Errorf("%s: expected balance %d, got %d", seat.PlayerID, 100-seat.Bet+seat.Payout, player.Balance)
+		}
+		if seat.Result == "" {
+			t.Errorf("%s: expected a result", seat.PlayerID)
+		}
+	}
+}
+
+func TestTableTimeouts(t *testing.T) {
+	table, _ := newTestTable(t, "alice", "bob")
+	now := time.Now()
+	table.Join("alice", -1)
+	table.Join("bob", -1)
+	table.PlaceBet("alice", 10, now)
+
+	// Bob never bets: the round is dealt without him once betting times out
+	table.Tick(now.Add(11 * time.Second))
+	state := table.State()
+	if state.Phase == PhaseBetting {
+		t.Fatal("expected the round to be dealt after the betting timeout")
+	}
+	if len(state.Seats[1].Hand.Cards) != 0 {
+		t.Error("expected bob to sit out the round")
+	}
+
+	// Alice never acts: she stands when her turn times out
+	if state.Phase == PhasePlaying {
+		table.Tick(now.Add(22 * time.Second))
+		if state = table.State(); state.Phase != PhaseSettled {
+			t.Errorf("expected the round to settle after the turn timeout, got %s", state.Phase)
+		}
+	}
+}
+
+func TestTableBettingTimeoutWithoutBets(t *testing.T) {
+	table, _ := newTestTable(t, "alice", "bob", "carol")
+	now := time.Now()
+	table.Join("alice", -1)
+	table.Join("bob", -1)
+	// Carol never bets, so that bob's bet below does not deal the round at once
+	table.Join("carol", -1)
+	table.PlaceBet("alice", 10, now)
+	table.Leave("alice", now)
+
+	// Nobody has a bet in when betting times out: no round is dealt and betting stays open
+	table.Tick(now.Add(11 * time.Second))
+	state := table.State()
+	if state.Phase != PhaseBetting || state.Round != 1 || len(state.DealerHand.Cards) != 0 || !state.BetDeadline.IsZero() {
+		t.Fatalf("expected betting to stay open without a deadline, got %s round %d with %+v", state.Phase, state.Round, state.DealerHand)
+	}
+
+	// The next bet starts a fresh clock
+	later := now.Add(time.Minute)
+	table.PlaceBet("bob", 10, later)
+	if state := table.State(); !state.BetDeadline.Equal(later.Add(10 * time.Second)) {
+		t.Errorf("expected the deadline to restart with bob's bet, got %v", state.BetDeadline)
+	}
+}
+
+func TestTableSettlesOutsideTheLock(t *testing.T) {
+	table, players := newTestTable(t, "alice")
+	now := time.Now()
+	table.Join("alice", -1)
+
+	// onSettle may look at the table again, which would deadlock under the lock
+	var settled []Settlement
+	table.onSettle = func(s Settlement) {
+		table.State()
+		settled = append(settled, s)
+	}
+	table.PlaceBet("alice", 10, now)
+	for table.State().Phase == PhasePlaying {
+		table.Act("alice", "stand", now)
+	}
+
+	player, _ := players.Get("alice")
+	if len(settled) != 1 || settled[0].Balance != player.Balance || player.Balance != 90+settled[0].Payout {
+		t.Errorf("expected one settlement once paid out, got %+v with balance %d", settled, player.Balance)
+	}
+}
+
+func TestTablePaysLikeSinglePlayerHands(t *testing.T) {
+	table, players := newTestTable(t, "alice", "bob")
+	now := time.Now()
+	table.Join("alice", 0)
+	table.Join("bob", 1)
+	// Alice is dealt a natural, bob 19 and the dealer 17
+	table.state.Shoe = &Shoe{Decks: 1, Cards: []Card{
+		{Suit: Hearts, Rank: Ace}, {Suit: Hearts, Rank: Ten}, {Suit: Clubs, Rank: Ten},
+		{Suit: Hearts, Rank: King}, {Suit: Hearts, Rank: Nine}, {Suit: Clubs, Rank: Seven},
+	}}
+	table.PlaceBet("alice", 10, now)
+	table.PlaceBet("bob", 10, now)
+	if err := table.Act("bob", "stand", now); err != nil {
+		t.Fatalf("stand: %v", err)
+	}
+
+	state := table.State()
+	if state.Phase != PhaseSettled {
+		t.Fatalf("expected the round to be settled, got %s", state.Phase)
+	}
+	for _, tt := range []struct {
+		seat   int
+		payout int
+	}{{0, 25}, {1, 20}} {
+		seat := state.Seats[tt.seat]
+		player, _ := players.Get(seat.PlayerID)
+		if seat.Payout != tt.payout || player.Balance != 90+tt.payout {
+			t.Errorf("%s: expected a payout of %d, got %d with balance %d", seat.PlayerID, tt.payout, seat.Payout, player.Balance)
+		}
+	}
+}
+
+func TestTableOpensFreshShoeWhenEmpty(t *testing.T) {
+	ids := []string{"p0", "p1", "p2", "p3", "p4", "p5", "p6"}
+	table, _ := newTestTable(t, ids...)
+	now := time.Now()
+	for i, id := range ids {
+		table.Join(id, i)
+	}
+	// Just above the reshuffle point, but short of the 16 cards a full table needs
+	table.state.Shoe = &Shoe{Decks: 1, Cards: NewDecks(1)[:14], CutCard: 13}
+	for _, id := range ids {
+		table.PlaceBet(id, 5, now)
+	}
+
+	state := table.State()
+	for _, seat := range state.Seats {
+		for _, c := range seat.Hand.Cards {
+			if c.Rank == "" {
+				t.Fatalf("expected only real cards, %s got %+v", seat.PlayerID, seat.Hand.Cards)
+			}
+		}
+	}
+	for _, c := range table.state.DealerHand.Cards {
+		if c.Rank == "" {
+			t.Fatalf("expected only real dealer cards, got %+v", table.state.DealerHand.Cards)
+		}
+	}
+}
+
+func TestTableStoreRemovesIdleTables(t *testing.T) {
+	store := NewTableStore(nil, nil)
+	store.IdleTimeout = time.Minute
+	idle := store.Create("idle", DefaultTableConfig)
+	busy := store.Create("busy", DefaultTableConfig)
+	busy.Join("alice", -1)
+	now := time.Now()
+
+	// The idle time counts from the first tick that finds the table empty
+	store.Tick(now)
+	store.Tick(now.Add(59 * time.Second))
+	if _, ok := store.Get("idle"); !ok {
+		t.Fatal("expected the table to stay within the idle timeout")
+	}
+	store.Tick(now.Add(time.Minute))
+	if _, ok := store.Get("idle"); ok {
+		t.Error("expected the idle table to be removed")
+	}
+	if _, ok := store.Get("busy"); !ok {
+		t.Error("expected the table with a player to stay")
+	}
+	// A player holding on to the removed table cannot sit down at it
+	if _, err := idle.Join("bob", -1); err != ErrTableClosed {
+		t.Errorf("expected ErrTableClosed, got %v", err)
+	}
+}
@@ -0,0 +1,482 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// TournamentStatus represents where a tournament is in its lifecycle
+type TournamentStatus string
+
+const (
+	TournamentRegistering TournamentStatus = "registering" // Open for registration
+	TournamentRunning     TournamentStatus = "running"     // Elimination rounds
+	TournamentFinal       TournamentStatus = "final"       // Final table plays its last round
+	TournamentClosed      TournamentStatus = "closed"      // Prizes paid out
+)
+
+// TxPrize is a tournament prize paid into the real balance
+const TxPrize TxKind = "prize"
+
+var (
+	ErrTournamentNotOpen    = errors.New("tournament is not open for registration")
+	ErrTournamentNotRunning = errors.New("tournament is not running")
+	ErrTournamentClosed     = errors.New("tournament is closed")
+	ErrAlreadyRegistered    = errors.New("player is already registered")
+	ErrNotRegistered        = errors.New("player is not registered")
+	ErrEliminated           = errors.New("player has been eliminated")
+	ErrRoundComplete        = errors.New("all hands of this round have been played")
+	ErrHandInProgress       = errors.New("finish the current tournament hand first")
+	ErrNotEnoughEntrants    = errors.New("at least two entrants are required")
+	ErrInvalidTournament    = errors.New("invalid tournament settings")
+)
+
+// TournamentConfig configures a tournament
+type TournamentConfig struct {
+	Name              string `json:"name"`
+	StartingStack     int    `json:"starting_stack"`      // Chips each entrant starts with
+	HandsPerRound     int    `json:"hands_per_round"`     // Hands every entrant plays before eliminations
+	EliminatePerRound int    `json:"eliminate_per_round"` // Lowest stacks dropped at each round boundary
+	FinalTableSize    int    `json:"final_table_size"`    // Entrants left when the final table starts
+	Prizes            []int  `json:"prizes"`              // Prize for 1st, 2nd, ... place
+
+	// RoundTimeout is how long entrants have to play a round; missing hands are sat out
+	RoundTimeout time.Duration `json:"round_timeout"`
+}
+
+// DefaultRoundTimeout applies when a tournament sets no round timeout
+const DefaultRoundTimeout = 10 * time.Minute
+
+// Validate checks that a tournament can be run with these settings
+func (c TournamentConfig) Validate() error {
+	if c.StartingStack < 1 || c.HandsPerRound < 1 || c.EliminatePerRound < 1 || c.FinalTableSize < 1 || c.RoundTimeout < 0 {
+		return ErrInvalidTournament
+	}
+	for _, prize := range c.Prizes {
+		if prize < 0 {
+			return ErrInvalidTournament
+		}
+	}
+	return nil
+}
+
+// Entrant is a registered player and their tournament chips, separate from Player.Balance
+type Entrant struct {
+	PlayerID    string `json:"player_id"`
+	Stack       int    `json:"stack"`
+	HandsPlayed int    `json:"hands_played"` // In the current round
+	TotalHands  int    `json:"total_hands"`
+	OpenGame    string `json:"open_game,omitempty"` // Game currently being played
+	Eliminated  bool   `json:"eliminated"`
+	OutInRound  int    `json:"out_in_round,omitempty"` // Round the entrant was eliminated in
+	PrizePaid   bool   `json:"prize_paid,omitempty"`   // Set by Close, so that closing again does not pay twice
+}
+
+// Standing is an entrant's position on the leaderboard
+type Standing struct {
+	Place int `json:"place"`
+	Entrant
+	Prize int `json:"prize"`
+}
+
+// TournamentState is the serializable state of a tournament
+type TournamentState struct {
+	ID        string           `json:"id"`
+	Config    TournamentConfig `json:"config"`
+	Status    TournamentStatus `json:"status"`
+	Round     int              `json:"round"`
+	Entrants  []Entrant        `json:"entrants"` // In registration order
+	CreatedAt time.Time        `json:"created_at"`
+	StartedAt time.Time        `json:"started_at,omitempty"`
+	ClosedAt  time.Time        `json:"closed_at,omitempty"`
+
+	RoundDeadline time.Time `json:"round_deadline,omitempty"` // When the current round ends at the latest
+}
+
+// Tournament is a series of elimination rounds played with tournament chips.
+// All methods are safe for concurrent use.
+type Tournament struct {
+	mu     sync.Mutex
+	ledger *Ledger
+	state  TournamentState
+}
+
+// NewTournament creates a tournament open for registration
+func NewTournament(id string, config TournamentConfig, ledger *Ledger) *Tournament {
+	return &Tournament{
+		ledger: ledger,
+		state: TournamentState{
+			ID:        id,
+			Config:    config,
+			Status:    TournamentRegistering,
+			Entrants:  []Entrant{},
+			CreatedAt: time.Now(),
+		},
+	}
+}
+
+// RestoreTournament recreates a tournament from saved state
+func RestoreTournament(state TournamentState, ledger *Ledger) *Tournament {
+	return &Tournament{ledger: ledger, state: state}
+}
+
+// ID returns the tournament ID
+func (t *Tournament) ID() string { return t.state.ID }
+
+// State returns a copy of the tournament state
+func (t *Tournament) State() TournamentState {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.copyState()
+}
+
+func (t *Tournament) copyState() TournamentState {
+	state := t.state
+	state.Entrants = append([]Entrant(nil), t.state.Entrants...)
+	state.Config.Prizes = append([]int(nil), t.state.Config.Prizes...)
+	return state
+}
+
+// Register enters a player with the starting stack
+func (t *Tournament) Register(playerID string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.state.Status != TournamentRegistering {
+		return ErrTournamentNotOpen
+	}
+	if t.entrant(playerID) != nil {
+		return ErrAlreadyRegistered
+	}
+	t.state.Entrants = append(t.state.Entrants, Entrant{PlayerID: playerID, Stack: t.state.Config.StartingStack})
+	return nil
+}
+
+// Start closes registration and begins the first round
+func (t *Tournament) Start(now time.Time) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.state.Status != TournamentRegistering {
+		return ErrTournamentNotOpen
+	}
+	if len(t.state.Entrants) < 2 {
+		return ErrNotEnoughEntrants
+	}
+	t.state.Status = TournamentRunning
+	t.state.Round = 1
+	t.state.StartedAt = now
+	t.state.RoundDeadline = now.Add(t.roundTimeout())
+	// A small field goes straight to the final table
+	if t.active() <= t.state.Config.FinalTableSize {
+		t.state.Status = TournamentFinal
+	}
+	return nil
+}
+
+// Bet takes chips from an entrant's stack for gameID.
+// The first bet of a game opens a hand; later ones (e.g. a split) add to it.
+func (t *Tournament) Bet(playerID, gameID string, amount int) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	e, err := t.playing(playerID)
+	if err != nil {
+		return err
+	}
+	if amount < 1 {
+		return ErrInvalidAmount
+	}
+	if e.OpenGame != gameID {
+		if e.OpenGame != "" {
+			return ErrHandInProgress
+		}
+		if e.HandsPlayed >= t.state.Config.HandsPerRound {
+			return ErrRoundComplete
+		}
+	}
+	if e.Stack < amount {
+		return ErrInsufficientFunds
+	}
+	e.Stack -= amount
+	e.OpenGame = gameID
+	return nil
+}
+
+// Win gives chips back to an entrant's stack
+func (t *Tournament) Win(playerID string, amount int) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	e, err := t.playing(playerID)
+	if err != nil {
+		return err
+	}
+	if amount < 1 {
+		return ErrInvalidAmount
+	}
+	e.Stack += amount
+	return nil
+}
+
+// HandFinished counts the hand played in gameID and applies eliminations once the round is over
+func (t *Tournament) HandFinished(playerID, gameID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	e := t.entrant(playerID)
+	if e == nil || e.OpenGame != gameID || t.state.Status == TournamentClosed {
+		return
+	}
+	e.OpenGame = ""
+	e.HandsPlayed++
+	e.TotalHands++
+	// Busted entrants cannot play on
+	if e.Stack == 0 {
+		t.eliminate(e)
+	}
+	if t.roundComplete() {
+		t.nextRound(time.Now())
+	}
+}
+
+// HandCancelled frees an entrant for another hand after the bets of gameID were refunded; it does not count
+func (t *Tournament) HandCancelled(playerID, gameID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if e := t.entrant(playerID); e != nil && e.OpenGame == gameID {
+		e.OpenGame = ""
+	}
+}
+
+// Tick ends a round whose deadline has passed.
+// Entrants sit out the hands they have not played; a hand still in progress is their last of the round.
+func (t *Tournament) Tick(now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.state.Status != TournamentRunning && t.state.Status != TournamentFinal {
+		return
+	}
+	// Once the final round is played there is nothing left to wait for
+	if t.state.Status == TournamentFinal && t.roundComplete() {
+		t.state.RoundDeadline = time.Time{}
+		return
+	}
+	if !now.After(t.state.RoundDeadline) {
+		return
+	}
+	hands := t.state.Config.HandsPerRound
+	for i := range t.state.Entrants {
+		e := &t.state.Entrants[i]
+		if e.Eliminated {
+			continue
+		}
+		if e.OpenGame != "" {
+			e.HandsPlayed = max(e.HandsPlayed, hands-1)
+		} else {
+			e.HandsPlayed = hands
+		}
+	}
+	if t.roundComplete() {
+		t.nextRound(now)
+	}
+}
+
+// Stack returns the chips of an entrant
+func (t *Tournament) Stack(playerID string) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if e := t.entrant(playerID); e != nil {
+		return e.Stack
+	}
+	return 0
+}
+
+// Leaderboard returns the entrants from first to last place.
+// Prizes are those the entrants would win if the tournament closed now.
+func (t *Tournament) Leaderboard() []Standing {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.standings()
+}
+
+// Close ends the tournament and pays the prizes into the real balances.
+// Chips still at stake in open hands count as lost. A prize that cannot be paid does not hold up
+// the others; closing the tournament again pays the ones still missing.
+func (t *Tournament) Close(now time.Time) ([]Standing, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	switch t.state.Status {
+	case TournamentClosed:
+		if t.prizesPaid() {
+			return nil, ErrTournamentClosed
+		}
+	case TournamentRegistering:
+		// Nobody has played a hand, so there is nothing to award
+		return nil, ErrTournamentNotRunning
+	default:
+		// The standings are final once play stops
+		t.state.Status = TournamentClosed
+		t.state.ClosedAt = now
+		t.state.RoundDeadline = time.Time{}
+	}
+
+	standings := t.standings()
+	var errs []error
+	for _, s := range standings {
+		if s.Prize == 0 || s.PrizePaid || t.ledger == nil {
+			continue
+		}
+		reason := fmt.Sprintf("tournament %s: place %d", t.state.Config.Name, s.Place)
+		if _, err := t.ledger.Credit(s.PlayerID, TxPrize, "", s.Prize, reason); err != nil {
+			errs = append(errs, fmt.Errorf("prize for place %d: %w", s.Place, err))
+			continue
+		}
+		t.entrant(s.PlayerID).PrizePaid = true
+	}
+	return standings, errors.Join(errs...)
+}
+
+// prizesPaid reports whether every prize won has been paid; callers hold the lock
+func (t *Tournament) prizesPaid() bool {
+	for _, s := range t.standings() {
+		if s.Prize > 0 && !s.PrizePaid && t.ledger != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// playing returns an entrant that may still play; callers hold the lock
+func (t *Tournament) playing(playerID string) (*Entrant, error) {
+	switch t.state.Status {
+	case TournamentClosed:
+		return nil, ErrTournamentClosed
+	case TournamentRegistering:
+		return nil, ErrTournamentNotRunning
+	}
+	e := t.entrant(playerID)
+	if e == nil {
+		return nil, ErrNotRegistered
+	}
+	if e.Eliminated {
+		return nil, ErrEliminated
+	}
+	return e, nil
+}
+
+func (t *Tournament) entrant(playerID string) *Entrant {
+	for i := range t.state.Entrants {
+		if t.state.Entrants[i].PlayerID == playerID {
+			return &t.state.Entrants[i]
+		}
+	}
+	return nil
+}
+
+func (t *Tournament) eliminate(e *Entrant) {
+	e.Eliminated = true
+	e.OutInRound = t.state.Round
+}
+
+// active counts the entrants that are still in
+func (t *Tournament) active() int {
+	n := 0
+	for _, e := range t.state.Entrants {
+		if !e.Eliminated {
+			n++
+		}
+	}
+	return n
+}
+
+// roundComplete reports whether every remaining entrant has played the round
+func (t *Tournament) roundComplete() bool {
+	for _, e := range t.state.Entrants {
+		if !e.Eliminated && (e.OpenGame != "" || e.HandsPlayed < t.state.Config.HandsPerRound) {
+			return false
+		}
+	}
+	return true
+}
+
+// nextRound drops the lowest stacks and starts the next round.
+// Eliminations never cut the field below the final table; the final round is the last one.
+func (t *Tournament) nextRound(now time.Time) {
+	if t.state.Status != TournamentRunning {
+		t.state.RoundDeadline = time.Time{}
+		return
+	}
+
+	// Latest registrant first, so that the stable sort below puts them first on a tie
+	var remaining []*Entrant
+	for i := len(t.state.Entrants) - 1; i >= 0; i-- {
+		if !t.state.Entrants[i].Eliminated {
+			remaining = append(remaining, &t.state.Entrants[i])
+		}
+	}
+	// Lowest stack first; on a tie the later registrant goes out first
+	sort.SliceStable(remaining, func(i, j int) bool { return remaining[i].Stack < remaining[j].Stack })
+	cut := min(t.state.Config.EliminatePerRound, len(remaining)-t.state.Config.FinalTableSize)
+	for i := cut - 1; i >= 0; i-- {
+		t.eliminate(remaining[i])
+	}
+
+	t.state.Round++
+	t.state.RoundDeadline = now.Add(t.roundTimeout())
+	for i := range t.state.Entrants {
+		t.state.Entrants[i].HandsPlayed = 0
+	}
+	if t.active() <= t.state.Config.FinalTableSize {
+		t.state.Status = TournamentFinal
+	}
+}
+
+func (t *Tournament) roundTimeout() time.Duration {
+	if t.state.Config.RoundTimeout > 0 {
+		return t.state.Config.RoundTimeout
+	}
+	return DefaultRoundTimeout
+}
+
+// standings orders the entrants: remaining ones by stack, then by how late they went out
+func (t *Tournament) standings() []Standing {
+	entrants := append([]Entrant(nil), t.state.Entrants...)
+	sort.SliceStable(entrants, func(i, j int) bool {
+		a, b := entrants[i], entrants[j]
+		if a.Eliminated != b.Eliminated {
+			return !a.Eliminated
+		}
+		if a.OutInRound != b.OutInRound {
+			return a.OutInRound > b.OutInRound
+		}
+		return a.Stack > b.Stack
+	})
+
+	standings := make([]Standing, len(entrants))
+	for i, e := range entrants {
+		standings[i] = Standing{Place: i + 1, Entrant: e}
+		if i < len(t.state.Config.Prizes) {
+			standings[i].Prize = t.state.Config.Prizes[i]
+		}
+	}
+	return standings
+}
+
+// tournamentWallet plays with an entrant's tournament chips
+type tournamentWallet struct {
+	tournament *Tournament
+	playerID   string
+}
+
+func (w tournamentWallet) Debit(kind TxKind, gameID string, amount int, reason string) error {
+	return w.tournament.Bet(w.playerID, gameID, amount)
+}
+
+func (w tournamentWallet) Credit(kind TxKind, gameID string, amount int, reason string) error {
+	return w.tournament.Win(w.playerID, amount)
+}
+
+func (w tournamentWallet) Balance() int { return w.tournament.Stack(w.playerID) }
+
+func (w tournamentWallet) Settled(gameID string) { w.tournament.HandFinished(w.playerID, gameID) }
+
+func (w tournamentWallet) Cancelled(gameID string) { w.tournament.HandCancelled(w.playerID, gameID) }
@@ -0,0 +1,103 @@
+package game
+
+import (
+	"sync"
+	"time"
+)
+
+// TournamentStore is a thread-safe in-memory store for tournaments.
+// Once started it also ends every round whose deadline has passed.
+type TournamentStore struct {
+	mu          sync.RWMutex
+	tournaments map[string]*Tournament
+	ledger      *Ledger
+
+	stop    chan struct{}
+	done    chan struct{}
+	once    sync.Once
+	started bool
+}
+
+// NewTournamentStore creates a new TournamentStore whose prizes are paid through ledger
+func NewTournamentStore(ledger *Ledger) *TournamentStore {
+	return &TournamentStore{
+		tournaments: make(map[string]*Tournament),
+		ledger:      ledger,
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+}
+
+// Create creates and stores a tournament open for registration
+func (s *TournamentStore) Create(id string, config TournamentConfig) *Tournament {
+	t := NewTournament(id, config, s.ledger)
+	s.save(t)
+	return t
+}
+
+// Restore stores a tournament recreated from saved state
+func (s *TournamentStore) Restore(state TournamentState) *Tournament {
+	t := RestoreTournament(state, s.ledger)
+	s.save(t)
+	return t
+}
+
+func (s *TournamentStore) save(t *Tournament) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tournaments[t.ID()] = t
+}
+
+// Get retrieves a tournament by ID
+func (s *TournamentStore) Get(id string) (*Tournament, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	t, exists := s.tournaments[id]
+	return t, exists
+}
+
+// All returns every stored tournament
+func (s *TournamentStore) All() []*Tournament {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	tournaments := make([]*Tournament, 0, len(s.tournaments))
+	for _, t := range s.tournaments {
+		tournaments = append(tournaments, t)
+	}
+	return tournaments
+}
+
+// Tick applies the round deadlines of every tournament
+func (s *TournamentStore) Tick(now time.Time) {
+	for _, t := range s.All() {
+		t.Tick(now)
+	}
+}
+
+// Start ticks every tournament at the given interval in the background
+func (s *TournamentStore) Start(interval time.Duration) {
+	s.started = true
+	go func() {
+		defer close(s.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case now := <-ticker.C:
+				s.Tick(now)
+			case <-s.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop ends the background ticking and waits for it to exit
+func (s *TournamentStore) Stop() {
+	s.once.Do(func() {
+		close(s.stop)
+		if s.started {
+			<-s.done
+		}
+	})
+}
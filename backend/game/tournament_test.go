@@ -0,0 +1,248 @@
+package game
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// playHand bets and settles one tournament hand; net is what the hand won or lost
+func playHand(t *testing.T, tour *Tournament, playerID, gameID string, bet, net int) {
+	t.Helper()
+	if err := tour.Bet(playerID, gameID, bet); err != nil {
+		t.Fatalf("%s: bet failed: %v", playerID, err)
+	}
+	if payout := bet + net; payout > 0 {
+		if err := tour.Win(playerID, payout); err != nil {
+			t.Fatalf("%s: win failed: %v", playerID, err)
+		}
+	}
+	tour.HandFinished(playerID, gameID)
+}
+
+func TestTournamentRounds(t *testing.T) {
+	players := NewPlayerStore()
+	ledger := NewLedger(players)
+	for _, id := range []string{"a", "b", "c", "d"} {
+		players.Save(&Player{ID: id})
+	}
+
+	tour := NewTournament("t1", TournamentConfig{
+		Name: "Sunday", StartingStack: 100, HandsPerRound: 1, EliminatePerRound: 1, FinalTableSize: 2, Prizes: []int{50, 20},
+	}, ledger)
+	for _, id := range []string{"a", "b", "c", "d"} {
+		if err := tour.Register(id); err != nil {
+			t.Fatalf("register %s: %v", id, err)
+		}
+	}
+	if err := tour.Register("a"); !errors.Is(err, ErrAlreadyRegistered) {
+		t.Errorf("Expected ErrAlreadyRegistered, got %v", err)
+	}
+	if err := tour.Bet("a", "g0", 10); !errors.Is(err, ErrTournamentNotRunning) {
+		t.Errorf("Expected ErrTournamentNotRunning before start, got %v", err)
+	}
+	if err := tour.Start(time.Now()); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+
+	// Round 1: d has the lowest stack and goes out
+	playHand(t, tour, "a", "a1", 10, 10)
+	playHand(t, tour, "b", "b1", 10, 0)
+	playHand(t, tour, "c", "c1", 10, -5)
+	if err := tour.Bet("a", "a2", 10); !errors.Is(err, ErrRoundComplete) {
+		t.Errorf("Expected ErrRoundComplete, got %v", err)
+	}
+	playHand(t, tour, "d", "d1", 20, -20)
+
+	state := tour.State()
+	if state.Round != 2 || state.Status != TournamentRunning {
+		t.Fatalf("Expected running round 2, got %s round %d", state.Status, state.Round)
+	}
+	if err := tour.Bet("d", "d2", 10); !errors.Is(err, ErrEliminated) {
+		t.Errorf("Expected ErrEliminated, got %v", err)
+	}
+
+	// Round 2: c goes out and the final table is reached
+	playHand(t, tour, "a", "a2", 10, -10)
+	playHand(t, tour, "b", "b2", 10, 0)
+	playHand(t, tour, "c", "c2", 10, -10)
+	if state := tour.State(); state.Status != TournamentFinal || state.Round != 3 {
+		t.Fatalf("Expected final table in round 3, got %s round %d", state.Status, state.Round)
+	}
+
+	playHand(t, tour, "a", "a3", 50, 50)
+	playHand(t, tour, "b", "b3", 10, -10)
+	standings, err := tour.Close(time.Now())
+	if err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	order := []string{"a", "b", "c", "d"}
+	for i, s := range standings {
+		if s.PlayerID != order[i] || s.Place != i+1 {
+			t.Errorf("Place %d: expected %s, got %s", i+1, order[i], s.PlayerID)
+		}
+	}
+
+	// Prizes go to the real balance; tournament chips never do
+	for id, want := range map[string]int{"a": 50, "b": 20, "c": 0, "d": 0} {
+		if p, _ := players.Get(id); p.Balance != want {
+			t.Errorf("%s: expected balance %d, got %d", id, want, p.Balance)
+		}
+	}
+	if _, err := tour.Close(time.Now()); !errors.Is(err, ErrTournamentClosed) {
+		t.Errorf("Expected ErrTournamentClosed, got %v", err)
+	}
+}
+
+func TestTournamentCloseRetriesUnpaidPrizes(t *testing.T) {
+	players := NewPlayerStore()
+	players.Save(&Player{ID: "a"})
+	tour := NewTournament("t1", TournamentConfig{StartingStack: 100, HandsPerRound: 1, EliminatePerRound: 1, FinalTableSize: 1, Prizes: []int{50, 20}}, NewLedger(players))
+	tour.Register("a")
+	tour.Register("b")
+	tour.Start(time.Now())
+	playHand(t, tour, "a", "a1", 10, 10)
+
+	// b's prize cannot be paid, which does not keep a from being paid
+	if _, err := tour.Close(time.Now()); err == nil {
+		t.Fatal("Expected an error for the unpaid prize")
+	}
+	if state := tour.State(); state.Status != TournamentClosed {
+		t.Errorf("Expected play to stop, got %s", state.Status)
+	}
+	if err := tour.Bet("b", "b1", 10); !errors.Is(err, ErrTournamentClosed) {
+		t.Errorf("Expected ErrTournamentClosed, got %v", err)
+	}
+
+	// Closing again pays only the missing prize
+	players.Save(&Player{ID: "b"})
+	if _, err := tour.Close(time.Now()); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	for id, want := range map[string]int{"a": 50, "b": 20} {
+		if p, _ := players.Get(id); p.Balance != want {
+			t.Errorf("%s: expected balance %d, got %d", id, want, p.Balance)
+		}
+	}
+	if _, err := tour.Close(time.Now()); !errors.Is(err, ErrTournamentClosed) {
+		t.Errorf("Expected ErrTournamentClosed once every prize is paid, got %v", err)
+	}
+}
+
+func TestTournamentOneHandAtATime(t *testing.T) {
+	tour := NewTournament("t1", TournamentConfig{StartingStack: 20, HandsPerRound: 3, EliminatePerRound: 1, FinalTableSize: 1}, nil)
+	tour.Register("a")
+	tour.Register("b")
+	tour.Start(time.Now())
+
+	if err := tour.Bet("a", "g1", 10); err != nil {
+		t.Fatalf("bet: %v", err)
+	}
+	if err := tour.Bet("a", "g2", 5); !errors.Is(err, ErrHandInProgress) {
+		t.Errorf("Expected ErrHandInProgress, got %v", err)
+	}
+	// A cancelled hand frees the entrant without counting
+	tour.Bet("b", "b1", 5)
+	tour.Win("b", 5)
+	tour.HandCancelled("b", "b1")
+	if err := tour.Bet("b", "b2", 5); err != nil || tour.State().Entrants[1].HandsPlayed != 0 {
+		t.Errorf("Expected b to start another hand with none played, got %v", err)
+	}
+	// A split adds to the open hand
+	if err := tour.Bet("a", "g1", 10); err != nil {
+		t.Errorf("Expected the split to be accepted, got %v", err)
+	}
+	if err := tour.Bet("a", "g1", 10); !errors.Is(err, ErrInsufficientFunds) {
+		t.Errorf("Expected ErrInsufficientFunds, got %v", err)
+	}
+
+	// Losing the whole stack eliminates right away
+	tour.HandFinished("a", "g1")
+	if s := tour.Leaderboard(); s[len(s)-1].PlayerID != "a" || !s[len(s)-1].Eliminated {
+		t.Errorf("Expected a to be eliminated in last place, got %+v", s)
+	}
+}
+
+func TestTournamentRoundDeadline(t *testing.T) {
+	tour := NewTournament("t1", TournamentConfig{StartingStack: 100, HandsPerRound: 2, EliminatePerRound: 1, FinalTableSize: 1, RoundTimeout: time.Minute}, nil)
+	for _, id := range []string{"a", "b", "c"} {
+		tour.Register(id)
+	}
+	now := time.Now()
+	tour.Start(now)
+
+	// a finishes the round, b is mid-hand and c never shows up
+	playHand(t, tour, "a", "a1", 10, 10)
+	playHand(t, tour, "a", "a2", 10, 10)
+	if err := tour.Bet("b", "b1", 10); err != nil {
+		t.Fatalf("bet: %v", err)
+	}
+
+	tour.Tick(now.Add(30 * time.Second))
+	if state := tour.State(); state.Round != 1 {
+		t.Fatalf("Expected the round to wait for its deadline, got round %d", state.Round)
+	}
+
+	tour.Tick(now.Add(2 * time.Minute))
+	if err := tour.Bet("c", "c1", 10); !errors.Is(err, ErrRoundComplete) {
+		t.Errorf("Expected c to sit out the rest of the round, got %v", err)
+	}
+	if state := tour.State(); state.Round != 1 {
+		t.Fatalf("Expected the round to wait for the hand in progress, got round %d", state.Round)
+	}
+
+	// The hand in progress is b's last; the round then ends and the lowest stack goes out
+	tour.Win("b", 20)
+	tour.HandFinished("b", "b1")
+	state := tour.State()
+	if state.Round != 2 || !state.RoundDeadline.After(now) {
+		t.Fatalf("Expected round 2 with a new deadline, got round %d ending %v", state.Round, state.RoundDeadline)
+	}
+	if s := tour.Leaderboard(); s[len(s)-1].PlayerID != "c" || !s[len(s)-1].Eliminated {
+		t.Errorf("Expected c to be eliminated, got %+v", s)
+	}
+}
+
+func TestTournamentTieEliminatesLaterRegistrant(t *testing.T) {
+	tour := NewTournament("t1", TournamentConfig{StartingStack: 100, HandsPerRound: 1, EliminatePerRound: 1, FinalTableSize: 1}, nil)
+	for _, id := range []string{"a", "b", "c"} {
+		tour.Register(id)
+	}
+	if _, err := tour.Close(time.Now()); !errors.Is(err, ErrTournamentNotRunning) {
+		t.Errorf("Expected closing during registration to fail, got %v", err)
+	}
+	tour.Start(time.Now())
+
+	// a and b tie on the lowest stack; b registered later
+	playHand(t, tour, "a", "a1", 10, -10)
+	playHand(t, tour, "b", "b1", 10, -10)
+	playHand(t, tour, "c", "c1", 10, 0)
+	for _, s := range tour.Leaderboard() {
+		if s.Eliminated != (s.PlayerID == "b") {
+			t.Errorf("Expected only b to go out on the tie, got %+v", s)
+		}
+	}
+}
+
+func TestTournamentFinalRoundDeadlineClears(t *testing.T) {
+	tour := NewTournament("t1", TournamentConfig{StartingStack: 100, HandsPerRound: 1, EliminatePerRound: 1, FinalTableSize: 2}, nil)
+	tour.Register("a")
+	tour.Register("b")
+	now := time.Now()
+	tour.Start(now)
+	if state := tour.State(); state.Status != TournamentFinal || state.RoundDeadline.IsZero() {
+		t.Fatalf("Expected the final round to have a deadline, got %s ending %v", state.Status, state.RoundDeadline)
+	}
+
+	playHand(t, tour, "a", "a1", 10, 0)
+	playHand(t, tour, "b", "b1", 10, 0)
+	tour.Tick(now.Add(time.Second))
+	if state := tour.State(); !state.RoundDeadline.IsZero() {
+		t.Errorf("Expected no deadline once the final round is played, got %v", state.RoundDeadline)
+	}
+	tour.Close(now)
+	tour.Tick(now.Add(DefaultRoundTimeout * 2))
+	if state := tour.State(); !state.RoundDeadline.IsZero() {
+		t.Errorf("Expected no deadline once closed, got %v", state.RoundDeadline)
+	}
+}
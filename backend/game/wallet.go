@@ -0,0 +1,58 @@
+package game
+
+// Wallet is where the stake of a game comes from and where its winnings go
+type Wallet interface {
+	Debit(kind TxKind, gameID string, amount int, reason string) error
+	Credit(kind TxKind, gameID string, amount int, reason string) error
+	Balance() int
+	// Settled is called once the game is over
+	Settled(gameID string)
+	// Cancelled is called instead of Settled for a game whose bets could not all be placed,
+	// once the ones that were are refunded
+	Cancelled(gameID string)
+}
+
+// ledgerWallet plays with a player's real balance
+type ledgerWallet struct {
+	ledger   *Ledger
+	playerID string
+}
+
+func (w ledgerWallet) Debit(kind TxKind, gameID string, amount int, reason string) error {
+	_, err := w.ledger.Debit(w.playerID, kind, gameID, amount, reason)
+	return err
+}
+
+func (w ledgerWallet) Credit(kind TxKind, gameID string, amount int, reason string) error {
+	_, err := w.ledger.Credit(w.playerID, kind, gameID, amount, reason)
+	return err
+}
+
+func (w ledgerWallet) Balance() int {
+	return w.ledger.players.Balance(w.playerID)
+}
+
+func (w ledgerWallet) Settled(string) {}
+
+func (w ledgerWallet) Cancelled(string) {}
+
+// Wallets resolves the wallet a game plays with: a tournament stack or the player's balance
+type Wallets struct {
+	Ledger      *Ledger
+	Tournaments *TournamentStore // Optional
+}
+
+// For returns the wallet of a player, inside tournamentID if it is set
+func (w Wallets) For(playerID, tournamentID string) Wallet {
+	if tournamentID != "" && w.Tournaments != nil {
+		if t, ok := w.Tournaments.Get(tournamentID); ok {
+			return tournamentWallet{tournament: t, playerID: playerID}
+		}
+	}
+	return ledgerWallet{ledger: w.Ledger, playerID: playerID}
+}
+
+// Of returns the wallet a game plays with
+func (w Wallets) Of(g *GameState) Wallet {
+	return w.For(g.PlayerID, g.TournamentID)
+}
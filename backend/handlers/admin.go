@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// streamTokenTTL is how long a stream token may be used to connect; open streams outlive it
+const streamTokenTTL = 5 * time.Minute
+
+// RequireAdmin only lets requests through whose X-Admin-Token matches token.
+// With an empty token the admin API is disabled.
+func RequireAdmin(token string) gin.HandlerFunc {
+	return requireAdmin(token, false)
+}
+
+// RequireAdminStream is RequireAdmin for event streams. EventSource cannot set
+// headers, so it also accepts a ?token= issued by IssueStreamToken; that token
+// only opens streams and expires quickly, so the admin token never ends up in a URL.
+func RequireAdminStream(token string) gin.HandlerFunc {
+	return requireAdmin(token, true)
+}
+
+func requireAdmin(token string, streamToken bool) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if token == "" {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Admin API is disabled"})
+			return
+		}
+		given := ctx.GetHeader("X-Admin-Token")
+		if subtle.ConstantTimeCompare([]byte(given), []byte(token)) == 1 {
+			ctx.Next()
+			return
+		}
+		if streamToken && verifyStreamToken(token, ctx.Query("token"), time.Now()) {
+			ctx.Next()
+			return
+		}
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid admin token"})
+	}
+}
+
+// IssueStreamToken handles POST /api/admin/stream-token, behind RequireAdmin
+func IssueStreamToken(token string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		expires := time.Now().Add(streamTokenTTL)
+		ctx.JSON(http.StatusCreated, gin.H{"token": signStreamToken(token, expires), "expires_at": expires})
+	}
+}
+
+// signStreamToken returns "<expiry>.<signature>", signed with the admin token
+func signStreamToken(token string, expires time.Time) string {
+	unix := strconv.FormatInt(expires.Unix(), 10)
+	return unix + "." + streamSignature(token, unix)
+}
+
+// verifyStreamToken checks the signature and that the token has not expired
+func verifyStreamToken(token, given string, now time.Time) bool {
+	unix, signature, ok := strings.Cut(given, ".")
+	if !ok {
+		return false
+	}
+	expires, err := strconv.ParseInt(unix, 10, 64)
+	if err != nil || now.Unix() > expires {
+		return false
+	}
+	return hmac.Equal([]byte(signature), []byte(streamSignature(token, unix)))
+}
+
+func streamSignature(token, unix string) string {
+	mac := hmac.New(sha256.New, []byte(token))
+	mac.Write([]byte("stream:" + unix))
+	return hex.EncodeToString(mac.Sum(nil))
+}
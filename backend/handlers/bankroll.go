@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"blackjack-api/game"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BankrollResponse DTO: the policy in force and the player's grants
+type BankrollResponse struct {
+	Policy      game.BankrollPolicy `json:"policy"`
+	Amount      int                 `json:"amount"`                  // Chips per claim
+	Cooldown    string              `json:"cooldown"`                // Between two claims, e.g. "24h0m0s"
+	NextClaimAt *time.Time          `json:"next_claim_at,omitempty"` // Set while cooling down
+	Balance     int                 `json:"balance"`
+	Grants      []game.Grant        `json:"grants"` // Newest first
+}
+
+// AdminGrantRequest DTO
+type AdminGrantRequest struct {
+	Amount int    `json:"amount" binding:"required,gt=0"`
+	Note   string `json:"note"`
+}
+
+// GetBankroll handles GET /api/players/me/bankroll
+func (c *GameController) GetBankroll(ctx *gin.Context) {
+	player, ok := c.currentPlayer(ctx)
+	if !ok {
+		return
+	}
+	ctx.JSON(http.StatusOK, c.bankrollView(player))
+}
+
+// ClaimGrant handles POST /api/players/me/bankroll/claim: the daily or ad grant, depending on the policy
+func (c *GameController) ClaimGrant(ctx *gin.Context) {
+	player, ok := c.currentPlayer(ctx)
+	if !ok {
+		return
+	}
+
+	grant, err := c.Bankroll.Claim(player.ID, time.Now())
+	switch {
+	case errors.Is(err, game.ErrClaimsDisabled):
+		ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, game.ErrClaimCooldown):
+		ctx.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error(), "next_claim_at": c.Bankroll.NextClaim(player.ID)})
+		return
+	case err != nil:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusCreated, gin.H{"grant": grant, "balance": c.PlayerStore.Balance(player.ID)})
+}
+
+// AdminGrant handles POST /api/admin/players/:id/grants
+func (c *GameController) AdminGrant(ctx *gin.Context) {
+	var req AdminGrantRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	player, exists := c.PlayerStore.Get(ctx.Param("id"))
+	if !exists {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Player not found"})
+		return
+	}
+
+	grant, err := c.Bankroll.AdminGrant(player.ID, req.Amount, req.Note, time.Now())
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusCreated, gin.H{"grant": grant, "balance": c.PlayerStore.Balance(player.ID)})
+}
+
+// bankrollView builds the bankroll response for a player
+func (c *GameController) bankrollView(player *game.Player) BankrollResponse {
+	config := c.Bankroll.Config()
+	view := BankrollResponse{
+		Policy:   config.Policy,
+		Amount:   config.Amount,
+		Cooldown: config.Cooldown.String(),
+		Balance:  player.Balance,
+		Grants:   c.Bankroll.Grants(player.ID),
+	}
+	if next := c.Bankroll.NextClaim(player.ID); time.Now().Before(next) {
+		view.NextClaimAt = &next
+	}
+	return view
+}
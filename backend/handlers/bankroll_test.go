@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"blackjack-api/game"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestBankroll(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	controller := NewGameController()
+	router := gin.Default()
+	router.POST("/api/games", controller.StartGame)
+	router.GET("/api/players/me/bankroll", controller.GetBankroll)
+	router.POST("/api/players/me/bankroll/claim", controller.ClaimGrant)
+	admin := router.Group("/api/admin", RequireAdmin("secret"))
+	admin.POST("/players/:id/grants", controller.AdminGrant)
+
+	do := func(method, url, body string, headers map[string]string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, url, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	player := map[string]string{"X-Player-ID": "broke"}
+
+	// A broke player is no longer topped up behind their back
+	controller.getOrCreatePlayer("broke")
+	controller.Ledger.Debit("broke", game.TxBet, "lost", 100, "bet")
+	if w := do("POST", "/api/games", `{"bet_amount": 5}`, player); w.Code != http.StatusBadRequest {
+		t.Fatalf("Expected 400 without chips, got %d: %s", w.Code, w.Body.String())
+	}
+
+	if w := do("POST", "/api/players/me/bankroll/claim", "", map[string]string{"X-Player-ID": "someone"}); w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 claiming for an unknown player, got %d", w.Code)
+	}
+	w := do("POST", "/api/players/me/bankroll/claim", "", player)
+	if w.Code != http.StatusCreated || !bytes.Contains(w.Body.Bytes(), []byte(`"balance":100`)) {
+		t.Fatalf("Expected the daily grant, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := do("POST", "/api/players/me/bankroll/claim", "", player); w.Code != http.StatusTooManyRequests {
+		t.Errorf("Expected 429 during the cooldown, got %d", w.Code)
+	}
+
+	if w := do("POST", "/api/admin/players/broke/grants", `{"amount": -500}`, map[string]string{"X-Admin-Token": "secret"}); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a negative grant, got %d", w.Code)
+	}
+	if w := do("POST", "/api/admin/players/broke/grants", `{"amount": 40, "note": "goodwill"}`, map[string]string{"X-Admin-Token": "secret"}); w.Code != http.StatusCreated {
+		t.Fatalf("Expected 201 for an admin grant, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var bankroll BankrollResponse
+	json.Unmarshal(do("GET", "/api/players/me/bankroll", "", player).Body.Bytes(), &bankroll)
+	if bankroll.Policy != game.BankrollDaily || bankroll.Balance != 140 || bankroll.NextClaimAt == nil {
+		t.Errorf("Expected the daily policy cooling down at 140, got %+v", bankroll)
+	}
+	if len(bankroll.Grants) != 2 || bankroll.Grants[0].Source != game.GrantAdmin || bankroll.Grants[0].Note != "goodwill" {
+		t.Errorf("Expected both grants, newest first, got %+v", bankroll.Grants)
+	}
+}
@@ -2,38 +2,94 @@ package handlers
 
 import (
 	"blackjack-api/game"
+	"errors"
+	"fmt"
+	"log"
 	"net/http"
+	"sync"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
 
 type GameController struct {
-	Store       *game.GameStore
-	PlayerStore *game.PlayerStore
+	Store        *game.GameStore
+	PlayerStore  *game.PlayerStore
+	HistoryStore *game.HistoryStore
+	Ledger       *game.Ledger
+	Bus          *game.Bus
+	Tables       *game.TableStore
+	Tournaments  *game.TournamentStore
+	Leaderboards *game.Leaderboards
+	Achievements *game.Achievements
+	Stats        *game.StatsStore
+	Decisions    *game.DecisionStore
+	Trainers     *game.TrainerStore
+	Promotions   *game.PromotionStore
+	Bankroll     *game.Bankroll
+	Safeguards   *game.SafeguardStore
+
+	players playerLocks // See lockPlayer
 }
 
 func NewGameController() *GameController {
-	return &GameController{
-		Store:       game.NewGameStore(),
-		PlayerStore: game.NewPlayerStore(),
+	playerStore := game.NewPlayerStore()
+	c := &GameController{
+		Store:        game.NewGameStore(),
+		PlayerStore:  playerStore,
+		HistoryStore: game.NewHistoryStore(),
+		Ledger:       game.NewLedger(playerStore),
+		Bus:          game.NewBus(),
+		Leaderboards: game.NewLeaderboards(),
+		Achievements: game.NewAchievements(playerStore, game.DefaultAchievements),
+		Stats:        game.NewStatsStore(),
+		Decisions:    game.NewDecisionStore(),
+		Trainers:     game.NewTrainerStore(),
+		Promotions:   game.NewPromotionStore(),
 	}
+	bankroll, _ := game.DefaultBankrollConfig(game.BankrollDaily)
+	c.Bankroll = game.NewBankroll(c.Ledger, bankroll)
+	c.Safeguards = game.NewSafeguardStore(c.Ledger)
+	c.Tables = game.NewTableStore(c.Ledger, c.Bus)
+	c.Tournaments = game.NewTournamentStore(c.Ledger)
+	c.Tables.OnSettle = c.onSettled
+
+	// Every balance change is published for the event streams
+	c.Ledger.Notify = func(tx game.Transaction) {
+		c.Bus.Publish(game.Notification{Type: game.NotifyBalance, GameID: tx.GameID, PlayerID: tx.PlayerID, Transaction: &tx})
+	}
+	return c
+}
+
+// Wallets resolves whether a game plays with the real balance or tournament chips
+func (c *GameController) Wallets() game.Wallets {
+	return game.Wallets{Ledger: c.Ledger, Tournaments: c.Tournaments}
 }
 
 // GameResponse DTO to hide internal details if needed (e.g., hidden dealer card)
 type GameResponse struct {
-	ID               string          `json:"id"`
-	PlayerHand       game.Hand       `json:"player_hand"`
-	SplitHand        *game.Hand      `json:"split_hand,omitempty"`
-	CurrentHandIndex int             `json:"current_hand_index"`
-	DealerHand       game.Hand       `json:"dealer_hand"` // We might need to mask this
-	Status           game.GameStatus `json:"status"`
-	PlayerBalance    int             `json:"player_balance"`
-	CurrentBet       int             `json:"current_bet"`
+	ID               string               `json:"id"`
+	PlayerHand       game.Hand            `json:"player_hand"`          // First hand
+	SplitHand        *game.Hand           `json:"split_hand,omitempty"` // Second hand, if any
+	Hands            []game.Hand          `json:"hands"`                // Every hand, in play order
+	CurrentHandIndex int                  `json:"current_hand_index"`
+	DealerHand       game.Hand            `json:"dealer_hand"` // We might need to mask this
+	Status           game.GameStatus      `json:"status"`
+	PlayerBalance    int                  `json:"player_balance"`
+	CurrentBet       int                  `json:"current_bet"`
+	SideBets         []game.SideBetResult `json:"side_bets,omitempty"` // Itemized, with payouts once settled
+	Rules            game.Rules           `json:"rules"`
+	Actions          []string             `json:"actions"` // Actions the rules allow, in the rule set's own words
 }
 
 type StartGameRequest struct {
-	BetAmount int `json:"bet_amount" binding:"required"`
+	BetAmount    int            `json:"bet_amount" binding:"required"`
+	TournamentID string         `json:"tournament_id"` // Play with tournament chips instead of the balance
+	SideBets     map[string]int `json:"side_bets"`     // Optional wagers by side bet name, see GET /api/side-bets
+	Variant      game.Variant   `json:"variant"`       // Rule set, "classic" (default) or "spanish21"
+	Charlie      int            `json:"charlie"`       // Optional: a hand of this many cards, 5 to 8, wins outright
+	Table        string         `json:"table"`         // Table profile with the betting limits, "standard" (default); see GET /api/table-profiles
 }
 
 // StartGame handles POST /api/games
@@ -50,389 +106,781 @@ func (c *GameController) StartGame(ctx *gin.Context) {
 		return
 	}
 
-	if req.BetAmount < 1 {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Bet amount must be at least 1"})
+	gameState, wallet, apiErr := c.startGame(playerID, req)
+	if apiErr != nil {
+		ctx.JSON(apiErr.Status, apiErr.body())
 		return
 	}
 
-	// Get or Create Player
-	player, exists := c.PlayerStore.Get(playerID)
-	if !exists {
-		player = &game.Player{ID: playerID, Balance: 100}
-		c.PlayerStore.Save(player)
-	}
+	// The game is in the store already, open to the janitor and the player's other connections
+	gameState.Lock()
+	defer gameState.Unlock()
+	ctx.JSON(http.StatusCreated, c.maskDealerHand(gameState, wallet.Balance()))
+}
 
-	// Auto-top-up if balance is 0 (Bankrupt)
-	if player.Balance <= 0 {
-		player.Balance = 100
-		c.PlayerStore.Save(player)
+// startGame places the bet and deals a new game; shared by the REST and WebSocket APIs
+func (c *GameController) startGame(playerID string, req StartGameRequest) (*game.GameState, game.Wallet, *apiError) {
+	defer c.lockPlayer(playerID)()
+	if req.BetAmount < 1 {
+		return nil, nil, &apiError{Status: http.StatusBadRequest, Message: "Bet amount must be at least 1"}
+	}
+	rules, ok := game.RulesFor(req.Variant)
+	if !ok {
+		return nil, nil, &apiError{Status: http.StatusBadRequest, Message: "Unknown variant"}
+	}
+	if req.Charlie != 0 {
+		if req.Charlie < 5 || req.Charlie > 8 {
+			return nil, nil, &apiError{Status: http.StatusBadRequest, Message: "Charlie must be 5 to 8 cards"}
+		}
+		rules.Charlie = req.Charlie
+	}
+	// The promotions running now apply for the whole game, even if they end before it does
+	for _, p := range c.Promotions.Active(time.Now(), rules.Variant) {
+		rules.Bonuses = append(rules.Bonuses, p.Rule)
+	}
+	sideBets, err := game.PlaceSideBets(req.SideBets)
+	if err != nil {
+		return nil, nil, &apiError{Status: http.StatusBadRequest, Message: "Invalid side bet: " + err.Error()}
+	}
+	if err := game.CheckSideBets(sideBets, rules, game.GameDecks); err != nil {
+		return nil, nil, &apiError{Status: http.StatusBadRequest, Message: "Invalid side bet: " + err.Error()}
+	}
+	sideStake := 0
+	for _, b := range sideBets {
+		sideStake += b.Amount
 	}
 
-	// Validate Balance
-	if player.Balance < req.BetAmount {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Insufficient funds"})
-		return
+	// Cash games are dealt under the limits of a table profile; tournament stacks bound themselves
+	var limits *game.TableProfile
+	if req.TournamentID == "" {
+		profile, ok := game.LookupTableProfile(req.Table)
+		if !ok {
+			return nil, nil, &apiError{Status: http.StatusBadRequest, Message: "Unknown table profile"}
+		}
+		stake := req.BetAmount * rules.StartingHands()
+		err := profile.CheckBet(req.BetAmount)
+		if err == nil {
+			err = profile.CheckGameExposure(stake)
+		}
+		if err == nil {
+			err = profile.CheckPlayerExposure(c.exposure(playerID, nil) + stake + sideStake)
+		}
+		if err != nil {
+			return nil, nil, limitError(http.StatusBadRequest, err)
+		}
+		limits = &profile
+
+		// The player's own limits, cool-off and self-exclusion
+		if err := c.Safeguards.CheckStart(playerID, stake+sideStake, time.Now()); err != nil {
+			return nil, nil, limitError(http.StatusForbidden, err)
+		}
+	} else if err := c.Safeguards.CheckPlay(playerID, time.Now()); err != nil {
+		// Tournament chips are not real money, but prizes are
+		return nil, nil, limitError(http.StatusForbidden, err)
 	}
 
-	// Deduct Bet
-	player.Balance -= req.BetAmount
-	c.PlayerStore.Save(player)
+	// Broke players get chips through the bankroll policy, see POST /api/players/me/bankroll/claim
+	c.getOrCreatePlayer(playerID)
 
-	// Initialize Deck
-	deck := game.NewDeck()
-	deck = game.Shuffle(deck)
+	if req.TournamentID != "" {
+		if _, exists := c.Tournaments.Get(req.TournamentID); !exists {
+			return nil, nil, &apiError{Status: http.StatusNotFound, Message: "Tournament not found"}
+		}
+	}
+	wallet := c.Wallets().For(playerID, req.TournamentID)
 
-	// Deal initial cards
-	playerHand := game.Hand{Cards: []game.Card{}}
-	dealerHand := game.Hand{Cards: []game.Card{}}
+	id := uuid.New().String()
 
-	// Player gets 2 cards
-	playerHand.Cards = append(playerHand.Cards, game.DealCard(&deck))
-	playerHand.Cards = append(playerHand.Cards, game.DealCard(&deck))
+	// Validate Balance and Deduct Bet, one per hand; side bets must be covered as well
+	hands := rules.StartingHands()
+	if wallet.Balance() < req.BetAmount*hands+sideStake {
+		return nil, nil, &apiError{Status: http.StatusBadRequest, Message: "Insufficient funds", Code: CodeInsufficientFunds}
+	}
+	// The bets are posted one by one; if one fails, those already posted are refunded
+	debited := 0
+	debit := func(amount int, reason string) *apiError {
+		err := wallet.Debit(game.TxBet, id, amount, reason)
+		if err == nil {
+			debited += amount
+			return nil
+		}
+		if debited > 0 {
+			credit(wallet, game.TxRefund, id, debited, "bets not placed: "+err.Error())
+			wallet.Cancelled(id)
+		}
+		if errors.Is(err, game.ErrInsufficientFunds) {
+			return &apiError{Status: http.StatusBadRequest, Message: "Insufficient funds", Code: CodeInsufficientFunds}
+		}
+		return &apiError{Status: http.StatusConflict, Message: err.Error()}
+	}
+	for i := 0; i < hands; i++ {
+		reason := "bet"
+		if hands > 1 {
+			reason = fmt.Sprintf("bet hand %d", i+1)
+		}
+		if apiErr := debit(req.BetAmount, reason); apiErr != nil {
+			return nil, nil, apiErr
+		}
+	}
+	for _, b := range sideBets {
+		if apiErr := debit(b.Amount, "side bet "+b.Name); apiErr != nil {
+			return nil, nil, apiErr
+		}
+	}
+	c.Safeguards.Played(playerID, time.Now())
 
-	// Dealer gets 2 cards
-	dealerHand.Cards = append(dealerHand.Cards, game.DealCard(&deck))
-	dealerHand.Cards = append(dealerHand.Cards, game.DealCard(&deck))
+	// Every change to the game goes through its event log
+	gameState := &game.GameState{}
+	gameState.Record(game.Event{Type: game.EventBetPlaced, GameID: id, PlayerID: playerID, Tournament: req.TournamentID, Amount: req.BetAmount, SideBets: sideBets, Rules: &rules, Limits: limits})
 
-	// Calculate initial scores
-	playerHand.Score = game.CalculateScore(playerHand.Cards)
-	dealerHand.Score = game.CalculateScore(dealerHand.Cards)
+	// Initialize Deck
+	gameState.Record(game.Event{Type: game.EventDeckShuffled, Deck: game.Shuffle(rules.Deck())})
 
-	id := uuid.New().String()
+	// Deal initial cards
+	// Player gets 2 cards per hand
+	for i := 0; i < hands; i++ {
+		gameState.Deal(game.SeatPlayer, i, true)
+		gameState.Deal(game.SeatPlayer, i, true)
+	}
 
-	gameState := &game.GameState{
-		ID:         id,
-		PlayerID:   playerID,
-		BetAmount:  req.BetAmount,
-		PlayerHand: playerHand,
-		DealerHand: dealerHand,
-		Deck:       deck,
-		Status:     game.StatusPlayerTurn,
+	// Dealer gets 2 cards, the second one face down.
+	// Double Exposure deals it face up; without a hole card it is drawn after the player acts.
+	// Pontoon deals both face down.
+	gameState.Deal(game.SeatDealer, 0, !rules.DealerCardsDown)
+	if !rules.NoHoleCard {
+		gameState.Deal(game.SeatDealer, 0, rules.HoleCardUp)
 	}
 
+	// Bets on the initial cards are paid right away
+	c.payoutSideBets(gameState, wallet, gameState.ResolveSideBets(false))
+
+	playerHand := gameState.Hands[0]
+	dealerHand := gameState.DealerHand
+
 	// Check for initial Blackjack
-	if playerHand.Score == 21 {
+	if rules.Switch {
+		// Switch hands are played first, naturals included; only a dealer blackjack ends the game now
 		if dealerHand.Score == 21 {
-			gameState.Status = game.StatusPush
+			c.finishPlayerTurn(gameState, wallet)
+		}
+	} else if playerHand.Score == 21 {
+		if rules.NoHoleCard {
+			// Nothing left for the player to do: the dealer's second card decides the natural
+			gameState.DealerDraw()
+			dealerHand = gameState.DealerHand
+		}
+		if dealerHand.Score == 21 && rules.DealerWinsTies {
+			c.settle(gameState, wallet, game.StatusDealerWon, 0)
+		} else if dealerHand.Score == 21 && !rules.Player21Wins {
 			// Refund Bet
-			player.Balance += req.BetAmount
+			credit(wallet, game.TxRefund, id, req.BetAmount, "push: both blackjack")
+			c.settle(gameState, wallet, game.StatusPush, req.BetAmount)
 		} else {
-			gameState.Status = game.StatusPlayerWon
 			// Blackjack Payout (3:2) -> Return Bet + 1.5 * Bet = 2.5 * Bet
 			// Since we already deducted the bet, we add 2.5 * Bet back.
 			// E.g. Bet 10. Balance -10. Win. Balance += 25. Net +15.
-			payout := int(float64(req.BetAmount) * 2.5)
-			player.Balance += payout
+			payout := rules.BlackjackPayout(req.BetAmount)
+			credit(wallet, game.TxPayout, id, payout, "blackjack")
+			c.settle(gameState, wallet, game.StatusPlayerWon, payout)
 		}
-		c.PlayerStore.Save(player)
 	} else if dealerHand.Score == 21 {
 		// Dealer blackjack, player loses (unless push handled above)
-		gameState.Status = game.StatusDealerWon
 		// No refund
+		c.settle(gameState, wallet, game.StatusDealerWon, 0)
 	}
 
 	c.Store.Save(gameState)
+	c.publishEvents(gameState, 0)
 
-	ctx.JSON(http.StatusCreated, c.maskDealerHand(gameState, player.Balance))
+	return gameState, wallet, nil
 }
 
 // ActionRequest DTO
 type ActionRequest struct {
-	Action string `json:"action" binding:"required"` // "hit" or "stand" or "split"
+	Action string `json:"action" binding:"required"` // "hit", "stand", "split", "double", "surrender", "rescue", "switch" or "buy", or a word of the rule set's terms
+	Amount int    `json:"amount"`                    // For "buy": stake added for the card, up to the original bet; defaults to the bet
 }
 
 // PerformAction handles POST /api/games/:id/action
+// Only the player who started the game, identified by X-Player-ID, may act on it.
 func (c *GameController) PerformAction(ctx *gin.Context) {
-	id := ctx.Param("id")
+	playerID, ok := requirePlayerID(ctx)
+	if !ok {
+		return
+	}
 	var req ActionRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
+	gameState, wallet, apiErr := c.performAction(playerID, ctx.Param("id"), req)
+	if apiErr != nil {
+		ctx.JSON(apiErr.Status, apiErr.body())
+		return
+	}
+
+	gameState.Lock()
+	defer gameState.Unlock()
+	ctx.JSON(http.StatusOK, c.maskDealerHand(gameState, wallet.Balance()))
+}
+
+// performAction applies an action of a player to their game; shared by the REST and WebSocket APIs
+func (c *GameController) performAction(playerID, id string, req ActionRequest) (*game.GameState, game.Wallet, *apiError) {
+	defer c.lockPlayer(playerID)()
+
 	gameState, exists := c.Store.Get(id)
 	if !exists {
-		ctx.JSON(http.StatusNotFound, gin.H{"error": "Game not found"})
-		return
+		// Finished games are moved to history by the janitor
+		if g, archived := c.HistoryStore.Get(id); archived && g.PlayerID == playerID {
+			return nil, nil, &apiError{Status: http.StatusBadRequest, Message: "Game is already over or not player's turn"}
+		}
+		return nil, nil, &apiError{Status: http.StatusNotFound, Message: "Game not found"}
 	}
+	if gameState.PlayerID != playerID {
+		return nil, nil, &apiError{Status: http.StatusForbidden, Message: "Not your game"}
+	}
+	// The janitor and snapshots may look at the game concurrently; the status is checked under the lock
+	gameState.Lock()
+	defer gameState.Unlock()
 
-	// Fetch Player for balance updates
-	player, pExists := c.PlayerStore.Get(gameState.PlayerID)
-	// If player is missing for some reason, re-create or error?
-	if !pExists {
-		player = &game.Player{ID: gameState.PlayerID, Balance: 0}
+	// If player is missing for some reason, re-create it so payouts can be posted
+	if _, pExists := c.PlayerStore.Get(gameState.PlayerID); !pExists {
+		c.PlayerStore.Save(&game.Player{ID: gameState.PlayerID, Balance: 0})
 	}
+	wallet := c.Wallets().Of(gameState)
 
 	if gameState.Status != game.StatusPlayerTurn {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Game is already over or not player's turn"})
-		return
+		return nil, nil, &apiError{Status: http.StatusBadRequest, Message: "Game is already over or not player's turn"}
+	}
+
+	// Publish whatever this action adds to the event log
+	from := len(gameState.Events)
+	defer c.publishEvents(gameState, from)
+
+	// Actions may be given in the rule set's own words, e.g. "twist" and "stick" in Pontoon
+	req.Action = gameState.Rules.Action(req.Action)
+
+	// Score the decision before the action changes the hand; it counts once the action went through.
+	// Rule sets basic strategy does not cover are counted as unscored instead.
+	decision, scored := game.ScoreDecision(gameState, req.Action, gameState.Rules)
+	if unscored := game.UnscoredReason(gameState.Rules); scored || unscored != "" {
+		defer func() {
+			switch {
+			case len(gameState.Events) == from:
+			case scored:
+				c.Decisions.Record(decision)
+			default:
+				c.Decisions.RecordUnscored(gameState.PlayerID, gameState.Rules.Variant, unscored)
+			}
+		}()
 	}
 
 	if req.Action == "split" {
+		activeHand := gameState.ActiveHand()
+		if activeHand == nil {
+			return nil, nil, &apiError{Status: http.StatusInternalServerError, Message: "Invalid hand state"}
+		}
 		// Validations
 		// 1. Can split only if not already split (simple version)
-		if gameState.SplitHand != nil {
-			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Cannot split again"})
-			return
+		if activeHand.Split {
+			return nil, nil, &apiError{Status: http.StatusBadRequest, Message: "Cannot split again"}
 		}
 		// 2. Can split only if 2 cards in hand
-		if len(gameState.PlayerHand.Cards) != 2 {
-			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Can only split with 2 cards"})
-			return
+		if len(activeHand.Cards) != 2 {
+			return nil, nil, &apiError{Status: http.StatusBadRequest, Message: "Can only split with 2 cards"}
 		}
 		// 3. Can split only if ranks match
-		if gameState.PlayerHand.Cards[0].Rank != gameState.PlayerHand.Cards[1].Rank {
-			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Can only split cards of same rank"})
-			return
+		if activeHand.Cards[0].Rank != activeHand.Cards[1].Rank {
+			return nil, nil, &apiError{Status: http.StatusBadRequest, Message: "Can only split cards of same rank"}
 		}
-		// 4. Check balance
-		if player.Balance < gameState.BetAmount {
-			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Insufficient funds to split"})
-			return
-		}
-
-		// Perform Split
-		player.Balance -= gameState.BetAmount
-		c.PlayerStore.Save(player)
-
-		card2 := gameState.PlayerHand.Cards[1]
-
-		// Setup First Hand
-		gameState.PlayerHand.Cards = []game.Card{gameState.PlayerHand.Cards[0]}
-		// Deal 2nd card to first hand
-		gameState.PlayerHand.Cards = append(gameState.PlayerHand.Cards, game.DealCard(&gameState.Deck))
-		gameState.PlayerHand.Score = game.CalculateScore(gameState.PlayerHand.Cards)
-
-		// Setup Split Hand
-		gameState.SplitHand = &game.Hand{
-			Cards: []game.Card{card2},
+		// 4. Check balance and Perform Split, unless the house funds it
+		free := gameState.Rules.FreeSplit(*activeHand)
+		if !free {
+			if apiErr := c.checkRaise(gameState, gameState.BetAmount); apiErr != nil {
+				return nil, nil, apiErr
+			}
+			if err := wallet.Debit(game.TxBet, gameState.ID, gameState.BetAmount, "split"); err != nil {
+				return nil, nil, &apiError{Status: http.StatusBadRequest, Message: "Insufficient funds to split", Code: CodeInsufficientFunds}
+			}
 		}
-		// Deal 2nd card to split hand
-		gameState.SplitHand.Cards = append(gameState.SplitHand.Cards, game.DealCard(&gameState.Deck))
-		gameState.SplitHand.Score = game.CalculateScore(gameState.SplitHand.Cards)
 
-		gameState.CurrentHandIndex = 0 // Start with first hand
+		// Moves the second card to a new hand right after this one, then deal each hand its second card
+		index := gameState.CurrentHandIndex
+		gameState.Record(game.Event{Type: game.EventActionTaken, Action: req.Action, HandIndex: index, Free: free})
+		gameState.Deal(game.SeatPlayer, index, true)
+		gameState.Deal(game.SeatPlayer, index+1, true)
 
 		// Important: In standard Blackjack, if you split Aces, you get 1 card each and stand automatically.
 		// Simplifying: Play normally for now unless specifically asked otherwise.
 
 		c.Store.Save(gameState)
-		ctx.JSON(http.StatusOK, c.maskDealerHand(gameState, player.Balance))
-		return
+		return gameState, wallet, nil
 	}
 
 	if req.Action == "hit" {
 		// Determine which hand to hit
-		var activeHand *game.Hand
-		if gameState.CurrentHandIndex == 0 {
-			activeHand = &gameState.PlayerHand
-		} else if gameState.CurrentHandIndex == 1 && gameState.SplitHand != nil {
-			activeHand = gameState.SplitHand
-		} else {
-			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid hand state"})
-			return
-		}
-
-		card := game.DealCard(&gameState.Deck)
-		activeHand.Cards = append(activeHand.Cards, card)
-		activeHand.Score = game.CalculateScore(activeHand.Cards)
-
-		if game.IsBust(activeHand.Score) {
-			// If split, move to next hand?
-			if gameState.SplitHand != nil && gameState.CurrentHandIndex == 0 {
-				gameState.CurrentHandIndex = 1
-			} else {
-				// Game Over (unless Dealer needs to play for other hand? No, if bust here, we settle this hand)
-				// Wait. Logic for Split:
-				// If Hand 1 busts, it loses immediately. Then play Hand 2.
-				// If Hand 2 busts, it loses immediately.
-				// Dealer plays if AT LEAST one hand did not bust.
-
-				if gameState.SplitHand != nil && gameState.CurrentHandIndex == 1 {
-					// Both hands played. Now Dealer?
-					// Only if one of them is valid (not bust).
-					// If Hand 1 Bust AND Hand 2 Bust -> Dealer doesn't play. Status DealerWon.
-
-					// Let's defer "Dealer Turn" logic to a common finish step.
-					// For now, if active hand busts, we treat it as "Stand" effectively but marked as bust.
-					// But we need to know it busted.
-					// Let's transition:
-					c.finishPlayerTurn(gameState)
-				} else if gameState.SplitHand == nil {
-					// Single hand bust -> Game Over
-					gameState.Status = game.StatusDealerWon
-				}
-			}
+		activeHand := gameState.ActiveHand()
+		if activeHand == nil {
+			return nil, nil, &apiError{Status: http.StatusInternalServerError, Message: "Invalid hand state"}
+		}
+		if activeHand.Doubled {
+			return nil, nil, &apiError{Status: http.StatusBadRequest, Message: "Hand is doubled, stand or rescue"}
+		}
+
+		gameState.Record(game.Event{Type: game.EventActionTaken, Action: req.Action, HandIndex: gameState.CurrentHandIndex})
+		gameState.Deal(game.SeatPlayer, gameState.CurrentHandIndex, true)
+
+		if game.IsBust(activeHand.Score) || gameState.Rules.HandComplete(*activeHand) {
+			// A busted hand loses and we move on to the next one.
+			// After the last hand the player turn is over; the dealer only plays if
+			// at least one hand did not bust (handled in finishPlayerTurn).
+			c.nextHand(gameState, wallet)
 		}
 
 		c.Store.Save(gameState)
-		ctx.JSON(http.StatusOK, c.maskDealerHand(gameState, player.Balance))
-		return
+		return gameState, wallet, nil
 
-	} else if req.Action == "stand" {
-		// If split and on first hand, move to second
-		if gameState.SplitHand != nil && gameState.CurrentHandIndex == 0 {
-			gameState.CurrentHandIndex = 1
-			c.Store.Save(gameState)
-			ctx.JSON(http.StatusOK, c.maskDealerHand(gameState, player.Balance))
-			return
+	} else if req.Action == "double" {
+		activeHand := gameState.ActiveHand()
+		if activeHand == nil {
+			return nil, nil, &apiError{Status: http.StatusInternalServerError, Message: "Invalid hand state"}
+		}
+		if refusal := gameState.Rules.DoubleRefusal(*activeHand); refusal != "" {
+			return nil, nil, &apiError{Status: http.StatusBadRequest, Message: refusal}
+		}
+		free := gameState.Rules.FreeDouble(*activeHand)
+		if !free {
+			if apiErr := c.checkRaise(gameState, gameState.BetAmount); apiErr != nil {
+				return nil, nil, apiErr
+			}
+			if err := wallet.Debit(game.TxBet, gameState.ID, gameState.BetAmount, "double"); err != nil {
+				return nil, nil, &apiError{Status: http.StatusBadRequest, Message: "Insufficient funds to double", Code: CodeInsufficientFunds}
+			}
 		}
 
-		// Otherwise finish player turn
-		c.finishPlayerTurn(gameState)
+		// Exactly one more card, then the hand stands.
+		// With double-down rescue the player still gets to stand or rescue a live hand.
+		gameState.Record(game.Event{Type: game.EventActionTaken, Action: req.Action, HandIndex: gameState.CurrentHandIndex, Free: free})
+		gameState.Deal(game.SeatPlayer, gameState.CurrentHandIndex, true)
 
-		// Update balance logic is complex with split.
-		// We need to calculate winnings for each hand against dealer.
-		// c.finishPlayerTurn handles dealer play. Now we calculate winnings.
+		if !gameState.Rules.DoubleRescue || activeHand.Score >= 21 {
+			c.nextHand(gameState, wallet)
+		}
+
+		c.Store.Save(gameState)
+		return gameState, wallet, nil
+
+	} else if req.Action == "buy" {
+		activeHand := gameState.ActiveHand()
+		if activeHand == nil {
+			return nil, nil, &apiError{Status: http.StatusInternalServerError, Message: "Invalid hand state"}
+		}
+		if !gameState.Rules.BuyCards {
+			return nil, nil, &apiError{Status: http.StatusBadRequest, Message: "Buy is not allowed under these rules"}
+		}
+		// Once a card was drawn for free, every further card is too
+		if gameState.HasTwisted(gameState.CurrentHandIndex) {
+			return nil, nil, &apiError{Status: http.StatusBadRequest, Message: "Cannot buy after twisting"}
+		}
+		amount := req.Amount
+		if amount == 0 {
+			amount = gameState.BetAmount
+		}
+		if amount < 1 || amount > gameState.BetAmount {
+			return nil, nil, &apiError{Status: http.StatusBadRequest, Message: "Can only buy for up to the original bet"}
+		}
+		if apiErr := c.checkRaise(gameState, amount); apiErr != nil {
+			return nil, nil, apiErr
+		}
+		if err := wallet.Debit(game.TxBet, gameState.ID, amount, "buy"); err != nil {
+			return nil, nil, &apiError{Status: http.StatusBadRequest, Message: "Insufficient funds to buy", Code: CodeInsufficientFunds}
+		}
 
-		totalWinnings := 0
+		gameState.Record(game.Event{Type: game.EventActionTaken, Action: req.Action, HandIndex: gameState.CurrentHandIndex, Amount: amount})
+		gameState.Deal(game.SeatPlayer, gameState.CurrentHandIndex, true)
 
-		// If dealer played (or we are resolving), compare hands.
-		// Note: finishPlayerTurn sets status.
+		if game.IsBust(activeHand.Score) || gameState.Rules.HandComplete(*activeHand) {
+			c.nextHand(gameState, wallet)
+		}
 
-		// Helper to compare one hand
-		resolveHand := func(hand game.Hand, bet int) int {
-			if game.IsBust(hand.Score) {
-				return 0 // Lost
+		c.Store.Save(gameState)
+		return gameState, wallet, nil
+
+	} else if req.Action == "surrender" || req.Action == "rescue" {
+		activeHand := gameState.ActiveHand()
+		if activeHand == nil {
+			return nil, nil, &apiError{Status: http.StatusInternalServerError, Message: "Invalid hand state"}
+		}
+		if req.Action == "surrender" {
+			// Late surrender: only as the first decision on an unsplit hand
+			if !gameState.Rules.Surrender {
+				return nil, nil, &apiError{Status: http.StatusBadRequest, Message: "Surrender is not allowed under these rules"}
 			}
-			if game.IsBust(gameState.DealerHand.Score) {
-				return bet * 2
+			if len(gameState.Hands) > 1 || len(activeHand.Cards) != 2 || activeHand.Doubled {
+				return nil, nil, &apiError{Status: http.StatusBadRequest, Message: "Can only surrender the first two cards"}
 			}
-			if hand.Score > gameState.DealerHand.Score {
-				return bet * 2
+		} else {
+			if !gameState.Rules.DoubleRescue {
+				return nil, nil, &apiError{Status: http.StatusBadRequest, Message: "Rescue is not allowed under these rules"}
 			}
-			if hand.Score == gameState.DealerHand.Score {
-				return bet // Push
+			if !activeHand.Doubled {
+				return nil, nil, &apiError{Status: http.StatusBadRequest, Message: "Can only rescue a doubled hand"}
 			}
-			return 0
 		}
 
-		// Calculate winnings
-		// Check Hand 1
-		totalWinnings += resolveHand(gameState.PlayerHand, gameState.BetAmount)
+		// Half of what is riding comes back when the hand is paid out
+		gameState.Record(game.Event{Type: game.EventActionTaken, Action: req.Action, HandIndex: gameState.CurrentHandIndex})
+		c.nextHand(gameState, wallet)
 
-		// Check Hand 2
-		if gameState.SplitHand != nil {
-			totalWinnings += resolveHand(*gameState.SplitHand, gameState.BetAmount)
+		c.Store.Save(gameState)
+		return gameState, wallet, nil
+
+	} else if req.Action == "switch" {
+		if !gameState.Rules.Switch {
+			return nil, nil, &apiError{Status: http.StatusBadRequest, Message: "Switch is not allowed under these rules"}
+		}
+		if gameState.HasActed() {
+			return nil, nil, &apiError{Status: http.StatusBadRequest, Message: "Can only switch before any other action"}
 		}
 
-		// Update Balance if any winnings
-		if totalWinnings > 0 {
-			player.Balance += totalWinnings
-			c.PlayerStore.Save(player)
+		// Swaps the second cards of the two hands; play then starts on the first hand
+		gameState.Record(game.Event{Type: game.EventActionTaken, Action: req.Action})
+
+		c.Store.Save(gameState)
+		return gameState, wallet, nil
+
+	} else if req.Action == "stand" {
+		if activeHand := gameState.ActiveHand(); activeHand != nil && activeHand.Score < gameState.Rules.MinStand {
+			return nil, nil, &apiError{Status: http.StatusBadRequest, Message: fmt.Sprintf("Cannot stand below %d", gameState.Rules.MinStand)}
 		}
+		gameState.Record(game.Event{Type: game.EventActionTaken, Action: req.Action, HandIndex: gameState.CurrentHandIndex})
 
-		// Set final Status for UI
-		// If split, status "PlayerWon" or "DealerWon" is ambiguous.
-		// Maybe just "GameOver"? Or keep it simple.
-		// If any hand won, maybe "PlayerWon"?
-		// Actually the UI usually shows result per hand or just balance update.
-		// Let's set StatusDealerTurn -> Status... something.
-		// Actually, finishPlayerTurn sets it to something?
-		// We need to decide what 'Status' means now.
-		// Let's say if all hands bust, DealerWon.
-		// If at least one hand wins, PlayerWon?
-		// Let's stick to: If game over, status is not PlayerTurn.
+		// Move to the split hand, or finish the player turn: dealer plays and every hand is paid out
+		c.nextHand(gameState, wallet)
 
 		c.Store.Save(gameState)
-		ctx.JSON(http.StatusOK, c.maskDealerHand(gameState, player.Balance))
-		return
+		return gameState, wallet, nil
 
 	} else {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid action"})
-		return
+		return nil, nil, &apiError{Status: http.StatusBadRequest, Message: "Invalid action"}
 	}
 }
 
-func (c *GameController) finishPlayerTurn(gameState *game.GameState) {
-	// Check if all player hands are busted.
-	allBusted := true
-	if !game.IsBust(gameState.PlayerHand.Score) {
-		allBusted = false
+// checkRaise checks a stake added to a game by a split, double or buy against its table limits
+// and, for cash games, the player's own wager and loss limits
+func (c *GameController) checkRaise(gameState *game.GameState, amount int) *apiError {
+	err := gameState.Limits.CheckGameExposure(gameState.Stake() + amount)
+	if err == nil {
+		err = gameState.Limits.CheckPlayerExposure(c.exposure(gameState.PlayerID, gameState) + amount)
 	}
-	if gameState.SplitHand != nil && !game.IsBust(gameState.SplitHand.Score) {
-		allBusted = false
+	if err != nil {
+		return limitError(http.StatusBadRequest, err)
 	}
+	if gameState.TournamentID == "" {
+		if err := c.Safeguards.CheckStake(gameState.PlayerID, amount, time.Now()); err != nil {
+			return limitError(http.StatusForbidden, err)
+		}
+	}
+	return nil
+}
+
+// exposure returns what a player has riding on unfinished cash games and unsettled table rounds.
+// held is the game the caller has locked, if any; see game.GameStore.Exposure.
+func (c *GameController) exposure(playerID string, held *game.GameState) int {
+	return c.Store.Exposure(playerID, held) + c.Tables.Exposure(playerID)
+}
+
+// lockPlayer serializes the requests that stake a player's money, so that limits checked
+// before a bet still hold when it is placed. It returns the unlock function.
+// Lock order: player, then game, then the stores.
+func (c *GameController) lockPlayer(playerID string) func() {
+	mu := c.players.get(playerID)
+	mu.Lock()
+	return mu.Unlock
+}
+
+// playerLocks holds one mutex per player
+type playerLocks struct {
+	mu    sync.Mutex
+	locks map[string]*sync.Mutex
+}
 
-	if allBusted {
-		gameState.Status = game.StatusDealerWon // Or simple "Game Over"
+func (l *playerLocks) get(playerID string) *sync.Mutex {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.locks == nil {
+		l.locks = make(map[string]*sync.Mutex)
+	}
+	mu, ok := l.locks[playerID]
+	if !ok {
+		mu = &sync.Mutex{}
+		l.locks[playerID] = mu
+	}
+	return mu
+}
+
+// nextHand moves on to the split hand, or finishes the player turn after the last hand
+func (c *GameController) nextHand(gameState *game.GameState, wallet game.Wallet) {
+	if !gameState.LastHand() {
+		gameState.Record(game.Event{Type: game.EventHandAdvanced})
 		return
 	}
+	c.finishPlayerTurn(gameState, wallet)
+}
+
+// finishPlayerTurn plays the dealer hand, pays out every player hand and settles the game
+func (c *GameController) finishPlayerTurn(gameState *game.GameState, wallet game.Wallet) {
+	rules := gameState.Rules
+	hands := gameState.Hands
+
+	// The dealer only plays if some hand still depends on the dealer's total.
+	// Busted and surrendered hands are lost, and a player 21 may win regardless.
+	dealerPlays := false
+	for _, hand := range hands {
+		if !game.IsBust(hand.Score) && !hand.Surrendered && !(rules.Player21Wins && hand.Score == 21) {
+			dealerPlays = true
+		}
+	}
+	if dealerPlays {
+		// Turning over the hole card starts the dealer turn
+		gameState.RevealHole()
+		for game.ShouldDealerHit(gameState.DealerHand) {
+			gameState.DealerDraw()
+		}
+	}
 
-	gameState.Status = game.StatusDealerTurn
+	// Without a hole card a dealer blackjack only shows now: every hand loses,
+	// and under OBO the doubles and splits are returned
+	if rules.NoHoleCard && game.IsBlackjack(gameState.DealerHand) {
+		refund := 0
+		if rules.OriginalBetOnly {
+			refund = gameState.Stake() - gameState.BetAmount
+		}
+		if refund > 0 {
+			credit(wallet, game.TxRefund, gameState.ID, refund, "dealer blackjack: original bet only")
+		}
+		c.settle(gameState, wallet, game.StatusDealerWon, refund)
+		return
+	}
 
-	// Dealer plays
-	for game.ShouldDealerHit(gameState.DealerHand) {
-		card := game.DealCard(&gameState.Deck)
-		gameState.DealerHand.Cards = append(gameState.DealerHand.Cards, card)
-		gameState.DealerHand.Score = game.CalculateScore(gameState.DealerHand.Cards)
+	// Calculate winnings and pay each hand separately so the ledger shows them apart
+	totalWinnings := 0
+	for i, hand := range hands {
+		bet, _ := gameState.HandStakes(hand)
+		winnings := gameState.HandWinnings(hand)
+		totalWinnings += winnings
+		switch {
+		case winnings == 0:
+			// Lost, or a free bet that pushed
+		case hand.Surrendered:
+			credit(wallet, game.TxRefund, gameState.ID, winnings, fmt.Sprintf("hand %d surrender", i+1))
+		case winnings == bet:
+			credit(wallet, game.TxRefund, gameState.ID, winnings, fmt.Sprintf("hand %d push", i+1))
+		case winnings > 0:
+			reason := fmt.Sprintf("hand %d win", i+1)
+			if bonus, ok := rules.BonusFor(hand); ok {
+				reason += ", bonus " + bonus.Rule
+			}
+			credit(wallet, game.TxPayout, gameState.ID, winnings, reason)
+		}
 	}
 
 	// Determine generic status (mostly for UI color)
-	if game.IsBust(gameState.DealerHand.Score) {
-		gameState.Status = game.StatusPlayerWon
-	} else {
-		// Complex result. Let's just say "finished" implicitly by not being PlayerTurn.
+	status := game.StatusPush
+	if totalWinnings == 0 {
+		status = game.StatusDealerWon
+	} else if len(hands) == 1 {
 		// We can reuse PlayerWon/DealerWon/Push if single hand.
-		if gameState.SplitHand == nil {
-			if gameState.DealerHand.Score > gameState.PlayerHand.Score {
-				gameState.Status = game.StatusDealerWon
-			} else if gameState.DealerHand.Score < gameState.PlayerHand.Score {
-				gameState.Status = game.StatusPlayerWon
-			} else {
-				gameState.Status = game.StatusPush
-			}
-		} else {
-			// Split results are mixed. Just mark as "DealerWon" (Game Over)
-			// and let UI show scores? Or add a Status "GameOver".
-			// Since we use strings, let's add "GameOver" or just leave as DealerTurn?
-			// No, DealerTurn implies waiting.
-			// Let's use "PlayerWon" if net profit > 0 ?
-			// Let's use "Push" as generic "Game Over" for split?
-			// User didn't specify. I'll stick to a generic status or just pick one.
-			// Let's leave it as "DealerWon" (meaning round finished)
-			// but relies on resolve logic to pay.
-			// Actually, "DealerWon" shows Red. "PlayerWon" shows Green.
-			// Maybe check net result?
-			// For now, let's use "PlayerWon" if at least one hand won?
-			gameState.Status = game.StatusPush // Neutral color?
+		if totalWinnings > gameState.Stake() {
+			status = game.StatusPlayerWon
+		} else if totalWinnings < gameState.Stake() {
+			status = game.StatusDealerWon
 		}
+	} else if game.IsBust(gameState.DealerHand.Score) && !(rules.Dealer22Push && gameState.DealerHand.Score == 22) {
+		status = game.StatusPlayerWon
 	}
+	// Results over several hands are mixed, so they are reported as "Push" (neutral color)
+	// and the balance shows the actual outcome.
+
+	c.settle(gameState, wallet, status, totalWinnings)
 }
 
-// maskDealerHand hides the dealer's second card if the game is still in progress
-func (c *GameController) maskDealerHand(g *game.GameState, balance int) GameResponse {
-	// If game is over, show everything
-	if g.Status != game.StatusPlayerTurn && g.Status != game.StatusDealerTurn {
-		return GameResponse{
-			ID:               g.ID,
-			PlayerHand:       g.PlayerHand,
-			SplitHand:        g.SplitHand,
-			CurrentHandIndex: g.CurrentHandIndex,
-			DealerHand:       g.DealerHand,
-			Status:           g.Status,
-			PlayerBalance:    balance,
-			CurrentBet:       g.BetAmount,
-		}
-	}
-
-	// Create a copy or a DTO
-	maskedHand := g.DealerHand
-	if len(maskedHand.Cards) > 1 {
-		// Keep only the first card visible
-		maskedHand.Cards = []game.Card{maskedHand.Cards[0], {Rank: "", Suit: ""}} // Mask second card
-		// Don't show score or show partial? Usually hide score too.
-		maskedHand.Score = 0 // Or just calculate score of first card
+// settle records the final result of a game and closes it in its wallet
+func (c *GameController) settle(gameState *game.GameState, wallet game.Wallet, status game.GameStatus, payout int) {
+	// The dealer plays out the hand for side bets on it, even if the main hand is already decided
+	if gameState.SideBetsNeedDealer() {
+		gameState.RevealHole()
+		for game.ShouldDealerHit(gameState.DealerHand) {
+			gameState.DealerDraw()
+		}
 	}
+	c.payoutSideBets(gameState, wallet, gameState.ResolveSideBets(true))
+
+	gameState.Settle(status, payout)
+	wallet.Settled(gameState.ID)
+	c.recordSettlement(gameState, wallet)
+}
+
+// OnExpired publishes a game the janitor expired and feeds it to the player aggregates
+// like any other finished game. The janitor holds the game's lock, see Janitor.OnExpire.
+func (c *GameController) OnExpired(gameState *game.GameState, from int) {
+	c.publishEvents(gameState, from)
+	c.recordSettlement(gameState, c.Wallets().Of(gameState))
+}
 
+// recordSettlement feeds a settled game to the player aggregates
+func (c *GameController) recordSettlement(gameState *game.GameState, wallet game.Wallet) {
+	// Tournament chips don't count towards the player aggregates
+	if gameState.TournamentID == "" {
+		settlement := game.GameSettlement(gameState)
+		settlement.Balance = wallet.Balance()
+		c.onSettled(settlement)
+	}
+}
+
+// payoutSideBets credits the winning side bets among those just settled
+func (c *GameController) payoutSideBets(gameState *game.GameState, wallet game.Wallet, settled []game.SideBetResult) {
+	for _, b := range settled {
+		if b.Payout > 0 {
+			credit(wallet, game.TxPayout, gameState.ID, b.Payout, fmt.Sprintf("side bet %s: %s", b.Name, b.Outcome))
+		}
+	}
+}
+
+// credit pays a player. A failed credit is logged: the game still shows the payout, but the balance does not.
+func credit(wallet game.Wallet, kind game.TxKind, gameID string, amount int, reason string) {
+	if err := wallet.Credit(kind, gameID, amount, reason); err != nil {
+		log.Printf("game %s: %s of %d (%s) failed: %v", gameID, kind, amount, reason, err)
+	}
+}
+
+// onSettled feeds a finished game or table seat to the player aggregates
+func (c *GameController) onSettled(s game.Settlement) {
+	c.Leaderboards.Record(s)
+	c.Achievements.Evaluate(s)
+	c.Stats.Record(s)
+}
+
+// maskDealerHand hides the dealer's second card until it is revealed
+func (c *GameController) maskDealerHand(g *game.GameState, balance int) GameResponse {
+	playerHand, splitHand := legacyHands(g.Hands)
 	return GameResponse{
 		ID:               g.ID,
-		PlayerHand:       g.PlayerHand,
-		SplitHand:        g.SplitHand,
+		PlayerHand:       playerHand,
+		SplitHand:        splitHand,
+		Hands:            g.Hands,
 		CurrentHandIndex: g.CurrentHandIndex,
-		DealerHand:       maskedHand,
+		DealerHand:       visibleDealerHand(g),
 		Status:           g.Status,
 		PlayerBalance:    balance,
 		CurrentBet:       g.BetAmount,
+		SideBets:         g.SideBets,
+		Rules:            g.Rules,
+		Actions:          g.Rules.Actions(),
+	}
+}
+
+// legacyHands returns the first two player hands for the player_hand and split_hand fields
+func legacyHands(hands []game.Hand) (game.Hand, *game.Hand) {
+	var first game.Hand
+	if len(hands) > 0 {
+		first = hands[0]
+	}
+	if len(hands) < 2 {
+		return first, nil
+	}
+	second := hands[1]
+	return first, &second
+}
+
+// visibleDealerHand returns the dealer hand as the player may see it
+func visibleDealerHand(g *game.GameState) game.Hand {
+	dealerHand := g.DealerHand
+	if !g.IsFinished() && g.HoleHidden() {
+		// Mask every card dealt face down; Pontoon has two
+		dealerHand.Cards = nil
+		for _, e := range g.Events {
+			if e.Type == game.EventCardDealt && e.Seat == game.SeatDealer {
+				dealerHand.Cards = append(dealerHand.Cards, *maskEvent(e, false).Card)
+			}
+		}
+		// Don't show score or show partial? Usually hide score too.
+		dealerHand.Score = 0
+	}
+	return dealerHand
+}
+
+// getOrCreatePlayer returns the player, creating it with the welcome bonus on first sight
+func (c *GameController) getOrCreatePlayer(playerID string) *game.Player {
+	player, exists := c.PlayerStore.Get(playerID)
+	if !exists {
+		player = &game.Player{ID: playerID}
+		c.PlayerStore.Save(player)
+		c.Ledger.Credit(playerID, game.TxBonus, "", 100, "welcome bonus")
+	}
+	return player
+}
+
+// publishEvents puts the game events from index from on the bus, masked as the player sees them
+func (c *GameController) publishEvents(g *game.GameState, from int) {
+	for _, e := range visibleEvents(g.Events, from) {
+		e := e
+		c.Bus.Publish(game.Notification{Type: game.NotifyGameEvent, Time: e.Time, GameID: g.ID, PlayerID: g.PlayerID, Event: &e})
+	}
+}
+
+// findGame looks up a game in the active store, then in history.
+// archived reports whether it was found in history.
+func (c *GameController) findGame(id string) (g *game.GameState, archived bool, ok bool) {
+	if g, ok := c.Store.Get(id); ok {
+		return g, false, true
+	}
+	if g, ok := c.HistoryStore.Get(id); ok {
+		return g, true, true
+	}
+	return nil, false, false
+}
+
+// CodeInsufficientFunds is the error code of a bet the balance cannot cover; clients may offer
+// the bankroll grant, see POST /api/players/me/bankroll/claim
+const CodeInsufficientFunds = "insufficient_funds"
+
+// apiError is a failed request as returned by the shared controller logic
+type apiError struct {
+	Status  int
+	Message string
+	Code    string // Set for violated limits (see game.LimitError) and CodeInsufficientFunds
+	Limit   int
+	Until   time.Time
+}
+
+// limitError turns a violated table or responsible gaming limit into a structured error
+func limitError(status int, err error) *apiError {
+	var limit *game.LimitError
+	if !errors.As(err, &limit) {
+		return &apiError{Status: status, Message: err.Error()}
+	}
+	return &apiError{Status: status, Message: limit.Message, Code: limit.Code, Limit: limit.Limit, Until: limit.Until}
+}
+
+// body returns the JSON response for the error; violated limits carry their code, the limit and when play resumes
+func (e *apiError) body() gin.H {
+	body := gin.H{"error": e.Message}
+	if e.Code != "" {
+		body["code"] = e.Code
+		body["limit"] = e.Limit
+	}
+	if !e.Until.IsZero() {
+		body["until"] = e.Until
+	}
+	return body
+}
+
+// until returns when play may resume, nil if not set
+func (e *apiError) until() *time.Time {
+	if e.Until.IsZero() {
+		return nil
 	}
+	return &e.Until
 }
@@ -4,9 +4,13 @@ import (
 	"blackjack-api/game"
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -118,3 +122,607 @@ func TestInsufficientFunds(t *testing.T) {
 		t.Errorf("Expected BadRequest for insufficient funds, got %v", w.Code)
 	}
 }
+
+func TestSideBets(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	controller := NewGameController()
+	router := gin.Default()
+	router.POST("/api/games", controller.StartGame)
+
+	start := func(req StartGameRequest) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(req)
+		r, _ := http.NewRequest("POST", "/api/games", bytes.NewBuffer(body))
+		r.Header.Set("Content-Type", "application/json")
+		r.Header.Set("X-Player-ID", "side-better")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		return w
+	}
+
+	if w := start(StartGameRequest{BetAmount: 10, SideBets: map[string]int{"insurance": 5}}); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected BadRequest for unknown side bet, got %v", w.Code)
+	}
+	if w := start(StartGameRequest{BetAmount: 75, Table: game.HighRollerProfile, SideBets: map[string]int{"bust_it": 50}}); w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), `"code":"`+CodeInsufficientFunds+`"`) {
+		t.Errorf("Expected BadRequest when side bets exceed the balance, got %v", w.Code)
+	}
+	// Games are dealt from one deck, which cannot produce suited trips; the main game keeps its deck
+	if w := start(StartGameRequest{BetAmount: 10, SideBets: map[string]int{"21+3": 5}}); w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "not offered") {
+		t.Errorf("Expected BadRequest for 21+3 on a single deck, got %v: %s", w.Code, w.Body.String())
+	}
+	// Nor can bets on the dealer upcard be placed when there is none
+	if w := start(StartGameRequest{BetAmount: 10, Variant: game.VariantPontoon, SideBets: map[string]int{"bust_it": 5}}); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected BadRequest for bust_it without an upcard, got %v", w.Code)
+	}
+
+	w := start(StartGameRequest{BetAmount: 10, SideBets: map[string]int{"bust_it": 5, "push_22": 5}})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected StatusCreated, got %v: %s", w.Code, w.Body.String())
+	}
+	var resp GameResponse
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	if len(resp.SideBets) != 2 || resp.SideBets[0].Name != "bust_it" {
+		t.Fatalf("Expected both side bets, got %+v", resp.SideBets)
+	}
+	if g, _, _ := controller.findGame(resp.ID); len(g.Events[1].Deck) != 52 {
+		t.Errorf("Expected the game dealt from one deck, got %d cards", len(g.Events[1].Deck))
+	}
+
+	// Bets on the dealer's hand wait for it; everything paid so far shows up in the balance
+	expected := 100 - 10 - 10
+	for _, b := range resp.SideBets {
+		expected += b.Payout
+	}
+	if resp.Status == game.StatusPlayerTurn {
+		if resp.SideBets[0].Settled || resp.SideBets[1].Settled {
+			t.Errorf("Expected the side bets to wait for the dealer, got %+v", resp.SideBets)
+		}
+		if resp.PlayerBalance != expected {
+			t.Errorf("Expected balance %d, got %d", expected, resp.PlayerBalance)
+		}
+	} else if !resp.SideBets[0].Settled || !resp.SideBets[1].Settled {
+		t.Errorf("Expected every side bet settled with the game, got %+v", resp.SideBets)
+	}
+}
+
+func TestSpanish21Actions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	controller := NewGameController()
+	router := gin.Default()
+	router.POST("/api/games/:id/action", controller.PerformAction)
+
+	// newGame deals a Spanish 21 game from a fixed deck: two player cards, then the dealer's
+	newGame := func(id string, ranks ...game.Rank) {
+		rules := game.Spanish21Rules
+		var deck []game.Card
+		for _, r := range ranks {
+			deck = append(deck, game.Card{Suit: game.Clubs, Rank: r})
+		}
+		controller.Ledger.Debit("spanish", game.TxBet, id, 10, "bet")
+		g := &game.GameState{}
+		g.Record(game.Event{Type: game.EventBetPlaced, GameID: id, PlayerID: "spanish", Amount: 10, Rules: &rules})
+		g.Record(game.Event{Type: game.EventDeckShuffled, Deck: deck})
+		g.Deal(game.SeatPlayer, 0, true)
+		g.Deal(game.SeatPlayer, 0, true)
+		g.Deal(game.SeatDealer, 0, true)
+		g.Deal(game.SeatDealer, 0, false)
+		controller.Store.Save(g)
+	}
+	act := func(id, action string) GameResponse {
+		body, _ := json.Marshal(ActionRequest{Action: action})
+		req, _ := http.NewRequest("POST", "/api/games/"+id+"/action", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Player-ID", "spanish")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected StatusOK on %s, got %v: %s", action, w.Code, w.Body.String())
+		}
+		var resp GameResponse
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		return resp
+	}
+	controller.getOrCreatePlayer("spanish")
+
+	// Double on three cards to 21: the player 21 wins without the dealer playing
+	newGame("double", game.Seven, game.Seven, game.King, game.Nine, game.Two, game.Five)
+	act("double", "hit")
+	resp := act("double", "double")
+	if resp.Status != game.StatusPlayerWon || resp.PlayerBalance != 100-20+40 {
+		t.Errorf("Expected doubled 21 to win 40, got %s with balance %d", resp.Status, resp.PlayerBalance)
+	}
+
+	// Rescue a doubled 14: the doubling comes back, the original bet is lost
+	newGame("rescue", game.Six, game.Five, game.King, game.Nine, game.Three)
+	balance := resp.PlayerBalance - 10
+	if resp = act("rescue", "double"); resp.Status != game.StatusPlayerTurn {
+		t.Fatalf("Expected the doubled hand to wait for stand or rescue, got %s", resp.Status)
+	}
+	resp = act("rescue", "rescue")
+	if resp.Status != game.StatusDealerWon || resp.PlayerBalance != balance-10+10 {
+		t.Errorf("Expected rescue to return 10, got %s with balance %d", resp.Status, resp.PlayerBalance)
+	}
+
+	// Late surrender returns half the bet
+	newGame("surrender", game.King, game.Six, game.King, game.Nine)
+	balance = resp.PlayerBalance - 10
+	resp = act("surrender", "surrender")
+	if resp.Status != game.StatusDealerWon || resp.PlayerBalance != balance+5 || !resp.PlayerHand.Surrendered {
+		t.Errorf("Expected surrender to return 5, got %s with balance %d", resp.Status, resp.PlayerBalance)
+	}
+}
+
+func TestBlackjackSwitch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	controller := NewGameController()
+	router := gin.Default()
+	router.POST("/api/games", controller.StartGame)
+	router.POST("/api/games/:id/action", controller.PerformAction)
+
+	act := func(id, action string) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(ActionRequest{Action: action})
+		req, _ := http.NewRequest("POST", "/api/games/"+id+"/action", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Player-ID", "switcher")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// A started Switch game takes two bets and deals two hands
+	body, _ := json.Marshal(StartGameRequest{BetAmount: 10, Variant: game.VariantSwitch})
+	req, _ := http.NewRequest("POST", "/api/games", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Player-ID", "switcher")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	var started GameResponse
+	json.Unmarshal(w.Body.Bytes(), &started)
+	if w.Code != http.StatusCreated || len(started.Hands) != 2 || started.SplitHand == nil {
+		t.Fatalf("Expected a Switch game with two hands, got %v: %s", w.Code, w.Body.String())
+	}
+	if started.Status == game.StatusPlayerTurn && started.PlayerBalance != 80 {
+		t.Errorf("Expected both bets taken, got balance %d", started.PlayerBalance)
+	}
+
+	if w := act(started.ID, "switch"); started.Status == game.StatusPlayerTurn && w.Code != http.StatusOK {
+		t.Errorf("Expected switch before any action to succeed, got %v", w.Code)
+	}
+
+	// Switching is rejected once the hands are being played, and in classic games
+	rules := game.SwitchRules
+	deck := []game.Card{
+		{Suit: game.Hearts, Rank: game.King}, {Suit: game.Hearts, Rank: game.Five},
+		{Suit: game.Clubs, Rank: game.Six}, {Suit: game.Clubs, Rank: game.King},
+		{Suit: game.Spades, Rank: game.King}, {Suit: game.Spades, Rank: game.Six}, {Suit: game.Diamonds, Rank: game.Six},
+	}
+	controller.Ledger.Debit("switcher", game.TxBet, "fixed", 20, "bet")
+	g := &game.GameState{}
+	g.Record(game.Event{Type: game.EventBetPlaced, GameID: "fixed", PlayerID: "switcher", Amount: 10, Rules: &rules})
+	g.Record(game.Event{Type: game.EventDeckShuffled, Deck: deck})
+	for _, i := range []int{0, 0, 1, 1} {
+		g.Deal(game.SeatPlayer, i, true)
+	}
+	g.Deal(game.SeatDealer, 0, true)
+	g.Deal(game.SeatDealer, 0, false)
+	controller.Store.Save(g)
+	balance := controller.Ledger.Balance(game.PlayerAccount("switcher"))
+
+	if w := act("fixed", "switch"); w.Code != http.StatusOK {
+		t.Fatalf("Expected switch to succeed, got %v", w.Code)
+	}
+	act("fixed", "stand")
+	if w := act("fixed", "switch"); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected switch after an action to be rejected, got %v", w.Code)
+	}
+
+	// 20 and 11 stand against a dealer 22: both hands push
+	w = act("fixed", "stand")
+	var resp GameResponse
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	if resp.Hands[0].Score != 20 || resp.Hands[1].Score != 11 {
+		t.Fatalf("Expected switched hands of 20 and 11, got %+v", resp.Hands)
+	}
+	if resp.DealerHand.Score != 22 || resp.Status != game.StatusPush || resp.PlayerBalance != balance+20 {
+		t.Errorf("Expected both hands to push on dealer 22, got %s with balance %d", resp.Status, resp.PlayerBalance)
+	}
+}
+
+func TestFreeBet(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	controller := NewGameController()
+	router := gin.Default()
+	router.POST("/api/games/:id/action", controller.PerformAction)
+	act := func(id, action string) GameResponse {
+		body, _ := json.Marshal(ActionRequest{Action: action})
+		req, _ := http.NewRequest("POST", "/api/games/"+id+"/action", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Player-ID", "free")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected StatusOK on %s, got %v: %s", action, w.Code, w.Body.String())
+		}
+		var resp GameResponse
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		return resp
+	}
+
+	// Free split of 8s, free double of the first hand's 11 to 20, stand on 18
+	newGame := func(id string, dealerDraw game.Rank) {
+		rules := game.FreeBetRules
+		controller.getOrCreatePlayer("free")
+		controller.Ledger.Debit("free", game.TxBet, id, 10, "bet")
+		g := &game.GameState{}
+		g.Record(game.Event{Type: game.EventBetPlaced, GameID: id, PlayerID: "free", Amount: 10, Rules: &rules})
+		g.Record(game.Event{Type: game.EventDeckShuffled, Deck: []game.Card{
+			{Suit: game.Hearts, Rank: game.Eight}, {Suit: game.Clubs, Rank: game.Eight},
+			{Suit: game.Spades, Rank: game.King}, {Suit: game.Spades, Rank: game.Six},
+			{Suit: game.Hearts, Rank: game.Three}, {Suit: game.Hearts, Rank: game.King},
+			{Suit: game.Clubs, Rank: game.Nine}, {Suit: game.Diamonds, Rank: dealerDraw},
+		}})
+		g.Deal(game.SeatPlayer, 0, true)
+		g.Deal(game.SeatPlayer, 0, true)
+		g.Deal(game.SeatDealer, 0, true)
+		g.Deal(game.SeatDealer, 0, false)
+		controller.Store.Save(g)
+	}
+	play := func(id string) GameResponse {
+		balance := controller.Ledger.Balance(game.PlayerAccount("free"))
+		resp := act(id, "split")
+		resp = act(id, "double")
+		if resp.PlayerBalance != balance || !resp.Hands[1].FreeStake || !resp.Hands[0].FreeDouble {
+			t.Fatalf("Expected free split and double to leave the balance at %d, got %d: %+v", balance, resp.PlayerBalance, resp.Hands)
+		}
+		return act(id, "stand")
+	}
+
+	// Dealer 22 pushes: only the real bet comes back
+	newGame("push", game.Six)
+	before := controller.Ledger.Balance(game.PlayerAccount("free"))
+	if resp := play("push"); resp.DealerHand.Score != 22 || resp.PlayerBalance != before+10 {
+		t.Errorf("Expected only the real 10 back on a dealer 22, got balance %d (dealer %d)", resp.PlayerBalance, resp.DealerHand.Score)
+	}
+
+	// Dealer busts: the real bet pays 20, each free bet pays its 10 of winnings
+	newGame("bust", game.Seven)
+	before = controller.Ledger.Balance(game.PlayerAccount("free"))
+	if resp := play("bust"); resp.PlayerBalance != before+40 {
+		t.Errorf("Expected 40 back on a dealer bust, got balance %d", resp.PlayerBalance)
+	}
+}
+
+func TestDealerExposureVariants(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	controller := NewGameController()
+	router := gin.Default()
+	router.POST("/api/games/:id/action", controller.PerformAction)
+	act := func(id, action string) GameResponse {
+		body, _ := json.Marshal(ActionRequest{Action: action})
+		req, _ := http.NewRequest("POST", "/api/games/"+id+"/action", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Player-ID", "exposed")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected StatusOK on %s, got %v: %s", action, w.Code, w.Body.String())
+		}
+		var resp GameResponse
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		return resp
+	}
+	// newGame deals like startGame does for the rules: two player cards, then the dealer's
+	newGame := func(id string, rules game.Rules, ranks ...game.Rank) GameResponse {
+		controller.getOrCreatePlayer("exposed")
+		controller.Ledger.Debit("exposed", game.TxBet, id, 10, "bet")
+		var deck []game.Card
+		for _, r := range ranks {
+			deck = append(deck, game.Card{Suit: game.Spades, Rank: r})
+		}
+		g := &game.GameState{}
+		g.Record(game.Event{Type: game.EventBetPlaced, GameID: id, PlayerID: "exposed", Amount: 10, Rules: &rules})
+		g.Record(game.Event{Type: game.EventDeckShuffled, Deck: deck})
+		g.Deal(game.SeatPlayer, 0, true)
+		g.Deal(game.SeatPlayer, 0, true)
+		g.Deal(game.SeatDealer, 0, true)
+		if !rules.NoHoleCard {
+			g.Deal(game.SeatDealer, 0, rules.HoleCardUp)
+		}
+		controller.Store.Save(g)
+		return controller.maskDealerHand(g, 0)
+	}
+	balance := func() int { return controller.Ledger.Balance(game.PlayerAccount("exposed")) }
+
+	// Double Exposure: both dealer cards show, and a tie goes to the dealer
+	resp := newGame("exposure", game.DoubleExposureRules, game.King, game.Queen, game.King, game.Jack)
+	if resp.DealerHand.Cards[1].Rank != game.Jack || resp.DealerHand.Score != 20 {
+		t.Fatalf("Expected both dealer cards visible, got %+v", resp.DealerHand)
+	}
+	before := balance()
+	if resp = act("exposure", "stand"); resp.Status != game.StatusDealerWon || resp.PlayerBalance != before {
+		t.Errorf("Expected the dealer to win the tie, got %s with balance %d", resp.Status, resp.PlayerBalance)
+	}
+
+	// ENHC: the dealer draws the second card after a double; a blackjack takes the double too
+	resp = newGame("enhc", game.ENHCRules, game.Six, game.Five, game.Ace, game.Nine, game.King)
+	if len(resp.DealerHand.Cards) != 1 {
+		t.Fatalf("Expected a single dealer card before the player acts, got %+v", resp.DealerHand)
+	}
+	before = balance()
+	if resp = act("enhc", "double"); resp.Status != game.StatusDealerWon || resp.PlayerBalance != before-10 {
+		t.Errorf("Expected a dealer blackjack to take the double, got %s with balance %d", resp.Status, resp.PlayerBalance)
+	}
+
+	// OBO: only the original bet is lost to the dealer blackjack
+	newGame("obo", game.ENHCOBORules, game.Six, game.Five, game.Ace, game.Nine, game.King)
+	before = balance()
+	if resp = act("obo", "double"); resp.Status != game.StatusDealerWon || resp.PlayerBalance != before {
+		t.Errorf("Expected the double to be returned, got %s with balance %d", resp.Status, resp.PlayerBalance)
+	}
+}
+
+func TestPontoon(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	controller := NewGameController()
+	router := gin.Default()
+	router.POST("/api/games/:id/action", controller.PerformAction)
+	act := func(action string, expectedCode int) GameResponse {
+		body, _ := json.Marshal(ActionRequest{Action: action})
+		req, _ := http.NewRequest("POST", "/api/games/pontoon/action", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Player-ID", "punter")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != expectedCode {
+			t.Fatalf("Expected %d on %s, got %v: %s", expectedCode, action, w.Code, w.Body.String())
+		}
+		var resp GameResponse
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		return resp
+	}
+
+	rules := game.PontoonRules
+	controller.getOrCreatePlayer("punter")
+	controller.Ledger.Debit("punter", game.TxBet, "pontoon", 10, "bet")
+	before := controller.Ledger.Balance(game.PlayerAccount("punter"))
+	var deck []game.Card
+	for _, r := range []game.Rank{game.Five, game.Six, game.King, game.Seven, game.Two, game.Two, game.Two} {
+		deck = append(deck, game.Card{Suit: game.Hearts, Rank: r})
+	}
+	g := &game.GameState{}
+	g.Record(game.Event{Type: game.EventBetPlaced, GameID: "pontoon", PlayerID: "punter", Amount: 10, Rules: &rules})
+	g.Record(game.Event{Type: game.EventDeckShuffled, Deck: deck})
+	g.Deal(game.SeatPlayer, 0, true)
+	g.Deal(game.SeatPlayer, 0, true)
+	g.Deal(game.SeatDealer, 0, false)
+	g.Deal(game.SeatDealer, 0, false)
+	controller.Store.Save(g)
+
+	resp := controller.maskDealerHand(g, before)
+	if resp.DealerHand.Cards[0].Rank != "" || resp.DealerHand.Cards[1].Rank != "" || resp.DealerHand.Score != 0 {
+		t.Fatalf("Expected both dealer cards hidden, got %+v", resp.DealerHand)
+	}
+	if len(resp.Actions) != 4 || resp.Actions[0] != "twist" || resp.Actions[1] != "stick" {
+		t.Errorf("Expected the Pontoon vocabulary, got %v", resp.Actions)
+	}
+
+	act("stick", http.StatusBadRequest)  // 11 is below the minimum of 15
+	act("double", http.StatusBadRequest) // Cards are bought instead
+	act("buy", http.StatusOK)
+	act("twist", http.StatusOK)
+	act("buy", http.StatusBadRequest) // No buying after a twist
+
+	// The fifth card makes a five-card trick of 17, beating the dealer's 17 at 2:1 on the bought stake too
+	resp = act("twist", http.StatusOK)
+	if resp.Status != game.StatusPlayerWon || resp.Hands[0].Bought != 10 {
+		t.Fatalf("Expected the five-card trick to win, got %s with %+v", resp.Status, resp.Hands[0])
+	}
+	if resp.PlayerBalance != before-10+60 {
+		t.Errorf("Expected balance %d, got %d", before-10+60, resp.PlayerBalance)
+	}
+}
+
+func TestTableLimits(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	controller := NewGameController()
+	router := gin.Default()
+	router.POST("/api/games", controller.StartGame)
+	router.POST("/api/games/:id/action", controller.PerformAction)
+	router.POST("/api/tables/:id/bet", controller.PlaceTableBet)
+	post := func(url string, body any, playerID string) (int, map[string]any) {
+		data, _ := json.Marshal(body)
+		req, _ := http.NewRequest("POST", url, bytes.NewBuffer(data))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Player-ID", playerID)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		var resp map[string]any
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		return w.Code, resp
+	}
+
+	tests := []struct {
+		name  string
+		req   StartGameRequest
+		code  string
+		limit float64
+	}{
+		{"AboveMax", StartGameRequest{BetAmount: 11}, game.LimitBetAboveMax, 10},
+		{"BelowMin", StartGameRequest{BetAmount: 10, Table: game.HighRollerProfile}, game.LimitBetBelowMin, 25},
+		{"Increment", StartGameRequest{BetAmount: 30, Table: game.HighRollerProfile}, game.LimitBetIncrement, 25},
+		{"UnknownTable", StartGameRequest{BetAmount: 10, Table: "vip"}, "", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, resp := post("/api/games", tt.req, "limited")
+			if status != http.StatusBadRequest {
+				t.Fatalf("Expected BadRequest, got %d: %v", status, resp)
+			}
+			if tt.code != "" && (resp["code"] != tt.code || resp["limit"] != tt.limit) {
+				t.Errorf("Expected code %s with limit %v, got %v", tt.code, tt.limit, resp)
+			}
+		})
+	}
+
+	// Unfinished games count against the player's exposure
+	for i := 0; i < 10; i++ {
+		controller.Store.Save(&game.GameState{ID: fmt.Sprintf("open-%d", i), PlayerID: "exposed", BetAmount: 10, Hands: []game.Hand{{}}, Status: game.StatusPlayerTurn})
+	}
+	if status, resp := post("/api/games", StartGameRequest{BetAmount: 1}, "exposed"); status != http.StatusBadRequest || resp["code"] != game.LimitPlayerExposure {
+		t.Errorf("Expected the player exposure limit, got %d: %v", status, resp)
+	}
+
+	// Multi-seat tables are dealt under the same limits
+	table := controller.Tables.Create("limits-table", game.DefaultTableConfig)
+	for _, playerID := range []string{"limited", "exposed"} {
+		controller.getOrCreatePlayer(playerID)
+		table.Join(playerID, -1)
+	}
+	if status, resp := post("/api/tables/limits-table/bet", TableBetRequest{BetAmount: 11}, "limited"); status != http.StatusBadRequest || resp["code"] != game.LimitBetAboveMax {
+		t.Errorf("Expected the table max bet, got %d: %v", status, resp)
+	}
+	if status, resp := post("/api/tables/limits-table/bet", TableBetRequest{BetAmount: 5}, "exposed"); status != http.StatusBadRequest || resp["code"] != game.LimitPlayerExposure {
+		t.Errorf("Expected the player exposure limit at a table, got %d: %v", status, resp)
+	}
+	if status, resp := post("/api/tables/limits-table/bet", TableBetRequest{BetAmount: 5}, "limited"); status != http.StatusOK {
+		t.Fatalf("Expected a table bet within the limits, got %d: %v", status, resp)
+	}
+	if got := controller.exposure("limited", nil); got != 5 {
+		t.Errorf("Expected the seated bet to count as exposure, got %d", got)
+	}
+
+	// Doubling may not take a game over its exposure limit
+	limits := game.TableProfile{MaxGameExposure: 15}
+	rules := game.DefaultRules
+	g := &game.GameState{}
+	g.Record(game.Event{Type: game.EventBetPlaced, GameID: "capped", PlayerID: "capped", Amount: 10, Rules: &rules, Limits: &limits})
+	g.Record(game.Event{Type: game.EventDeckShuffled, Deck: []game.Card{{Rank: game.Five}, {Rank: game.Six}, {Rank: game.King}, {Rank: game.Seven}, {Rank: game.Nine}}})
+	g.Deal(game.SeatPlayer, 0, true)
+	g.Deal(game.SeatPlayer, 0, true)
+	g.Deal(game.SeatDealer, 0, true)
+	g.Deal(game.SeatDealer, 0, false)
+	controller.getOrCreatePlayer("capped")
+	controller.Store.Save(g)
+	if status, resp := post("/api/games/capped/action", ActionRequest{Action: "double"}, "capped"); status != http.StatusBadRequest || resp["code"] != game.LimitGameExposure || resp["limit"] != 15.0 {
+		t.Errorf("Expected the game exposure limit, got %d: %v", status, resp)
+	}
+}
+
+func TestActionsRequireOwnership(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	controller := NewGameController()
+	router := gin.Default()
+	router.POST("/api/games", controller.StartGame)
+	router.POST("/api/games/:id/action", controller.PerformAction)
+	post := func(url, body, playerID string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("POST", url, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		if playerID != "" {
+			req.Header.Set("X-Player-ID", playerID)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	var started GameResponse
+	json.Unmarshal(post("/api/games", `{"bet_amount": 5}`, "owner").Body.Bytes(), &started)
+	g, _ := controller.Store.Get(started.ID)
+	events := len(g.Events)
+
+	if w := post("/api/games/"+started.ID+"/action", `{"action": "stand"}`, ""); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 without X-Player-ID, got %d", w.Code)
+	}
+	if w := post("/api/games/"+started.ID+"/action", `{"action": "stand"}`, "intruder"); w.Code != http.StatusForbidden {
+		t.Errorf("Expected 403 acting on someone else's game, got %d", w.Code)
+	}
+	if msgs := controller.handleWS("intruder", WSRequest{Type: "action", GameID: started.ID, Action: "stand"}); len(msgs) != 1 || msgs[0].Type != "error" {
+		t.Errorf("Expected an error acting on someone else's game over WebSocket, got %+v", msgs)
+	}
+	if len(g.Events) != events {
+		t.Errorf("Expected the game to be untouched, got %d events instead of %d", len(g.Events), events)
+	}
+}
+
+func TestConcurrentStartsKeepExposureLimit(t *testing.T) {
+	controller := NewGameController()
+	controller.getOrCreatePlayer("rush")
+	controller.Ledger.Credit("rush", game.TxBonus, "", 1000, "bonus")
+	profile, _ := game.LookupTableProfile(game.DefaultTableProfile)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 30; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			controller.startGame("rush", StartGameRequest{BetAmount: profile.MaxBet})
+		}()
+	}
+	wg.Wait()
+	if got := controller.exposure("rush", nil); got > profile.MaxPlayerExposure {
+		t.Errorf("Expected at most %d riding, got %d", profile.MaxPlayerExposure, got)
+	}
+}
+
+func TestExpiredGamesSettleLikeOthers(t *testing.T) {
+	controller := NewGameController()
+	janitor := game.NewJanitor(controller.Store, controller.HistoryStore, controller.Wallets(), game.JanitorConfig{IdleTimeout: time.Minute, Policy: game.ExpiryRefund})
+	janitor.OnExpire = controller.OnExpired
+
+	// Keep dealing until a game waits on the player; naturals settle on the deal
+	var abandoned *game.GameState
+	for abandoned == nil {
+		g, _, apiErr := controller.startGame("idle-player", StartGameRequest{BetAmount: 10})
+		if apiErr != nil {
+			t.Fatalf("start: %s", apiErr.Message)
+		}
+		if g.Status == game.StatusPlayerTurn {
+			abandoned = g
+		}
+	}
+	before, _ := controller.Stats.Get("idle-player")
+
+	notifications, unsubscribe := controller.Bus.Subscribe(func(n game.Notification) bool { return n.GameID == abandoned.ID })
+	defer unsubscribe()
+	if _, expired := janitor.Sweep(time.Now().Add(time.Hour)); expired != 1 {
+		t.Fatalf("Expected the abandoned game to expire, got %d", expired)
+	}
+
+	settled := false
+	for len(notifications) > 0 {
+		if n := <-notifications; n.Type == game.NotifyGameEvent && n.Event.Type == game.EventSettled {
+			settled = true
+		}
+	}
+	if !settled {
+		t.Error("Expected the expiry to be published")
+	}
+	if after, _ := controller.Stats.Get("idle-player"); after.HandsPlayed != before.HandsPlayed+1 || after.Pushes != before.Pushes+1 {
+		t.Errorf("Expected the refunded game to count as a push, got %+v", after)
+	}
+}
+
+func TestStartGameRefundsPartialBets(t *testing.T) {
+	controller := NewGameController()
+	controller.getOrCreatePlayer("racer")
+
+	// Something else spends the rest of the balance right after the main bet is posted
+	notify := controller.Ledger.Notify
+	controller.Ledger.Notify = func(tx game.Transaction) {
+		notify(tx)
+		if tx.Kind == game.TxBet && tx.Reason == "bet" {
+			controller.Ledger.Debit("racer", game.TxBet, "elsewhere", tx.BalanceAfter, "table bet")
+		}
+	}
+	_, _, apiErr := controller.startGame("racer", StartGameRequest{BetAmount: 10, SideBets: map[string]int{"bust_it": 5}})
+	if apiErr == nil || apiErr.Status != http.StatusBadRequest {
+		t.Fatalf("Expected the side bet to fail for lack of funds, got %+v", apiErr)
+	}
+
+	txs := controller.Ledger.Transactions("racer")
+	if len(txs) == 0 || txs[0].Kind != game.TxRefund || txs[0].Amount != 10 || txs[0].BalanceAfter != 10 {
+		t.Errorf("Expected the main bet to be refunded, got %+v", txs)
+	}
+	if games := controller.Store.All(); len(games) != 0 {
+		t.Errorf("Expected no game to be dealt, got %d", len(games))
+	}
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"blackjack-api/game"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// LeaderboardResponse DTO; one page of a leaderboard plus the caller's own rank
+type LeaderboardResponse struct {
+	Metric  game.LeaderboardMetric `json:"metric"`
+	Window  game.LeaderboardWindow `json:"window"`
+	Page    int                    `json:"page"`
+	PerPage int                    `json:"per_page"`
+	Total   int                    `json:"total"` // Ranked players across all pages
+	Entries []LeaderboardRow       `json:"entries"`
+	You     *LeaderboardRow        `json:"you"` // Null if the caller is not ranked
+}
+
+// LeaderboardRow is a ranked player as shown to others
+type LeaderboardRow struct {
+	Rank   int     `json:"rank"`
+	Player string  `json:"player"` // Anonymized unless it is the caller
+	You    bool    `json:"you,omitempty"`
+	Value  float64 `json:"value"`
+	Games  int     `json:"games"`
+}
+
+// GetLeaderboard handles GET /api/leaderboards/:metric?window=daily|weekly|all-time&page=1&per_page=20
+func (c *GameController) GetLeaderboard(ctx *gin.Context) {
+	metric := game.LeaderboardMetric(ctx.Param("metric"))
+	window := game.LeaderboardWindow(ctx.DefaultQuery("window", string(game.WindowAllTime)))
+	page, err := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "page must be a positive integer"})
+		return
+	}
+	perPage, err := strconv.Atoi(ctx.DefaultQuery("per_page", strconv.Itoa(defaultPageSize)))
+	if err != nil || perPage < 1 || perPage > maxPageSize {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "per_page must be between 1 and " + strconv.Itoa(maxPageSize)})
+		return
+	}
+
+	entries, err := c.Leaderboards.Rank(metric, window, time.Now())
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	playerID := ctx.GetHeader("X-Player-ID")
+	resp := LeaderboardResponse{
+		Metric:  metric,
+		Window:  window,
+		Page:    page,
+		PerPage: perPage,
+		Total:   len(entries),
+		Entries: []LeaderboardRow{},
+	}
+	for i, e := range entries {
+		row := leaderboardRow(e, playerID)
+		if row.You {
+			resp.You = &row
+		}
+		if i >= (page-1)*perPage && i < page*perPage {
+			resp.Entries = append(resp.Entries, row)
+		}
+	}
+	ctx.JSON(http.StatusOK, resp)
+}
+
+// leaderboardRow anonymizes an entry unless it belongs to playerID
+func leaderboardRow(e game.LeaderboardEntry, playerID string) LeaderboardRow {
+	row := LeaderboardRow{Rank: e.Rank, Player: anonymize(e.PlayerID), Value: e.Value, Games: e.Games}
+	if playerID != "" && e.PlayerID == playerID {
+		row.Player, row.You = playerID, true
+	}
+	return row
+}
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"blackjack-api/game"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestGetLeaderboard(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	controller := NewGameController()
+	router := gin.Default()
+	router.GET("/api/leaderboards/:metric", controller.GetLeaderboard)
+
+	now := time.Now()
+	for i, id := range []string{"first", "second", "third"} {
+		controller.Leaderboards.Record(game.Settlement{PlayerID: id, Time: now, Result: game.StatusPlayerWon, Staked: 10, Payout: 10 + 30 - 10*i})
+	}
+
+	get := func(url, playerID string) (*httptest.ResponseRecorder, LeaderboardResponse) {
+		req, _ := http.NewRequest("GET", url, nil)
+		req.Header.Set("X-Player-ID", playerID)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		var resp LeaderboardResponse
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		return w, resp
+	}
+
+	w, resp := get("/api/leaderboards/net?window=daily&page=2&per_page=1", "third")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if resp.Total != 3 || len(resp.Entries) != 1 || resp.Entries[0].Rank != 2 || resp.Entries[0].Value != 20 {
+		t.Errorf("Expected the second place on page 2, got %+v", resp)
+	}
+	if resp.Entries[0].Player == "second" {
+		t.Errorf("Expected other players to be anonymized")
+	}
+	if resp.You == nil || resp.You.Rank != 3 || resp.You.Player != "third" {
+		t.Errorf("Expected the caller's own rank, got %+v", resp.You)
+	}
+
+	if w, _ := get("/api/leaderboards/luck", ""); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an unknown metric, got %d", w.Code)
+	}
+	if w, _ := get("/api/leaderboards/net?per_page=1000", ""); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an oversized page, got %d", w.Code)
+	}
+}
+
+func TestSettledGamesFeedLeaderboards(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	controller := NewGameController()
+	router := gin.Default()
+	router.POST("/api/games", controller.StartGame)
+	router.POST("/api/games/:id/action", controller.PerformAction)
+
+	post := func(url, body string) GameResponse {
+		req, _ := http.NewRequest("POST", url, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Player-ID", "ranked")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		var resp GameResponse
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		return resp
+	}
+
+	g := post("/api/games", `{"bet_amount": 10}`)
+	if g.Status == game.StatusPlayerTurn {
+		g = post("/api/games/"+g.ID+"/action", `{"action": "stand"}`)
+	}
+
+	entries, _ := controller.Leaderboards.Rank(game.MetricNet, game.WindowAllTime, time.Now())
+	if len(entries) != 1 || entries[0].PlayerID != "ranked" || entries[0].Games != 1 {
+		t.Fatalf("Expected the settled game on the leaderboard, got %+v", entries)
+	}
+	if want := float64(g.PlayerBalance - 100); entries[0].Value != want {
+		t.Errorf("Expected net %v, got %v", want, entries[0].Value)
+	}
+}
@@ -0,0 +1,13 @@
+package handlers
+
+import (
+	"blackjack-api/game"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListTableProfiles handles GET /api/table-profiles
+func (c *GameController) ListTableProfiles(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, game.TableProfiles())
+}
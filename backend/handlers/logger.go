@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"bytes"
+	"encoding/json"
 	"io"
 	"sync"
 	"time"
@@ -18,6 +19,9 @@ type LogEntry struct {
 	ResponseBody string    `json:"response_body"`
 }
 
+// redactedPlayerID stands in for player IDs in the request log
+const redactedPlayerID = "[player]"
+
 var (
 	requestLogs []LogEntry
 	logMutex    sync.RWMutex
@@ -46,9 +50,9 @@ func StatsMiddleware() gin.HandlerFunc {
 			Timestamp:    start,
 			Method:       c.Request.Method,
 			Path:         c.Request.URL.Path,
-			RequestBody:  string(reqBodyBytes),
+			RequestBody:  redactBody(reqBodyBytes),
 			Status:       c.Writer.Status(),
-			ResponseBody: blw.body.String(),
+			ResponseBody: redactBody(blw.body.Bytes()),
 		})
 		// Keep log size manageable? Let's keep last 100 for now to avoid memory leak
 		if len(requestLogs) > 100 {
@@ -58,6 +62,46 @@ func StatsMiddleware() gin.HandlerFunc {
 	}
 }
 
+// redactBody blanks out every player_id field of a JSON body: the log is served to anyone
+// on /stats, and a player ID is the player's credential. Other bodies are logged as they are.
+func redactBody(body []byte) string {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var v any
+	if err := decoder.Decode(&v); err != nil || !redactPlayerIDs(v) {
+		return string(body)
+	}
+	redacted, err := json.Marshal(v)
+	if err != nil {
+		return string(body)
+	}
+	return string(redacted)
+}
+
+// redactPlayerIDs replaces the player_id fields found anywhere in a decoded JSON value;
+// it reports whether there were any
+func redactPlayerIDs(v any) bool {
+	found := false
+	switch v := v.(type) {
+	case map[string]any:
+		for key, field := range v {
+			if id, ok := field.(string); ok && key == "player_id" && id != "" {
+				v[key] = redactedPlayerID
+				found = true
+			} else if redactPlayerIDs(field) {
+				found = true
+			}
+		}
+	case []any:
+		for _, item := range v {
+			if redactPlayerIDs(item) {
+				found = true
+			}
+		}
+	}
+	return found
+}
+
 type bodyLogWriter struct {
 	gin.ResponseWriter
 	body *bytes.Buffer
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestStatsRedactPlayerID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/stats", GetStats)
+	api := router.Group("/api", StatsMiddleware())
+	api.POST("/echo/1", func(ctx *gin.Context) {
+		ctx.JSON(http.StatusOK, gin.H{"player_id": ctx.GetHeader("X-Player-ID"), "game_id": "g1", "hands": []gin.H{{"rank": "1", "bet": 1}}})
+	})
+
+	// A short ID must not rewrite anything but the player_id fields
+	req, _ := http.NewRequest("POST", "/api/echo/1", strings.NewReader(`{"player_id": "1", "bet_amount": 1}`))
+	req.Header.Set("X-Player-ID", "1")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	w := httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/stats", nil)
+	router.ServeHTTP(w, req)
+	var logs []LogEntry
+	json.Unmarshal(w.Body.Bytes(), &logs)
+	if len(logs) != 1 || logs[0].Path != "/api/echo/1" {
+		t.Fatalf("Expected the request path untouched, got %+v", logs)
+	}
+	var request, response map[string]any
+	json.Unmarshal([]byte(logs[0].RequestBody), &request)
+	json.Unmarshal([]byte(logs[0].ResponseBody), &response)
+	if request["player_id"] != redactedPlayerID || request["bet_amount"] != 1.0 {
+		t.Errorf("Expected only the player ID redacted from the request, got %s", logs[0].RequestBody)
+	}
+	hand, _ := response["hands"].([]any)[0].(map[string]any)
+	if response["player_id"] != redactedPlayerID || response["game_id"] != "g1" || hand["rank"] != "1" || hand["bet"] != 1.0 {
+		t.Errorf("Expected only the player ID redacted from the response, got %s", logs[0].ResponseBody)
+	}
+}
@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"blackjack-api/game"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TransactionsResponse DTO listing a player's ledger
+type TransactionsResponse struct {
+	PlayerID      string             `json:"player_id"`
+	Balance       int                `json:"balance"`
+	LedgerBalance int                `json:"ledger_balance"`
+	Reconciled    bool               `json:"reconciled"` // Balance matches the ledger
+	Transactions  []game.Transaction `json:"transactions"`
+}
+
+// PlayerResponse DTO
+type PlayerResponse struct {
+	ID      string       `json:"id"`
+	Balance int          `json:"balance"`
+	Badges  []game.Badge `json:"badges"`
+}
+
+// GetPlayer handles GET /api/players/me
+func (c *GameController) GetPlayer(ctx *gin.Context) {
+	player, ok := c.currentPlayer(ctx)
+	if !ok {
+		return
+	}
+
+	// player is a copy, so its badges can be read without the store lock
+	badges := append([]game.Badge{}, player.Badges...)
+	ctx.JSON(http.StatusOK, PlayerResponse{ID: player.ID, Balance: player.Balance, Badges: badges})
+}
+
+// ListAchievements handles GET /api/achievements
+func (c *GameController) ListAchievements(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, c.Achievements.List())
+}
+
+// GetPlayerStats handles GET /api/players/me/stats and GET /api/players/:id/stats, for the caller only
+func (c *GameController) GetPlayerStats(ctx *gin.Context) {
+	player, ok := c.currentPlayer(ctx)
+	if !ok {
+		return
+	}
+
+	stats, exists := c.Stats.Get(player.ID)
+	if !exists {
+		// Nothing settled yet
+		stats = game.PlayerStats{PlayerID: player.ID}
+	}
+	if stats.ByStartingHand == nil {
+		stats.ByStartingHand = map[string]map[string]*game.ResultBucket{}
+	}
+	ctx.JSON(http.StatusOK, stats)
+}
+
+// GetAccuracy handles GET /api/players/me/accuracy
+// Rule sets the strategy calculator does not model (see game.UnscoredReason) are counted under "unscored".
+func (c *GameController) GetAccuracy(ctx *gin.Context) {
+	player, ok := c.currentPlayer(ctx)
+	if !ok {
+		return
+	}
+
+	decisions, exists := c.Decisions.Get(player.ID)
+	if !exists {
+		decisions = game.PlayerDecisions{PlayerID: player.ID, ByCategory: map[game.HandCategory]*game.Accuracy{}, Unscored: map[game.Variant]*game.Unscored{}}
+	}
+	ctx.JSON(http.StatusOK, decisions)
+}
+
+// GetMistakes handles GET /api/players/me/mistakes?limit=10, costliest first
+func (c *GameController) GetMistakes(ctx *gin.Context) {
+	player, ok := c.currentPlayer(ctx)
+	if !ok {
+		return
+	}
+	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 || limit > game.MaxMistakesPerPlayer {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and " + strconv.Itoa(game.MaxMistakesPerPlayer)})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"player_id": player.ID, "mistakes": c.Decisions.Mistakes(player.ID, limit)})
+}
+
+// GetTransactions handles GET /api/players/me/transactions
+func (c *GameController) GetTransactions(ctx *gin.Context) {
+	player, ok := c.currentPlayer(ctx)
+	if !ok {
+		return
+	}
+
+	balance, ledgerBalance, reconciled := c.Ledger.Reconcile(player.ID)
+	ctx.JSON(http.StatusOK, TransactionsResponse{
+		PlayerID:      player.ID,
+		Balance:       balance,
+		LedgerBalance: ledgerBalance,
+		Reconciled:    reconciled,
+		Transactions:  c.Ledger.Transactions(player.ID),
+	})
+}
+
+// currentPlayer loads the player identified by X-Player-ID.
+// Routes under /api/players/me use it, so the credential never shows up in a request path;
+// on routes naming a player with :id, that player must be the caller.
+func (c *GameController) currentPlayer(ctx *gin.Context) (*game.Player, bool) {
+	id, ok := requirePlayerID(ctx)
+	if !ok {
+		return nil, false
+	}
+	if named := ctx.Param("id"); named != "" && named != id {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "Players may only read their own data"})
+		return nil, false
+	}
+	player, exists := c.PlayerStore.Get(id)
+	if !exists {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Player not found"})
+		return nil, false
+	}
+	return player, true
+}
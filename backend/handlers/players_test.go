@@ -0,0 +1,212 @@
+package handlers
+
+import (
+	"blackjack-api/game"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestGetPlayerShowsBadges(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	controller := NewGameController()
+	router := gin.Default()
+	router.GET("/api/players/me", controller.GetPlayer)
+
+	controller.getOrCreatePlayer("p1")
+	controller.onSettled(game.Settlement{PlayerID: "p1", GameID: "g1", Time: time.Now(), Result: game.StatusPlayerWon, Staked: 10, Payout: 20, Balance: 110})
+
+	get := func(playerID string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", "/api/players/me", nil)
+		req.Header.Set("X-Player-ID", playerID)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := get("someone-else"); w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown player, got %d", w.Code)
+	}
+
+	w := get("p1")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", w.Code)
+	}
+	var resp PlayerResponse
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	if len(resp.Badges) != 1 || resp.Badges[0].ID != "first_win" {
+		t.Errorf("Expected the first_win badge, got %+v", resp.Badges)
+	}
+}
+
+func TestDoubleFeedsStats(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	controller := NewGameController()
+	router := gin.Default()
+	router.POST("/api/games/:id/action", controller.PerformAction)
+	router.GET("/api/players/me/stats", controller.GetPlayerStats)
+	router.GET("/api/players/:id/stats", controller.GetPlayerStats)
+
+	controller.getOrCreatePlayer("p1")
+	controller.getOrCreatePlayer("p2")
+	controller.Ledger.Debit("p1", game.TxBet, "g1", 10, "bet")
+
+	deck := []game.Card{
+		{Suit: game.Hearts, Rank: game.Five}, {Suit: game.Spades, Rank: game.Six},
+		{Suit: game.Clubs, Rank: game.King}, {Suit: game.Diamonds, Rank: game.Seven},
+		{Suit: game.Clubs, Rank: game.Ten},
+	}
+	g := &game.GameState{}
+	g.Record(game.Event{Type: game.EventBetPlaced, GameID: "g1", PlayerID: "p1", Amount: 10})
+	g.Record(game.Event{Type: game.EventDeckShuffled, Deck: deck})
+	g.Deal(game.SeatPlayer, 0, true)
+	g.Deal(game.SeatPlayer, 0, true)
+	g.Deal(game.SeatDealer, 0, true)
+	g.Deal(game.SeatDealer, 0, false)
+	controller.Store.Save(g)
+
+	req, _ := http.NewRequest("POST", "/api/games/g1/action", bytes.NewBufferString(`{"action": "double"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Player-ID", "p1")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var resp GameResponse
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	// 21 against the dealer's 17 pays back twice the doubled bet: 100 - 20 + 40
+	if resp.Status != game.StatusPlayerWon || !resp.PlayerHand.Doubled || resp.PlayerBalance != 120 {
+		t.Fatalf("Expected a doubled win with balance 120, got %+v", resp)
+	}
+
+	req, _ = http.NewRequest("GET", "/api/players/me/stats", nil)
+	req.Header.Set("X-Player-ID", "p1")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var stats game.PlayerStats
+	json.Unmarshal(w.Body.Bytes(), &stats)
+	if stats.HandsPlayed != 1 || stats.Doubles != 1 || stats.TotalBet != 20 || stats.Net != 20 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+	if b := stats.ByStartingHand["hard 11"]["10"]; b == nil || b.Wins != 1 {
+		t.Errorf("Expected a win in the hard 11 vs 10 bucket, got %+v", stats.ByStartingHand)
+	}
+
+	// The stats are also served under the player's ID, to that player only
+	for _, tt := range []struct {
+		caller string
+		status int
+	}{{"p1", http.StatusOK}, {"p2", http.StatusForbidden}} {
+		req, _ = http.NewRequest("GET", "/api/players/p1/stats", nil)
+		req.Header.Set("X-Player-ID", tt.caller)
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != tt.status {
+			t.Errorf("%s: expected %d for p1's stats, got %d", tt.caller, tt.status, w.Code)
+		}
+	}
+}
+
+func TestDecisionsAreScored(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	controller := NewGameController()
+	router := gin.Default()
+	router.POST("/api/games/:id/action", controller.PerformAction)
+	router.GET("/api/players/me/accuracy", controller.GetAccuracy)
+	router.GET("/api/players/me/mistakes", controller.GetMistakes)
+
+	controller.getOrCreatePlayer("p1")
+	deck := []game.Card{
+		{Suit: game.Hearts, Rank: game.Ten}, {Suit: game.Spades, Rank: game.Six},
+		{Suit: game.Clubs, Rank: game.King}, {Suit: game.Diamonds, Rank: game.Seven},
+	}
+	g := &game.GameState{}
+	g.Record(game.Event{Type: game.EventBetPlaced, GameID: "g1", PlayerID: "p1", Amount: 10})
+	g.Record(game.Event{Type: game.EventDeckShuffled, Deck: deck})
+	g.Deal(game.SeatPlayer, 0, true)
+	g.Deal(game.SeatPlayer, 0, true)
+	g.Deal(game.SeatDealer, 0, true)
+	g.Deal(game.SeatDealer, 0, false)
+	controller.Store.Save(g)
+
+	// The same hand in Spanish 21, which basic strategy does not cover
+	spanish := &game.GameState{}
+	spanish.Record(game.Event{Type: game.EventBetPlaced, GameID: "g2", PlayerID: "p1", Amount: 10, Rules: &game.Spanish21Rules})
+	spanish.Record(game.Event{Type: game.EventDeckShuffled, Deck: append([]game.Card(nil), deck...)})
+	spanish.Deal(game.SeatPlayer, 0, true)
+	spanish.Deal(game.SeatPlayer, 0, true)
+	spanish.Deal(game.SeatDealer, 0, true)
+	spanish.Deal(game.SeatDealer, 0, false)
+	controller.Store.Save(spanish)
+
+	do := func(method, url, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, url, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Player-ID", "p1")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// Invalid actions are not scored
+	do("POST", "/api/games/g1/action", `{"action": "split"}`)
+	// Standing on 16 against a 10 is a mistake
+	do("POST", "/api/games/g1/action", `{"action": "stand"}`)
+	// In Spanish 21 it is counted, but not scored
+	do("POST", "/api/games/g2/action", `{"action": "stand"}`)
+
+	var accuracy game.PlayerDecisions
+	json.Unmarshal(do("GET", "/api/players/me/accuracy", "").Body.Bytes(), &accuracy)
+	if accuracy.Overall.Decisions != 1 || accuracy.Overall.Correct != 0 || accuracy.ByCategory[game.CategoryHard] == nil {
+		t.Errorf("Expected one wrong hard decision, got %+v", accuracy)
+	}
+	if u := accuracy.Unscored[game.VariantSpanish21]; u == nil || u.Decisions != 1 || u.Reason == "" {
+		t.Errorf("Expected one unscored Spanish 21 decision with a reason, got %+v", accuracy.Unscored)
+	}
+
+	var resp struct {
+		Mistakes []game.Decision `json:"mistakes"`
+	}
+	json.Unmarshal(do("GET", "/api/players/me/mistakes?limit=5", "").Body.Bytes(), &resp)
+	if len(resp.Mistakes) != 1 || resp.Mistakes[0].Best != game.ActionHit || resp.Mistakes[0].Cost <= 0 {
+		t.Errorf("Expected the stand to be listed as a mistake, got %+v", resp.Mistakes)
+	}
+}
+
+func TestGetTransactionsOfCurrentPlayer(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	controller := NewGameController()
+	router := gin.Default()
+	router.GET("/api/players/me/transactions", controller.GetTransactions)
+
+	controller.getOrCreatePlayer("p1")
+	controller.getOrCreatePlayer("p2")
+	get := func(playerID string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", "/api/players/me/transactions", nil)
+		if playerID != "" {
+			req.Header.Set("X-Player-ID", playerID)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := get(""); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 without X-Player-ID, got %d", w.Code)
+	}
+	if w := get("nobody"); w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown player, got %d", w.Code)
+	}
+
+	w := get("p1")
+	var resp TransactionsResponse
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	if w.Code != http.StatusOK || resp.PlayerID != "p1" || !resp.Reconciled || len(resp.Transactions) != 1 {
+		t.Errorf("Expected the welcome bonus of p1 only, got %d %+v", w.Code, resp)
+	}
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"blackjack-api/game"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// CreatePromotionRequest DTO
+type CreatePromotionRequest struct {
+	Name     string         `json:"name" binding:"required"`
+	Rule     string         `json:"rule" binding:"required"` // Bonus rule, e.g. "suited 6-7-8 pays 2:1"
+	Variants []game.Variant `json:"variants"`                // Empty for every variant
+	StartsAt *time.Time     `json:"starts_at"`               // Defaults to now
+	EndsAt   time.Time      `json:"ends_at" binding:"required"`
+}
+
+// ListPromotions handles GET /api/promotions: the bonus rules running now
+func (c *GameController) ListPromotions(ctx *gin.Context) {
+	now := time.Now()
+	promotions := []game.Promotion{}
+	for _, p := range c.Promotions.All() {
+		if p.Running(now) {
+			promotions = append(promotions, p)
+		}
+	}
+	ctx.JSON(http.StatusOK, promotions)
+}
+
+// ListAllPromotions handles GET /api/admin/promotions, past and scheduled ones included
+func (c *GameController) ListAllPromotions(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, c.Promotions.All())
+}
+
+// CreatePromotion handles POST /api/admin/promotions
+func (c *GameController) CreatePromotion(ctx *gin.Context) {
+	var req CreatePromotionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	p := game.Promotion{
+		ID:       uuid.New().String(),
+		Name:     req.Name,
+		Rule:     req.Rule,
+		Variants: req.Variants,
+		StartsAt: time.Now(),
+		EndsAt:   req.EndsAt,
+	}
+	if req.StartsAt != nil {
+		p.StartsAt = *req.StartsAt
+	}
+	if err := p.Validate(); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Promotions.Save(p)
+	ctx.JSON(http.StatusCreated, p)
+}
+
+// DeletePromotion handles DELETE /api/admin/promotions/:id; games already dealt keep the bonus
+func (c *GameController) DeletePromotion(ctx *gin.Context) {
+	if !c.Promotions.Delete(ctx.Param("id")) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Promotion not found"})
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestPromotions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	controller := NewGameController()
+	router := gin.Default()
+	router.POST("/api/games", controller.StartGame)
+	router.GET("/api/promotions", controller.ListPromotions)
+	admin := router.Group("/api/admin", RequireAdmin("secret"))
+	admin.POST("/promotions", controller.CreatePromotion)
+	admin.DELETE("/promotions/:id", controller.DeletePromotion)
+
+	do := func(method, url, body string, headers map[string]string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, url, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	adminHeaders := map[string]string{"X-Admin-Token": "secret"}
+	player := map[string]string{"X-Player-ID": "promo"}
+	ends := time.Now().Add(time.Hour).Format(time.RFC3339)
+
+	if w := do("POST", "/api/admin/promotions", `{"name": "Typo", "rule": "suited 6-7-8 pay 2:1", "ends_at": "`+ends+`"}`, adminHeaders); w.Code != http.StatusBadRequest {
+		t.Fatalf("Expected 400 for a bad rule, got %d", w.Code)
+	}
+	w := do("POST", "/api/admin/promotions", `{"name": "Summer", "rule": "suited 6-7-8 pays 2:1", "ends_at": "`+ends+`"}`, adminHeaders)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var created struct {
+		ID string `json:"id"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &created)
+
+	if w := do("GET", "/api/promotions", "", nil); !bytes.Contains(w.Body.Bytes(), []byte("Summer")) {
+		t.Errorf("Expected the running promotion to be listed, got %s", w.Body.String())
+	}
+
+	// Games dealt while the promotion runs carry its bonus rule
+	w = do("POST", "/api/games", `{"bet_amount": 1, "charlie": 6}`, player)
+	var resp GameResponse
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	if len(resp.Rules.Bonuses) != 1 || resp.Rules.Bonuses[0] != "suited 6-7-8 pays 2:1" || resp.Rules.Charlie != 6 {
+		t.Errorf("Expected the bonus rule and a six-card Charlie, got %+v", resp.Rules)
+	}
+
+	if w := do("DELETE", "/api/admin/promotions/"+created.ID, "", adminHeaders); w.Code != http.StatusNoContent {
+		t.Fatalf("Expected 204, got %d", w.Code)
+	}
+	w = do("POST", "/api/games", `{"bet_amount": 1}`, player)
+	resp = GameResponse{}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	if len(resp.Rules.Bonuses) != 0 {
+		t.Errorf("Expected no bonus rules once the promotion is gone, got %v", resp.Rules.Bonuses)
+	}
+
+	if w := do("POST", "/api/games", `{"bet_amount": 1, "charlie": 9}`, player); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a nine-card Charlie, got %d", w.Code)
+	}
+}
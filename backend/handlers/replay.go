@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"blackjack-api/game"
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ReplayFrame is the table as it looked right after one event
+type ReplayFrame struct {
+	Event            game.Event      `json:"event"`
+	PlayerHand       game.Hand       `json:"player_hand"`
+	SplitHand        *game.Hand      `json:"split_hand,omitempty"`
+	Hands            []game.Hand     `json:"hands"`
+	CurrentHandIndex int             `json:"current_hand_index"`
+	DealerHand       game.Hand       `json:"dealer_hand"`
+	Status           game.GameStatus `json:"status"`
+}
+
+// ReplayResponse DTO for a finished game
+type ReplayResponse struct {
+	ID         string          `json:"id"`
+	BetAmount  int             `json:"bet_amount"`
+	Status     game.GameStatus `json:"status"`
+	ShareToken string          `json:"share_token,omitempty"` // Only returned to the owner
+	Frames     []ReplayFrame   `json:"frames"`
+}
+
+// GetReplay handles GET /api/games/:id/replay
+// The owner identifies with X-Player-ID; anyone else needs the share token (?token=).
+func (c *GameController) GetReplay(ctx *gin.Context) {
+	gameState, archived, exists := c.findGame(ctx.Param("id"))
+	if !exists {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Game not found"})
+		return
+	}
+	gameState.Lock()
+	defer gameState.Unlock()
+
+	if !gameState.IsFinished() {
+		ctx.JSON(http.StatusConflict, gin.H{"error": "Replay is only available once the game is over"})
+		return
+	}
+
+	owner := ctx.GetHeader("X-Player-ID") != "" && ctx.GetHeader("X-Player-ID") == gameState.PlayerID
+	token := ctx.Query("token")
+	shared := token != "" && gameState.ShareToken != "" &&
+		subtle.ConstantTimeCompare([]byte(token), []byte(gameState.ShareToken)) == 1
+	if !owner && !shared {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "Not allowed to view this replay"})
+		return
+	}
+
+	resp := ReplayResponse{
+		ID:        gameState.ID,
+		BetAmount: gameState.BetAmount,
+		Status:    gameState.Status,
+		Frames:    replayFrames(gameState.Events),
+	}
+	if !owner {
+		// The player ID is the owner's only credential; viewers see the hands, not who played them
+		for i := range resp.Frames {
+			resp.Frames[i].Event.PlayerID = ""
+			resp.Frames[i].Event.Tournament = ""
+		}
+	}
+
+	if owner {
+		// Hand out a share token the first time the owner asks for the replay
+		if gameState.ShareToken == "" {
+			gameState.ShareToken = uuid.New().String()
+			if archived {
+				c.HistoryStore.Save(gameState)
+			} else {
+				c.Store.Save(gameState)
+			}
+		}
+		resp.ShareToken = gameState.ShareToken
+	}
+
+	ctx.JSON(http.StatusOK, resp)
+}
+
+// replayFrames turns an event log into frames with cards revealed progressively
+func replayFrames(events []game.Event) []ReplayFrame {
+	frames := []ReplayFrame{}
+	for _, step := range game.Steps(events) {
+		e := step.Events[len(step.Events)-1]
+		if e.Type == game.EventDeckShuffled {
+			continue // Nothing visible happens
+		}
+		playerHand, splitHand := legacyHands(step.Hands)
+		frames = append(frames, ReplayFrame{
+			Event:            maskEvent(e, step.HoleRevealed()),
+			PlayerHand:       playerHand,
+			SplitHand:        splitHand,
+			Hands:            step.Hands,
+			CurrentHandIndex: step.CurrentHandIndex,
+			DealerHand:       visibleDealerHand(step),
+			Status:           step.Status,
+		})
+	}
+	return frames
+}
+
+// maskEvent hides the card of a face-down deal until the hole card is revealed
+func maskEvent(e game.Event, revealed bool) game.Event {
+	if e.Type == game.EventCardDealt && !e.FaceUp && !revealed {
+		e.Card = &game.Card{}
+	}
+	return e
+}
+
+// visibleEvents returns the events from index from on, masked as they were when they happened.
+// The deck shuffle is left out since it would reveal every card.
+func visibleEvents(events []game.Event, from int) []game.Event {
+	visible := []game.Event{}
+	revealed := false
+	for i, e := range events {
+		if e.Type == game.EventHoleRevealed {
+			revealed = true
+		}
+		if i < from || e.Type == game.EventDeckShuffled {
+			continue
+		}
+		visible = append(visible, maskEvent(e, revealed))
+	}
+	return visible
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"blackjack-api/game"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestReplay(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	controller := NewGameController()
+	router := gin.Default()
+	router.GET("/api/games/:id/replay", controller.GetReplay)
+
+	deck := []game.Card{
+		{Suit: game.Hearts, Rank: game.Ten}, {Suit: game.Spades, Rank: game.Nine},
+		{Suit: game.Clubs, Rank: game.Ten}, {Suit: game.Diamonds, Rank: game.Seven},
+	}
+	g := &game.GameState{}
+	g.Record(game.Event{Type: game.EventBetPlaced, GameID: "g1", PlayerID: "owner", Amount: 10})
+	g.Record(game.Event{Type: game.EventDeckShuffled, Deck: deck})
+	g.Deal(game.SeatPlayer, 0, true)
+	g.Deal(game.SeatPlayer, 0, true)
+	g.Deal(game.SeatDealer, 0, true)
+	g.Deal(game.SeatDealer, 0, false)
+	controller.Store.Save(g)
+
+	get := func(url, playerID string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", url, nil)
+		if playerID != "" {
+			req.Header.Set("X-Player-ID", playerID)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// Not available while the game is in progress
+	if w := get("/api/games/g1/replay", "owner"); w.Code != http.StatusConflict {
+		t.Fatalf("Expected Conflict for unfinished game, got %v", w.Code)
+	}
+
+	g.Record(game.Event{Type: game.EventActionTaken, Action: "stand"})
+	g.Settle(game.StatusPlayerWon, 20)
+
+	w := get("/api/games/g1/replay", "owner")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected StatusOK for owner, got %v", w.Code)
+	}
+	var resp ReplayResponse
+	json.Unmarshal(w.Body.Bytes(), &resp)
+
+	if resp.ShareToken == "" {
+		t.Fatal("Expected owner to receive a share token")
+	}
+	// bet, 4 deals, stand, reveal, settle (the shuffle is skipped)
+	if len(resp.Frames) != 8 {
+		t.Fatalf("Expected 8 frames, got %d", len(resp.Frames))
+	}
+	holeDeal := resp.Frames[4]
+	if holeDeal.Event.Card.Rank != "" || holeDeal.DealerHand.Cards[1].Rank != "" {
+		t.Errorf("Expected hole card to be hidden before the reveal, got %+v", holeDeal)
+	}
+	last := resp.Frames[len(resp.Frames)-1]
+	if last.DealerHand.Cards[1].Rank != game.Seven || last.Status != game.StatusPlayerWon {
+		t.Errorf("Expected hole card revealed in final frame, got %+v", last)
+	}
+
+	if w := get("/api/games/g1/replay", "someone-else"); w.Code != http.StatusForbidden {
+		t.Errorf("Expected Forbidden without token, got %v", w.Code)
+	}
+
+	w = get("/api/games/g1/replay?token="+resp.ShareToken, "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected StatusOK with share token, got %v", w.Code)
+	}
+	var shared ReplayResponse
+	json.Unmarshal(w.Body.Bytes(), &shared)
+	if shared.ShareToken != "" {
+		t.Error("Expected share token to be withheld from viewers")
+	}
+	if bytes.Contains(w.Body.Bytes(), []byte("owner")) {
+		t.Errorf("Expected the owner's ID to be withheld from viewers, got %s", w.Body.String())
+	}
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"blackjack-api/game"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetLimitsRequest DTO: the limits to change; omitted limits stay as they are, 0 removes one.
+// Lower limits apply at once, higher ones after game.SafeguardRaiseDelay.
+type SetLimitsRequest struct {
+	DailyLoss      *int `json:"daily_loss"`
+	WeeklyLoss     *int `json:"weekly_loss"`
+	DailyWager     *int `json:"daily_wager"`
+	TotalWager     *int `json:"total_wager"`
+	SessionMinutes *int `json:"session_minutes"`
+}
+
+// PeriodRequest DTO for cool-offs and self-exclusions
+type PeriodRequest struct {
+	Days int `json:"days" binding:"required"`
+}
+
+// GetSafeguards handles GET /api/players/me/limits
+func (c *GameController) GetSafeguards(ctx *gin.Context) {
+	player, ok := c.currentPlayer(ctx)
+	if !ok {
+		return
+	}
+	ctx.JSON(http.StatusOK, c.Safeguards.Get(player.ID, time.Now()))
+}
+
+// SetSafeguards handles PUT /api/players/me/limits
+func (c *GameController) SetSafeguards(ctx *gin.Context) {
+	player, ok := c.currentPlayer(ctx)
+	if !ok {
+		return
+	}
+	var req SetLimitsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	changes := map[game.SafeguardKind]*int{
+		game.SafeguardDailyLoss:  req.DailyLoss,
+		game.SafeguardWeeklyLoss: req.WeeklyLoss,
+		game.SafeguardDailyWager: req.DailyWager,
+		game.SafeguardTotalWager: req.TotalWager,
+		game.SafeguardSession:    req.SessionMinutes,
+	}
+	for _, value := range changes {
+		if value != nil && *value < 0 {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Limits cannot be negative"})
+			return
+		}
+	}
+	now := time.Now()
+	for _, kind := range game.SafeguardKinds {
+		if value := changes[kind]; value != nil {
+			if _, err := c.Safeguards.SetLimit(player.ID, kind, *value, now); err != nil {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+		}
+	}
+	ctx.JSON(http.StatusOK, c.Safeguards.Get(player.ID, now))
+}
+
+// CoolOff handles POST /api/players/me/cool-off
+func (c *GameController) CoolOff(ctx *gin.Context) {
+	c.blockPlay(ctx, c.Safeguards.CoolOff)
+}
+
+// SelfExclude handles POST /api/players/me/self-exclusion
+func (c *GameController) SelfExclude(ctx *gin.Context) {
+	c.blockPlay(ctx, c.Safeguards.SelfExclude)
+}
+
+// blockPlay starts a cool-off or self-exclusion period
+func (c *GameController) blockPlay(ctx *gin.Context, block func(playerID string, days int, now time.Time) (game.Safeguards, error)) {
+	player, ok := c.currentPlayer(ctx)
+	if !ok {
+		return
+	}
+	var req PeriodRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "days is required and must be an integer"})
+		return
+	}
+	sg, err := block(player.ID, req.Days, time.Now())
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, sg)
+}
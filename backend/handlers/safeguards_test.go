@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"blackjack-api/game"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestSafeguards(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	controller := NewGameController()
+	router := gin.Default()
+	router.POST("/api/games", controller.StartGame)
+	router.POST("/api/games/:id/action", controller.PerformAction)
+	router.GET("/api/players/me/limits", controller.GetSafeguards)
+	router.PUT("/api/players/me/limits", controller.SetSafeguards)
+	router.POST("/api/players/me/cool-off", controller.CoolOff)
+	router.POST("/api/players/me/self-exclusion", controller.SelfExclude)
+
+	do := func(method, url, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, url, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Player-ID", "careful")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	errorCode := func(w *httptest.ResponseRecorder) string {
+		var body struct {
+			Code string `json:"code"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &body)
+		return body.Code
+	}
+	controller.getOrCreatePlayer("careful")
+
+	if w := do("PUT", "/api/players/me/limits", `{"daily_wager": 12}`); w.Code != http.StatusOK {
+		t.Fatalf("Expected 200 setting a limit, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := do("POST", "/api/games", `{"bet_amount": 10}`); w.Code != http.StatusCreated {
+		t.Fatalf("Expected a game within the limit, got %d: %s", w.Code, w.Body.String())
+	}
+	w := do("POST", "/api/games", `{"bet_amount": 5}`)
+	if w.Code != http.StatusForbidden || errorCode(w) != game.LimitDailyWager {
+		t.Errorf("Expected 403 %s, got %d: %s", game.LimitDailyWager, w.Code, w.Body.String())
+	}
+
+	// Raising waits for the delay
+	do("PUT", "/api/players/me/limits", `{"daily_wager": 100}`)
+	var sg game.Safeguards
+	json.Unmarshal(do("GET", "/api/players/me/limits", "").Body.Bytes(), &sg)
+	if limit := sg.Limits[game.SafeguardDailyWager]; limit.Value != 12 || limit.Pending == nil || *limit.Pending != 100 {
+		t.Errorf("Expected the raise to be pending, got %+v", limit)
+	}
+
+	// Doubling counts against the limits as well
+	rules := game.DefaultRules
+	g := &game.GameState{}
+	g.Record(game.Event{Type: game.EventBetPlaced, GameID: "raise", PlayerID: "careful", Amount: 2, Rules: &rules, Limits: &game.TableProfile{}})
+	g.Record(game.Event{Type: game.EventDeckShuffled, Deck: []game.Card{{Rank: game.Five}, {Rank: game.Six}, {Rank: game.King}, {Rank: game.Seven}, {Rank: game.Nine}}})
+	g.Deal(game.SeatPlayer, 0, true)
+	g.Deal(game.SeatPlayer, 0, true)
+	g.Deal(game.SeatDealer, 0, true)
+	g.Deal(game.SeatDealer, 0, false)
+	controller.Store.Save(g)
+	// Allow one token more than lost so far, whatever the first game's outcome
+	_, net := controller.Ledger.Activity("careful", time.Now().Add(-time.Hour))
+	do("PUT", "/api/players/me/limits", fmt.Sprintf(`{"daily_loss": %d}`, max(0, -net)+1))
+	w = do("POST", "/api/games/raise/action", `{"action": "double"}`)
+	if w.Code != http.StatusForbidden || errorCode(w) != game.LimitDailyLoss {
+		t.Errorf("Expected 403 %s on a double, got %d: %s", game.LimitDailyLoss, w.Code, w.Body.String())
+	}
+
+	if w := do("PUT", "/api/players/me/limits", `{"daily_loss": -5}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a negative limit, got %d", w.Code)
+	}
+	if w := do("POST", "/api/players/me/cool-off", `{"days": 100}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a cool-off that is too long, got %d", w.Code)
+	}
+	if w := do("POST", "/api/players/me/self-exclusion", `{"days": 365}`); w.Code != http.StatusOK {
+		t.Fatalf("Expected 200 self-excluding, got %d: %s", w.Code, w.Body.String())
+	}
+	w = do("POST", "/api/games", `{"bet_amount": 1}`)
+	if w.Code != http.StatusForbidden || errorCode(w) != game.LimitSelfExcluded || !bytes.Contains(w.Body.Bytes(), []byte(`"until"`)) {
+		t.Errorf("Expected 403 %s with an end, got %d: %s", game.LimitSelfExcluded, w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"blackjack-api/game"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SideBetInfo describes a side bet that can be placed with a game
+type SideBetInfo struct {
+	Name        string         `json:"name"`
+	AtDeal      bool           `json:"at_deal"`      // Settled right after the deal rather than with the game
+	NeedsUpcard bool           `json:"needs_upcard"` // Not offered when both dealer cards are down
+	MinDecks    int            `json:"min_decks"`    // Not offered on games dealt from fewer decks
+	Paytable    []game.Payline `json:"paytable"`
+}
+
+// ListSideBets handles GET /api/side-bets
+func (c *GameController) ListSideBets(ctx *gin.Context) {
+	bets := []SideBetInfo{}
+	for _, b := range game.SideBets() {
+		bets = append(bets, SideBetInfo{Name: b.Name(), AtDeal: b.AtDeal(), NeedsUpcard: b.NeedsUpcard(), MinDecks: b.MinDecks(), Paytable: b.Paytable()})
+	}
+	ctx.JSON(http.StatusOK, bets)
+}
@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"blackjack-api/game"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SpectatorGameView is what a spectator sees of a game: the player's masked view without balance or identity
+type SpectatorGameView struct {
+	ID               string          `json:"id"`
+	Player           string          `json:"player"` // Anonymized player ID
+	PlayerHand       game.Hand       `json:"player_hand"`
+	SplitHand        *game.Hand      `json:"split_hand,omitempty"`
+	Hands            []game.Hand     `json:"hands"`
+	CurrentHandIndex int             `json:"current_hand_index"`
+	DealerHand       game.Hand       `json:"dealer_hand"`
+	Status           game.GameStatus `json:"status"`
+	CurrentBet       int             `json:"current_bet"`
+	LastEvent        *game.Event     `json:"last_event,omitempty"`
+}
+
+// anonymizeSalt keeps aliases stable for the life of the process without being reversible
+var anonymizeSalt = func() []byte {
+	salt := make([]byte, 16)
+	rand.Read(salt)
+	return salt
+}()
+
+// anonymize returns a stable alias that does not reveal the player ID
+func anonymize(playerID string) string {
+	if playerID == "" {
+		return ""
+	}
+	sum := sha256.Sum256(append(append([]byte{}, anonymizeSalt...), playerID...))
+	return "Player-" + hex.EncodeToString(sum[:3])
+}
+
+// SpectateGame handles GET /api/games/:id/spectate (Server-Sent Events, read-only)
+// Each event of the game is followed by the table as the player saw it at that point.
+func (c *GameController) SpectateGame(ctx *gin.Context) {
+	id := ctx.Param("id")
+	gameState, _, exists := c.findGame(id)
+	if !exists {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Game not found"})
+		return
+	}
+
+	// Views are built from copies since the player keeps acting on the game
+	gameState = gameState.Copy()
+	initial := sseMessage{Name: "state", Data: spectatorGameView(gameState, len(gameState.Events))}
+	c.stream(ctx,
+		func(n game.Notification) bool { return n.GameID == id && n.Type == game.NotifyGameEvent },
+		&initial,
+		func(n game.Notification) (sseMessage, bool) {
+			g, _, ok := c.findGame(id)
+			if !ok {
+				return sseMessage{}, false
+			}
+			if g = g.Copy(); n.Event.Seq > len(g.Events) {
+				return sseMessage{}, false
+			}
+			return sseMessage{Name: "state", Data: spectatorGameView(g, n.Event.Seq)}, true
+		})
+}
+
+// SpectateTable handles GET /api/tables/:id/spectate (Server-Sent Events, read-only)
+func (c *GameController) SpectateTable(ctx *gin.Context) {
+	id := ctx.Param("id")
+	table, exists := c.Tables.Get(id)
+	if !exists {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Table not found"})
+		return
+	}
+
+	initial := sseMessage{Name: "state", Data: spectatorTableView(table.State())}
+	c.stream(ctx,
+		func(n game.Notification) bool { return n.TableID == id },
+		&initial,
+		func(n game.Notification) (sseMessage, bool) {
+			return sseMessage{Name: "state", Data: spectatorTableView(table.State())}, true
+		})
+}
+
+// spectatorGameView rebuilds the game as it was after its first seq events and masks it
+func spectatorGameView(g *game.GameState, seq int) SpectatorGameView {
+	at := g
+	if seq < len(g.Events) {
+		at = game.Replay(g.Events[:seq])
+	}
+	playerHand, splitHand := legacyHands(at.Hands)
+	view := SpectatorGameView{
+		ID:               g.ID,
+		Player:           anonymize(g.PlayerID),
+		PlayerHand:       playerHand,
+		SplitHand:        splitHand,
+		Hands:            at.Hands,
+		CurrentHandIndex: at.CurrentHandIndex,
+		DealerHand:       visibleDealerHand(at),
+		Status:           at.Status,
+		CurrentBet:       at.BetAmount,
+	}
+	if visible := visibleEvents(g.Events[:seq], seq-1); seq > 0 && len(visible) > 0 {
+		view.LastEvent = &visible[0]
+		view.LastEvent.PlayerID = ""
+	}
+	return view
+}
+
+// spectatorTableView masks the hole card and anonymizes every seat
+func spectatorTableView(state game.TableState) game.TableState {
+	return tableView(state, "")
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"blackjack-api/game"
+	"strings"
+	"testing"
+)
+
+func TestAnonymize(t *testing.T) {
+	alias := anonymize("alice")
+	if !strings.HasPrefix(alias, "Player-") || strings.Contains(alias, "alice") {
+		t.Fatalf("Expected an opaque alias, got %q", alias)
+	}
+	if anonymize("alice") != alias {
+		t.Errorf("Expected the alias to be stable")
+	}
+	if anonymize("bob") == alias {
+		t.Errorf("Expected different players to get different aliases")
+	}
+	if anonymize("") != "" {
+		t.Errorf("Expected empty seats to stay empty")
+	}
+}
+
+func TestSpectatorGameView(t *testing.T) {
+	g := &game.GameState{}
+	g.Record(game.Event{Type: game.EventBetPlaced, GameID: "g1", PlayerID: "alice", Amount: 10})
+	g.Record(game.Event{Type: game.EventDeckShuffled, Deck: []game.Card{
+		{Rank: "10", Suit: "Hearts"}, {Rank: "9", Suit: "Clubs"},
+		{Rank: "7", Suit: "Spades"}, {Rank: "K", Suit: "Diamonds"},
+	}})
+	g.Deal(game.SeatPlayer, 0, true)
+	g.Deal(game.SeatDealer, 0, true)
+	g.Deal(game.SeatPlayer, 0, true)
+	g.Deal(game.SeatDealer, 0, false)
+
+	view := spectatorGameView(g, len(g.Events))
+	if view.Player == "alice" || view.Player == "" {
+		t.Errorf("Expected the player to be anonymized, got %q", view.Player)
+	}
+	if view.DealerHand.Cards[1].Rank != "" {
+		t.Errorf("Expected the hole card to be masked, got %v", view.DealerHand.Cards[1])
+	}
+	if view.LastEvent == nil || view.LastEvent.Card.Rank != "" {
+		t.Errorf("Expected the last event to hide the hole card, got %+v", view.LastEvent)
+	}
+
+	// Earlier frames only show the cards dealt so far
+	early := spectatorGameView(g, 3)
+	if len(early.PlayerHand.Cards) != 1 || len(early.DealerHand.Cards) != 0 {
+		t.Errorf("Expected one player card after 3 events, got %+v", early)
+	}
+
+	g.Settle(game.StatusPlayerWon, 20)
+	final := spectatorGameView(g, len(g.Events))
+	if final.DealerHand.Cards[1].Rank != "K" {
+		t.Errorf("Expected the hole card once settled, got %v", final.DealerHand.Cards[1])
+	}
+}
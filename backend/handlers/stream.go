@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"blackjack-api/game"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// streamKeepAlive is how often an idle stream sends a ping so proxies keep it open
+const streamKeepAlive = 15 * time.Second
+
+// sseMessage is a named Server-Sent Event
+type sseMessage struct {
+	Name string
+	Data any
+}
+
+// StreamGameEvents handles GET /api/games/:id/events (Server-Sent Events)
+// Only the owner may follow a game this way; others spectate it, see SpectateGame.
+func (c *GameController) StreamGameEvents(ctx *gin.Context) {
+	playerID, ok := requirePlayerID(ctx)
+	if !ok {
+		return
+	}
+	id := ctx.Param("id")
+	gameState, _, exists := c.findGame(id)
+	if !exists {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Game not found"})
+		return
+	}
+	if gameState.PlayerID != playerID {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "Not your game"})
+		return
+	}
+	c.stream(ctx, func(n game.Notification) bool { return n.GameID == id && n.PlayerID == playerID }, nil, relayNotification)
+}
+
+// StreamAllEvents handles GET /api/events (Server-Sent Events)
+// Notifications carry player IDs and balances, so each player only gets their own.
+func (c *GameController) StreamAllEvents(ctx *gin.Context) {
+	playerID, ok := requirePlayerID(ctx)
+	if !ok {
+		return
+	}
+	c.stream(ctx, func(n game.Notification) bool { return n.PlayerID == playerID }, nil, relayNotification)
+}
+
+// StreamGlobalEvents handles GET /api/admin/events (Server-Sent Events), behind RequireAdminStream
+// Unlike StreamAllEvents it relays every player's notifications.
+func (c *GameController) StreamGlobalEvents(ctx *gin.Context) {
+	c.stream(ctx, func(game.Notification) bool { return true }, nil, relayNotification)
+}
+
+// relayNotification sends a notification as is, named after what it carries
+func relayNotification(n game.Notification) (sseMessage, bool) {
+	return sseMessage{Name: n.Name(), Data: n}, true
+}
+
+// stream relays matching bus notifications to the client until it disconnects.
+// initial, if set, is sent first; render turns a notification into a message or skips it.
+func (c *GameController) stream(ctx *gin.Context, filter func(game.Notification) bool, initial *sseMessage, render func(game.Notification) (sseMessage, bool)) {
+	notifications, unsubscribe := c.Bus.Subscribe(filter)
+	defer unsubscribe()
+
+	// Send the headers right away so the client knows it is subscribed
+	ctx.Header("Content-Type", "text/event-stream")
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("Connection", "keep-alive")
+	ctx.Status(http.StatusOK)
+	if initial != nil {
+		ctx.SSEvent(initial.Name, initial.Data)
+	}
+	ctx.Writer.Flush()
+
+	keepAlive := time.NewTicker(streamKeepAlive)
+	defer keepAlive.Stop()
+
+	ctx.Stream(func(w io.Writer) bool {
+		select {
+		case n, ok := <-notifications:
+			if !ok {
+				return false
+			}
+			if msg, send := render(n); send {
+				ctx.SSEvent(msg.Name, msg.Data)
+			}
+			return true
+		case <-keepAlive.C:
+			ctx.SSEvent("ping", gin.H{"time": time.Now()})
+			return true
+		case <-ctx.Request.Context().Done():
+			return false
+		}
+	})
+}
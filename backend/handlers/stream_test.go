@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestStreamAllEvents(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	controller := NewGameController()
+	router := gin.Default()
+	router.GET("/api/events", controller.StreamAllEvents)
+	router.POST("/api/games", controller.StartGame)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	reqCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if resp, _ := http.Get(server.URL + "/api/events"); resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("Expected 400 without X-Player-ID, got %d", resp.StatusCode)
+	}
+	req, _ := http.NewRequestWithContext(reqCtx, "GET", server.URL+"/api/events", nil)
+	req.Header.Set("X-Player-ID", "sse-player")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/event-stream") {
+		t.Fatalf("Expected an event stream, got %q", ct)
+	}
+
+	// Another player's game comes first and must not show up
+	for _, playerID := range []string{"other-player", "sse-player"} {
+		startReq, _ := http.NewRequest("POST", server.URL+"/api/games", bytes.NewBufferString(`{"bet_amount": 5}`))
+		startReq.Header.Set("Content-Type", "application/json")
+		startReq.Header.Set("X-Player-ID", playerID)
+		if startResp, err := http.DefaultClient.Do(startReq); err != nil || startResp.StatusCode != http.StatusCreated {
+			t.Fatalf("start game failed: %v %v", err, startResp)
+		}
+	}
+
+	// The welcome bonus and bet show up as balance changes, then the deal follows
+	seen := map[string]bool{}
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		if strings.Contains(scanner.Text(), "other-player") {
+			t.Fatalf("Expected only the subscriber's notifications, got %s", scanner.Text())
+		}
+		if name, ok := strings.CutPrefix(scanner.Text(), "event:"); ok {
+			seen[name] = true
+		}
+		if seen["balance"] && seen["bet_placed"] && seen["card_dealt"] {
+			return
+		}
+	}
+	t.Errorf("Expected balance, bet_placed and card_dealt events, got %v", seen)
+}
+
+func TestStreamGlobalEvents(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	controller := NewGameController()
+	router := gin.Default()
+	router.POST("/api/admin/stream-token", RequireAdmin("secret"), IssueStreamToken("secret"))
+	router.GET("/api/admin/events", RequireAdminStream("secret"), controller.StreamGlobalEvents)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	expired := signStreamToken("secret", time.Now().Add(-time.Second))
+	forged := signStreamToken("guess", time.Now().Add(time.Minute))
+	for _, query := range []string{"", "?token=" + expired, "?token=" + forged, "?token=secret"} {
+		if resp, _ := http.Get(server.URL + "/api/admin/events" + query); resp.StatusCode != http.StatusUnauthorized {
+			t.Errorf("Expected 401 for %q, got %d", query, resp.StatusCode)
+		}
+	}
+
+	// EventSource cannot send headers, so it trades the admin token for a stream token
+	tokenReq, _ := http.NewRequest("POST", server.URL+"/api/admin/stream-token", nil)
+	tokenReq.Header.Set("X-Admin-Token", "secret")
+	tokenResp, err := http.DefaultClient.Do(tokenReq)
+	if err != nil || tokenResp.StatusCode != http.StatusCreated {
+		t.Fatalf("stream token failed: %v %v", err, tokenResp)
+	}
+	var issued struct {
+		Token string `json:"token"`
+	}
+	json.NewDecoder(tokenResp.Body).Decode(&issued)
+
+	reqCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(reqCtx, "GET", server.URL+"/api/admin/events?token="+url.QueryEscape(issued.Token), nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("subscribe: %v %v", err, resp)
+	}
+	defer resp.Body.Close()
+
+	// Every player's notifications come through
+	for _, playerID := range []string{"alice", "bob"} {
+		if _, _, apiErr := controller.startGame(playerID, StartGameRequest{BetAmount: 5}); apiErr != nil {
+			t.Fatalf("start: %s", apiErr.Message)
+		}
+	}
+	seen := map[string]bool{}
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		for _, playerID := range []string{"alice", "bob"} {
+			if strings.Contains(scanner.Text(), `"player_id":"`+playerID+`"`) {
+				seen[playerID] = true
+			}
+		}
+		if seen["alice"] && seen["bob"] {
+			return
+		}
+	}
+	t.Errorf("Expected notifications for both players, got %v", seen)
+}
@@ -0,0 +1,222 @@
+package handlers
+
+import (
+	"blackjack-api/game"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// CreateTableRequest DTO; zero values fall back to game.DefaultTableConfig
+type CreateTableRequest struct {
+	Decks              int `json:"decks"`
+	BetTimeoutSeconds  int `json:"bet_timeout_seconds"`
+	TurnTimeoutSeconds int `json:"turn_timeout_seconds"`
+}
+
+// JoinTableRequest DTO; without a seat the first free one is taken
+type JoinTableRequest struct {
+	Seat *int `json:"seat"`
+}
+
+// TableBetRequest DTO
+type TableBetRequest struct {
+	BetAmount int `json:"bet_amount" binding:"required"`
+}
+
+// CreateTable handles POST /api/tables
+func (c *GameController) CreateTable(ctx *gin.Context) {
+	var req CreateTableRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	if req.Decks < 0 || req.Decks > game.MaxTableDecks {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("decks must be 1 to %d", game.MaxTableDecks)})
+		return
+	}
+
+	config := game.DefaultTableConfig
+	if req.Decks > 0 {
+		config.Decks = req.Decks
+	}
+	if req.BetTimeoutSeconds > 0 {
+		config.BetTimeout = time.Duration(req.BetTimeoutSeconds) * time.Second
+	}
+	if req.TurnTimeoutSeconds > 0 {
+		config.TurnTimeout = time.Duration(req.TurnTimeoutSeconds) * time.Second
+	}
+
+	table := c.Tables.Create(uuid.New().String(), config)
+	ctx.JSON(http.StatusCreated, tableView(table.State(), ctx.GetHeader("X-Player-ID")))
+}
+
+// ListTables handles GET /api/tables
+func (c *GameController) ListTables(ctx *gin.Context) {
+	tables := []game.TableState{}
+	for _, table := range c.Tables.All() {
+		tables = append(tables, tableView(table.State(), ctx.GetHeader("X-Player-ID")))
+	}
+	ctx.JSON(http.StatusOK, tables)
+}
+
+// GetTable handles GET /api/tables/:id
+func (c *GameController) GetTable(ctx *gin.Context) {
+	table, ok := c.loadTable(ctx)
+	if !ok {
+		return
+	}
+	ctx.JSON(http.StatusOK, tableView(table.State(), ctx.GetHeader("X-Player-ID")))
+}
+
+// JoinTable handles POST /api/tables/:id/join
+func (c *GameController) JoinTable(ctx *gin.Context) {
+	playerID, table, ok := c.tableRequest(ctx)
+	if !ok {
+		return
+	}
+	var req JoinTableRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	seat := -1
+	if req.Seat != nil {
+		seat = *req.Seat
+	}
+
+	c.getOrCreatePlayer(playerID)
+	if _, err := table.Join(playerID, seat); err != nil {
+		respondTableError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, tableView(table.State(), playerID))
+}
+
+// LeaveTable handles POST /api/tables/:id/leave
+func (c *GameController) LeaveTable(ctx *gin.Context) {
+	playerID, table, ok := c.tableRequest(ctx)
+	if !ok {
+		return
+	}
+	if err := table.Leave(playerID, time.Now()); err != nil {
+		respondTableError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, tableView(table.State(), playerID))
+}
+
+// PlaceTableBet handles POST /api/tables/:id/bet
+func (c *GameController) PlaceTableBet(ctx *gin.Context) {
+	playerID, table, ok := c.tableRequest(ctx)
+	if !ok {
+		return
+	}
+	var req TableBetRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "bet_amount is required and must be an integer"})
+		return
+	}
+	defer c.lockPlayer(playerID)()
+	// Tables are dealt under the standard table profile
+	profile, _ := game.LookupTableProfile(game.DefaultTableProfile)
+	err := profile.CheckBet(req.BetAmount)
+	if err == nil {
+		err = profile.CheckPlayerExposure(c.exposure(playerID, nil) + req.BetAmount)
+	}
+	if err != nil {
+		apiErr := limitError(http.StatusBadRequest, err)
+		ctx.JSON(apiErr.Status, apiErr.body())
+		return
+	}
+	// Table bets are real money as well, so the player's own limits apply
+	if err := c.Safeguards.CheckStart(playerID, req.BetAmount, time.Now()); err != nil {
+		apiErr := limitError(http.StatusForbidden, err)
+		ctx.JSON(apiErr.Status, apiErr.body())
+		return
+	}
+	if err := table.PlaceBet(playerID, req.BetAmount, time.Now()); err != nil {
+		respondTableError(ctx, err)
+		return
+	}
+	c.Safeguards.Played(playerID, time.Now())
+	ctx.JSON(http.StatusOK, tableView(table.State(), playerID))
+}
+
+// TableAction handles POST /api/tables/:id/action ("hit" or "stand")
+func (c *GameController) TableAction(ctx *gin.Context) {
+	playerID, table, ok := c.tableRequest(ctx)
+	if !ok {
+		return
+	}
+	var req ActionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := table.Act(playerID, req.Action, time.Now()); err != nil {
+		respondTableError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, tableView(table.State(), playerID))
+}
+
+// loadTable finds the table in the :id path param and applies any expired timeouts
+func (c *GameController) loadTable(ctx *gin.Context) (*game.Table, bool) {
+	table, exists := c.Tables.Get(ctx.Param("id"))
+	if !exists {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Table not found"})
+		return nil, false
+	}
+	table.Tick(time.Now())
+	return table, true
+}
+
+// tableRequest loads the table and the player making the request
+func (c *GameController) tableRequest(ctx *gin.Context) (string, *game.Table, bool) {
+	playerID := ctx.GetHeader("X-Player-ID")
+	if playerID == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "X-Player-ID header is required"})
+		return "", nil, false
+	}
+	table, ok := c.loadTable(ctx)
+	return playerID, table, ok
+}
+
+// tableView hides the dealer's hole card while seats are still playing.
+// Player IDs are credentials, so every seat but the caller's is anonymized.
+func tableView(state game.TableState, playerID string) game.TableState {
+	if state.Phase == game.PhasePlaying && len(state.DealerHand.Cards) > 1 {
+		state.DealerHand.Cards[1] = game.Card{}
+		state.DealerHand.Score = 0
+	}
+	for i := range state.Seats {
+		if state.Seats[i].PlayerID != playerID {
+			state.Seats[i].PlayerID = anonymize(state.Seats[i].PlayerID)
+		}
+	}
+	return state
+}
+
+// respondTableError maps table errors to HTTP responses
+func respondTableError(ctx *gin.Context, err error) {
+	status := http.StatusBadRequest
+	switch {
+	case errors.Is(err, game.ErrTableFull), errors.Is(err, game.ErrSeatTaken),
+		errors.Is(err, game.ErrAlreadySeated), errors.Is(err, game.ErrAlreadyBet),
+		errors.Is(err, game.ErrBettingClosed), errors.Is(err, game.ErrNotYourTurn):
+		status = http.StatusConflict
+	case errors.Is(err, game.ErrTableClosed):
+		status = http.StatusGone
+	}
+	ctx.JSON(status, gin.H{"error": err.Error()})
+}
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"blackjack-api/game"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestTablesHideOtherPlayers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	controller := NewGameController()
+	router := gin.Default()
+	router.GET("/api/tables", controller.ListTables)
+	router.GET("/api/tables/:id", controller.GetTable)
+	router.POST("/api/tables/:id/join", controller.JoinTable)
+
+	controller.Tables.Create("t1", game.DefaultTableConfig)
+	do := func(method, url, playerID string) game.TableState {
+		req, _ := http.NewRequest(method, url, nil)
+		if playerID != "" {
+			req.Header.Set("X-Player-ID", playerID)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s %s: expected 200, got %d: %s", method, url, w.Code, w.Body.String())
+		}
+		var state game.TableState
+		if method == "GET" && url == "/api/tables" {
+			var states []game.TableState
+			json.Unmarshal(w.Body.Bytes(), &states)
+			state = states[0]
+		} else {
+			json.Unmarshal(w.Body.Bytes(), &state)
+		}
+		return state
+	}
+
+	do("POST", "/api/tables/t1/join", "alice")
+	state := do("POST", "/api/tables/t1/join", "bob")
+	if state.Seats[0].PlayerID != anonymize("alice") || state.Seats[1].PlayerID != "bob" {
+		t.Errorf("Expected alice anonymized and bob's own seat as is, got %q and %q", state.Seats[0].PlayerID, state.Seats[1].PlayerID)
+	}
+	for _, state := range []game.TableState{do("GET", "/api/tables", ""), do("GET", "/api/tables/t1", "")} {
+		if state.Seats[0].PlayerID != anonymize("alice") || state.Seats[1].PlayerID != anonymize("bob") {
+			t.Errorf("Expected every seat anonymized without X-Player-ID, got %q and %q", state.Seats[0].PlayerID, state.Seats[1].PlayerID)
+		}
+	}
+	if state := do("GET", "/api/tables/t1", "alice"); state.Seats[0].PlayerID != "alice" || state.Seats[1].PlayerID != anonymize("bob") {
+		t.Errorf("Expected only alice's own seat as is, got %q and %q", state.Seats[0].PlayerID, state.Seats[1].PlayerID)
+	}
+}
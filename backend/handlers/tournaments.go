@@ -0,0 +1,242 @@
+package handlers
+
+import (
+	"blackjack-api/game"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// CreateTournamentRequest DTO
+type CreateTournamentRequest struct {
+	Name              string `json:"name" binding:"required"`
+	StartingStack     int    `json:"starting_stack" binding:"required"`
+	HandsPerRound     int    `json:"hands_per_round" binding:"required"`
+	EliminatePerRound int    `json:"eliminate_per_round" binding:"required"`
+	FinalTableSize    int    `json:"final_table_size" binding:"required"`
+	Prizes            []int  `json:"prizes"`
+
+	RoundTimeoutSeconds int `json:"round_timeout_seconds"` // Zero falls back to game.DefaultRoundTimeout
+}
+
+// TournamentResponse DTO; entrants only appear, anonymized, on the leaderboard
+type TournamentResponse struct {
+	ID          string                `json:"id"`
+	Config      game.TournamentConfig `json:"config"`
+	Status      game.TournamentStatus `json:"status"`
+	Round       int                   `json:"round"`
+	Entrants    int                   `json:"entrants"`
+	Remaining   int                   `json:"remaining"`
+	CreatedAt   time.Time             `json:"created_at"`
+	StartedAt   time.Time             `json:"started_at,omitempty"`
+	Deadline    time.Time             `json:"round_deadline,omitempty"`
+	ClosedAt    time.Time             `json:"closed_at,omitempty"`
+	Leaderboard []StandingView        `json:"leaderboard"`
+}
+
+// TournamentLeaderboardResponse DTO: the standings alone, from first to last place
+type TournamentLeaderboardResponse struct {
+	ID        string                `json:"id"`
+	Status    game.TournamentStatus `json:"status"`
+	Round     int                   `json:"round"`
+	Remaining int                   `json:"remaining"`
+	Standings []StandingView        `json:"standings"`
+}
+
+// StandingView is a leaderboard row as shown to players
+type StandingView struct {
+	Place       int    `json:"place"`
+	Player      string `json:"player"` // Anonymized unless it is the caller
+	You         bool   `json:"you,omitempty"`
+	Stack       int    `json:"stack"`
+	HandsPlayed int    `json:"hands_played"`
+	TotalHands  int    `json:"total_hands"`
+	Eliminated  bool   `json:"eliminated"`
+	OutInRound  int    `json:"out_in_round,omitempty"`
+	Prize       int    `json:"prize"`
+}
+
+// CreateTournament handles POST /api/admin/tournaments
+func (c *GameController) CreateTournament(ctx *gin.Context) {
+	var req CreateTournamentRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	config := game.TournamentConfig{
+		Name:              req.Name,
+		StartingStack:     req.StartingStack,
+		HandsPerRound:     req.HandsPerRound,
+		EliminatePerRound: req.EliminatePerRound,
+		FinalTableSize:    req.FinalTableSize,
+		Prizes:            req.Prizes,
+		RoundTimeout:      time.Duration(req.RoundTimeoutSeconds) * time.Second,
+	}
+	if err := config.Validate(); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	t := c.Tournaments.Create(uuid.New().String(), config)
+	ctx.JSON(http.StatusCreated, tournamentView(t, ""))
+}
+
+// StartTournament handles POST /api/admin/tournaments/:id/start
+func (c *GameController) StartTournament(ctx *gin.Context) {
+	t, ok := c.loadTournament(ctx)
+	if !ok {
+		return
+	}
+	if err := t.Start(time.Now()); err != nil {
+		respondTournamentError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, tournamentView(t, ""))
+}
+
+// CloseTournament handles POST /api/admin/tournaments/:id/close.
+// The final standings are returned with the real player IDs.
+func (c *GameController) CloseTournament(ctx *gin.Context) {
+	t, ok := c.loadTournament(ctx)
+	if !ok {
+		return
+	}
+	standings, err := t.Close(time.Now())
+	if err != nil && standings == nil {
+		respondTournamentError(ctx, err)
+		return
+	}
+	if err != nil {
+		// Play has stopped, but the unpaid prizes wait for the tournament to be closed again
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Some prizes could not be paid, close the tournament again to retry: " + err.Error(), "tournament": tournamentView(t, ""), "results": standings})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"tournament": tournamentView(t, ""), "results": standings})
+}
+
+// ListTournaments handles GET /api/tournaments
+func (c *GameController) ListTournaments(ctx *gin.Context) {
+	playerID := ctx.GetHeader("X-Player-ID")
+	tournaments := []TournamentResponse{}
+	for _, t := range c.Tournaments.All() {
+		tournaments = append(tournaments, tournamentView(t, playerID))
+	}
+	ctx.JSON(http.StatusOK, tournaments)
+}
+
+// GetTournament handles GET /api/tournaments/:id
+func (c *GameController) GetTournament(ctx *gin.Context) {
+	t, ok := c.loadTournament(ctx)
+	if !ok {
+		return
+	}
+	ctx.JSON(http.StatusOK, tournamentView(t, ctx.GetHeader("X-Player-ID")))
+}
+
+// GetTournamentLeaderboard handles GET /api/tournaments/:id/leaderboard
+func (c *GameController) GetTournamentLeaderboard(ctx *gin.Context) {
+	t, ok := c.loadTournament(ctx)
+	if !ok {
+		return
+	}
+	state := t.State()
+	standings, remaining := standingViews(t.Leaderboard(), ctx.GetHeader("X-Player-ID"))
+	ctx.JSON(http.StatusOK, TournamentLeaderboardResponse{
+		ID:        state.ID,
+		Status:    state.Status,
+		Round:     state.Round,
+		Remaining: remaining,
+		Standings: standings,
+	})
+}
+
+// RegisterTournament handles POST /api/tournaments/:id/register
+func (c *GameController) RegisterTournament(ctx *gin.Context) {
+	playerID := ctx.GetHeader("X-Player-ID")
+	if playerID == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "X-Player-ID header is required"})
+		return
+	}
+	t, ok := c.loadTournament(ctx)
+	if !ok {
+		return
+	}
+	// Prizes are paid in real money, so players who stepped away may not enter
+	if err := c.Safeguards.CheckPlay(playerID, time.Now()); err != nil {
+		apiErr := limitError(http.StatusForbidden, err)
+		ctx.JSON(apiErr.Status, apiErr.body())
+		return
+	}
+	c.getOrCreatePlayer(playerID)
+	if err := t.Register(playerID); err != nil {
+		respondTournamentError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, tournamentView(t, playerID))
+}
+
+// loadTournament looks up the tournament in the :id path param and ends an overdue round
+func (c *GameController) loadTournament(ctx *gin.Context) (*game.Tournament, bool) {
+	t, exists := c.Tournaments.Get(ctx.Param("id"))
+	if !exists {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Tournament not found"})
+		return nil, false
+	}
+	t.Tick(time.Now())
+	return t, true
+}
+
+// tournamentView builds the public view of a tournament; playerID is shown unmasked
+func tournamentView(t *game.Tournament, playerID string) TournamentResponse {
+	state := t.State()
+	resp := TournamentResponse{
+		ID:        state.ID,
+		Config:    state.Config,
+		Status:    state.Status,
+		Round:     state.Round,
+		Entrants:  len(state.Entrants),
+		CreatedAt: state.CreatedAt,
+		StartedAt: state.StartedAt,
+		Deadline:  state.RoundDeadline,
+		ClosedAt:  state.ClosedAt,
+	}
+	resp.Leaderboard, resp.Remaining = standingViews(t.Leaderboard(), playerID)
+	return resp
+}
+
+// standingViews anonymizes the standings but for playerID's own row, and counts the entrants still in
+func standingViews(standings []game.Standing, playerID string) ([]StandingView, int) {
+	views, remaining := []StandingView{}, 0
+	for _, s := range standings {
+		if !s.Eliminated {
+			remaining++
+		}
+		view := StandingView{
+			Place:       s.Place,
+			Player:      anonymize(s.PlayerID),
+			Stack:       s.Stack,
+			HandsPlayed: s.HandsPlayed,
+			TotalHands:  s.TotalHands,
+			Eliminated:  s.Eliminated,
+			OutInRound:  s.OutInRound,
+			Prize:       s.Prize,
+		}
+		if playerID != "" && s.PlayerID == playerID {
+			view.Player, view.You = playerID, true
+		}
+		views = append(views, view)
+	}
+	return views, remaining
+}
+
+// respondTournamentError maps tournament errors to HTTP responses
+func respondTournamentError(ctx *gin.Context, err error) {
+	status := http.StatusConflict
+	if errors.Is(err, game.ErrInvalidTournament) {
+		status = http.StatusBadRequest
+	}
+	ctx.JSON(status, gin.H{"error": err.Error()})
+}
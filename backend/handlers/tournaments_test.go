@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"blackjack-api/game"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestTournamentFlow(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	controller := NewGameController()
+	router := gin.Default()
+	router.POST("/api/games", controller.StartGame)
+	router.GET("/api/tournaments/:id", controller.GetTournament)
+	router.GET("/api/tournaments/:id/leaderboard", controller.GetTournamentLeaderboard)
+	router.POST("/api/tournaments/:id/register", controller.RegisterTournament)
+	admin := router.Group("/api/admin", RequireAdmin("secret"))
+	admin.POST("/tournaments", controller.CreateTournament)
+	admin.POST("/tournaments/:id/start", controller.StartTournament)
+
+	do := func(method, url, body string, headers map[string]string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, url, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	adminHeaders := map[string]string{"X-Admin-Token": "secret"}
+	config := `{"name": "Sunday", "starting_stack": 500, "hands_per_round": 5, "eliminate_per_round": 1, "final_table_size": 2, "prizes": [100]}`
+
+	if w := do("POST", "/api/admin/tournaments", config, map[string]string{"X-Admin-Token": "wrong"}); w.Code != http.StatusUnauthorized {
+		t.Fatalf("Expected 401 with a wrong admin token, got %d", w.Code)
+	}
+	w := do("POST", "/api/admin/tournaments", config, adminHeaders)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var created TournamentResponse
+	json.Unmarshal(w.Body.Bytes(), &created)
+
+	for _, id := range []string{"alice", "bob"} {
+		if w := do("POST", "/api/tournaments/"+created.ID+"/register", "", map[string]string{"X-Player-ID": id}); w.Code != http.StatusCreated {
+			t.Fatalf("register %s: expected 201, got %d", id, w.Code)
+		}
+	}
+	// Prizes are real money, so a self-excluded player may not enter
+	controller.Safeguards.SelfExclude("carol", game.MinExclusionDays, time.Now())
+	if w := do("POST", "/api/tournaments/"+created.ID+"/register", "", map[string]string{"X-Player-ID": "carol"}); w.Code != http.StatusForbidden {
+		t.Errorf("Expected 403 registering while self-excluded, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := do("POST", "/api/admin/tournaments/"+created.ID+"/start", "", adminHeaders); w.Code != http.StatusOK {
+		t.Fatalf("start: expected 200, got %d", w.Code)
+	}
+
+	// The bet comes out of the tournament stack, not the balance
+	w = do("POST", "/api/games", `{"bet_amount": 50, "tournament_id": "`+created.ID+`"}`, map[string]string{"X-Player-ID": "alice"})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var gameResp GameResponse
+	json.Unmarshal(w.Body.Bytes(), &gameResp)
+	if gameResp.Status == game.StatusPlayerTurn && gameResp.PlayerBalance != 450 {
+		t.Errorf("Expected the stack to be 450, got %d", gameResp.PlayerBalance)
+	}
+	if player, _ := controller.PlayerStore.Get("alice"); player.Balance != 100 {
+		t.Errorf("Expected the real balance to stay 100, got %d", player.Balance)
+	}
+
+	// Only one tournament hand at a time
+	if gameResp.Status == game.StatusPlayerTurn {
+		w = do("POST", "/api/games", `{"bet_amount": 50, "tournament_id": "`+created.ID+`"}`, map[string]string{"X-Player-ID": "alice"})
+		if w.Code != http.StatusConflict {
+			t.Errorf("Expected 409 for a second open hand, got %d", w.Code)
+		}
+	}
+
+	// Nor may an entrant who started a cool-off play on
+	controller.Safeguards.CoolOff("bob", game.MinCoolOffDays, time.Now())
+	if w := do("POST", "/api/games", `{"bet_amount": 50, "tournament_id": "`+created.ID+`"}`, map[string]string{"X-Player-ID": "bob"}); w.Code != http.StatusForbidden {
+		t.Errorf("Expected 403 for a tournament hand while cooling off, got %d: %s", w.Code, w.Body.String())
+	}
+
+	// Others only see an anonymized leaderboard
+	w = do("GET", "/api/tournaments/"+created.ID, "", map[string]string{"X-Player-ID": "bob"})
+	var view TournamentResponse
+	json.Unmarshal(w.Body.Bytes(), &view)
+	for _, s := range view.Leaderboard {
+		if s.Player == "alice" {
+			t.Errorf("Expected alice to be anonymized for bob")
+		}
+		if s.You && s.Player != "bob" {
+			t.Errorf("Expected bob's own row to be marked, got %+v", s)
+		}
+	}
+
+	// The leaderboard route serves the standings alone
+	w = do("GET", "/api/tournaments/"+created.ID+"/leaderboard", "", map[string]string{"X-Player-ID": "bob"})
+	var board TournamentLeaderboardResponse
+	json.Unmarshal(w.Body.Bytes(), &board)
+	if w.Code != http.StatusOK || board.ID != created.ID || board.Remaining != 2 || len(board.Standings) != 2 || board.Standings[0].Place != 1 {
+		t.Fatalf("Expected both entrants in place order, got %d: %s", w.Code, w.Body.String())
+	}
+	if board.Standings[0].Stack < board.Standings[1].Stack {
+		t.Errorf("Expected the larger stack first, got %+v", board.Standings)
+	}
+}
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"blackjack-api/game"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CountAnswerRequest DTO
+type CountAnswerRequest struct {
+	RunningCount *int     `json:"running_count" binding:"required"`
+	TrueCount    *float64 `json:"true_count"` // Optional; graded for balanced systems
+}
+
+// StartTraining handles POST /api/training/start; zero settings fall back to game.DefaultTrainerConfig.
+// Starting again replaces the player's trainer with a fresh shoe.
+func (c *GameController) StartTraining(ctx *gin.Context) {
+	playerID, ok := requirePlayerID(ctx)
+	if !ok {
+		return
+	}
+	var config game.TrainerConfig
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&config); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	if err := config.Validate(); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	trainer, err := game.NewTrainer(playerID, config)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Trainers.Save(trainer)
+	ctx.JSON(http.StatusCreated, gin.H{"config": config, "quiz": trainer.Quiz()})
+}
+
+// TrainingDeal handles POST /api/training/deal
+func (c *GameController) TrainingDeal(ctx *gin.Context) {
+	trainer, ok := c.loadTrainer(ctx)
+	if !ok {
+		return
+	}
+	deal, err := trainer.Deal()
+	if err != nil {
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, deal)
+}
+
+// GetCountQuiz handles GET /api/training/count
+func (c *GameController) GetCountQuiz(ctx *gin.Context) {
+	trainer, ok := c.loadTrainer(ctx)
+	if !ok {
+		return
+	}
+	ctx.JSON(http.StatusOK, trainer.Quiz())
+}
+
+// AnswerCountQuiz handles POST /api/training/count
+func (c *GameController) AnswerCountQuiz(ctx *gin.Context) {
+	trainer, ok := c.loadTrainer(ctx)
+	if !ok {
+		return
+	}
+	var req CountAnswerRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "running_count is required and must be an integer"})
+		return
+	}
+
+	result, err := trainer.Answer(*req.RunningCount, req.TrueCount)
+	if errors.Is(err, game.ErrNoQuiz) {
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, result)
+}
+
+// loadTrainer looks up the trainer of the requesting player
+func (c *GameController) loadTrainer(ctx *gin.Context) (*game.Trainer, bool) {
+	playerID, ok := requirePlayerID(ctx)
+	if !ok {
+		return nil, false
+	}
+	trainer, exists := c.Trainers.Get(playerID)
+	if !exists {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "No training session, POST /api/training/start first"})
+		return nil, false
+	}
+	return trainer, true
+}
+
+// requirePlayerID reads the X-Player-ID header, answering 400 if it is missing
+func requirePlayerID(ctx *gin.Context) (string, bool) {
+	playerID := ctx.GetHeader("X-Player-ID")
+	if playerID == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "X-Player-ID header is required"})
+		return "", false
+	}
+	return playerID, true
+}
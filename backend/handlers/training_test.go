@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"blackjack-api/game"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestTrainingFlow(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	controller := NewGameController()
+	router := gin.Default()
+	router.POST("/api/training/start", controller.StartTraining)
+	router.POST("/api/training/deal", controller.TrainingDeal)
+	router.GET("/api/training/count", controller.GetCountQuiz)
+	router.POST("/api/training/count", controller.AnswerCountQuiz)
+
+	do := func(method, url string, body any) *httptest.ResponseRecorder {
+		var buf bytes.Buffer
+		if body != nil {
+			json.NewEncoder(&buf).Encode(body)
+		}
+		req, _ := http.NewRequest(method, url, &buf)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Player-ID", "counter")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := do("POST", "/api/training/deal", nil); w.Code != http.StatusNotFound {
+		t.Fatalf("Expected NotFound before starting, got %v", w.Code)
+	}
+	if w := do("POST", "/api/training/start", game.TrainerConfig{System: "zen"}); w.Code != http.StatusBadRequest {
+		t.Fatalf("Expected BadRequest for unknown system, got %v", w.Code)
+	}
+	config := game.TrainerConfig{System: game.CountKO, Mode: game.TrainerDrill, QuizEvery: 3, CardsPerDeal: 3}
+	if w := do("POST", "/api/training/start", config); w.Code != http.StatusCreated {
+		t.Fatalf("Expected Created, got %v: %s", w.Code, w.Body.String())
+	}
+	if w := do("POST", "/api/training/count", gin.H{"running_count": 0}); w.Code != http.StatusConflict {
+		t.Fatalf("Expected Conflict without a pending quiz, got %v", w.Code)
+	}
+
+	w := do("POST", "/api/training/deal", nil)
+	var deal game.TrainerDeal
+	json.Unmarshal(w.Body.Bytes(), &deal)
+	if w.Code != http.StatusOK || !deal.QuizDue {
+		t.Fatalf("Expected a deal with the quiz due, got %v: %s", w.Code, w.Body.String())
+	}
+	if w := do("POST", "/api/training/deal", nil); w.Code != http.StatusConflict {
+		t.Fatalf("Expected Conflict while the quiz is pending, got %v", w.Code)
+	}
+
+	var quiz game.TrainerQuiz
+	json.Unmarshal(do("GET", "/api/training/count", nil).Body.Bytes(), &quiz)
+	if !quiz.Pending || quiz.AskTrueCount || quiz.CardsSeen != 3 {
+		t.Errorf("Expected a pending KO quiz without true count after 3 cards, got %+v", quiz)
+	}
+
+	running := game.CountKO.InitialCount(game.DefaultTrainerConfig.Decks)
+	for _, card := range deal.Cards {
+		running += game.CountKO.Tag(card)
+	}
+	var result game.QuizResult
+	json.Unmarshal(do("POST", "/api/training/count", gin.H{"running_count": running}).Body.Bytes(), &result)
+	if !result.Correct || result.TrueCount != nil {
+		t.Errorf("Expected correct KO answer %d without true count, got %+v", running, result)
+	}
+}
@@ -0,0 +1,224 @@
+package handlers
+
+import (
+	"blackjack-api/game"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+var upgrader = websocket.Upgrader{}
+
+// wsAuthTimeout is how long a connection may stay open before authenticating
+const wsAuthTimeout = 10 * time.Second
+
+// WSRequest is a message sent by the client over /ws
+type WSRequest struct {
+	Type         string         `json:"type"`                    // "auth", "start" or "action"
+	PlayerID     string         `json:"player_id,omitempty"`     // For "auth"
+	BetAmount    int            `json:"bet_amount,omitempty"`    // For "start"
+	TournamentID string         `json:"tournament_id,omitempty"` // For "start", optional
+	SideBets     map[string]int `json:"side_bets,omitempty"`     // For "start", optional
+	Variant      game.Variant   `json:"variant,omitempty"`       // For "start", optional
+	Charlie      int            `json:"charlie,omitempty"`       // For "start", optional
+	Table        string         `json:"table,omitempty"`         // For "start", optional
+	GameID       string         `json:"game_id,omitempty"`       // For "action"
+	Action       string         `json:"action,omitempty"`        // Same values as ActionRequest
+	Amount       int            `json:"amount,omitempty"`        // For "action": see ActionRequest
+}
+
+// WSMessage is a message pushed to the client over /ws
+type WSMessage struct {
+	Type    string        `json:"type"` // "ready", "event", "state", "table" or "error"
+	GameID  string        `json:"game_id,omitempty"`
+	TableID string        `json:"table_id,omitempty"` // For "table": fetch the table to see what changed
+	Event   *game.Event   `json:"event,omitempty"`
+	Game    *GameResponse `json:"game,omitempty"`
+	Error   string        `json:"error,omitempty"`
+	Code    string        `json:"code,omitempty"`  // Violated table limit, see game.LimitError
+	Limit   int           `json:"limit,omitempty"` // Along with Code
+	Until   *time.Time    `json:"until,omitempty"` // When play may resume, along with Code
+}
+
+// ServeWS handles GET /ws
+// Browsers cannot set headers on the handshake, so the first message must be
+// {"type": "auth", "player_id": ...}, answered with "ready"; other clients may send X-Player-ID instead.
+// The player ID is a credential and is never taken from the query string, which ends up in access logs.
+// Every request is answered with one "event" message per card dealt or action taken, in order,
+// followed by the resulting "state". Changes made by the server, such as a game expired by the
+// janitor, are pushed the same way, and "table" messages tell when a table the player sits at moves on.
+func (c *GameController) ServeWS(ctx *gin.Context) {
+	conn, err := upgrader.Upgrade(ctx.Writer, ctx.Request, nil)
+	if err != nil {
+		return // Upgrade already wrote the error response
+	}
+	defer conn.Close()
+
+	ws := &wsConn{conn: conn, seen: make(map[string]int), stateAt: make(map[string]int)}
+	playerID := ctx.GetHeader("X-Player-ID")
+	if playerID == "" {
+		if playerID = ws.authenticate(); playerID == "" {
+			return
+		}
+	}
+
+	// Other tables are left out here, so that they cannot crowd the player's own pushes out of the buffer
+	notifications, unsubscribe := c.Bus.Subscribe(func(n game.Notification) bool {
+		return n.PlayerID == playerID || (n.Type == game.NotifyTable && slices.Contains(n.Seated, playerID))
+	})
+	defer unsubscribe()
+	go c.pushNotifications(ws, playerID, notifications)
+
+	for {
+		var req WSRequest
+		if err := conn.ReadJSON(&req); err != nil {
+			return
+		}
+		if err := ws.write(c.handleWS(playerID, req)...); err != nil {
+			return
+		}
+	}
+}
+
+// pushNotifications forwards what happens to the player's games and tables until the channel closes
+func (c *GameController) pushNotifications(ws *wsConn, playerID string, notifications <-chan game.Notification) {
+	for n := range notifications {
+		var err error
+		switch {
+		case n.Type == game.NotifyGameEvent && n.Event != nil:
+			err = ws.push(WSMessage{Type: "event", GameID: n.GameID, Event: n.Event}, func() *WSMessage {
+				if n.Event.Type != game.EventSettled {
+					return nil
+				}
+				// The client may not have asked for this, e.g. when the janitor expired the game
+				g, _, ok := c.findGame(n.GameID)
+				if !ok {
+					return nil
+				}
+				g.Lock()
+				defer g.Unlock()
+				resp := c.maskDealerHand(g, c.Wallets().Of(g).Balance())
+				return &WSMessage{Type: "state", GameID: n.GameID, Game: &resp}
+			})
+		case n.Type == game.NotifyTable:
+			err = ws.write(WSMessage{Type: "table", TableID: n.TableID})
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// wsConn serializes the writes to a connection, as requests and pushes are written from different goroutines.
+// Events and states reach the client once, whichever way they come first.
+type wsConn struct {
+	conn    *websocket.Conn
+	mu      sync.Mutex
+	seen    map[string]int // Last event sequence number written, by game ID
+	stateAt map[string]int // Last event written before the latest state, by game ID
+}
+
+// authenticate reads the "auth" message and returns the player ID, or "" if there was none
+func (ws *wsConn) authenticate() string {
+	ws.conn.SetReadDeadline(time.Now().Add(wsAuthTimeout))
+	var req WSRequest
+	if err := ws.conn.ReadJSON(&req); err != nil {
+		return ""
+	}
+	if req.Type != "auth" || req.PlayerID == "" {
+		ws.write(WSMessage{Type: "error", Error: "Send an auth message with the player_id first"})
+		return ""
+	}
+	ws.conn.SetReadDeadline(time.Time{})
+	if ws.write(WSMessage{Type: "ready"}) != nil {
+		return ""
+	}
+	return req.PlayerID
+}
+
+// write sends messages in order, skipping what the client already received
+func (ws *wsConn) write(msgs ...WSMessage) error {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	for _, msg := range msgs {
+		if err := ws.send(msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// push sends an event the client has not received yet, followed by the state built by then, if any
+func (ws *wsConn) push(event WSMessage, then func() *WSMessage) error {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	if event.Event.Seq <= ws.seen[event.GameID] {
+		return nil
+	}
+	if err := ws.send(event); err != nil {
+		return err
+	}
+	if msg := then(); msg != nil {
+		return ws.send(*msg)
+	}
+	return nil
+}
+
+// send writes a message unless it is an event already sent, or a state with no event since the last one;
+// callers hold the lock
+func (ws *wsConn) send(msg WSMessage) error {
+	switch msg.Type {
+	case "event":
+		if msg.Event.Seq <= ws.seen[msg.GameID] {
+			return nil
+		}
+		ws.seen[msg.GameID] = msg.Event.Seq
+	case "state":
+		if at, ok := ws.stateAt[msg.GameID]; ok && at == ws.seen[msg.GameID] {
+			return nil
+		}
+		ws.stateAt[msg.GameID] = ws.seen[msg.GameID]
+	}
+	return ws.conn.WriteJSON(msg)
+}
+
+// handleWS runs one client request through the same logic as the REST API
+func (c *GameController) handleWS(playerID string, req WSRequest) []WSMessage {
+	var (
+		gameState *game.GameState
+		wallet    game.Wallet
+		apiErr    *apiError
+		from      int // First event produced by this request
+	)
+
+	switch req.Type {
+	case "start":
+		gameState, wallet, apiErr = c.startGame(playerID, StartGameRequest{BetAmount: req.BetAmount, TournamentID: req.TournamentID, SideBets: req.SideBets, Variant: req.Variant, Charlie: req.Charlie, Table: req.Table})
+	case "action":
+		if g, _, ok := c.findGame(req.GameID); ok {
+			g.Lock()
+			from = len(g.Events)
+			g.Unlock()
+		}
+		gameState, wallet, apiErr = c.performAction(playerID, req.GameID, ActionRequest{Action: req.Action, Amount: req.Amount})
+	default:
+		apiErr = &apiError{Status: http.StatusBadRequest, Message: "Unknown message type"}
+	}
+	if apiErr != nil {
+		return []WSMessage{{Type: "error", GameID: req.GameID, Error: apiErr.Message, Code: apiErr.Code, Limit: apiErr.Limit, Until: apiErr.until()}}
+	}
+
+	gameState.Lock()
+	defer gameState.Unlock()
+	var msgs []WSMessage
+	for _, e := range visibleEvents(gameState.Events, from) {
+		e := e
+		msgs = append(msgs, WSMessage{Type: "event", GameID: gameState.ID, Event: &e})
+	}
+	resp := c.maskDealerHand(gameState, wallet.Balance())
+	return append(msgs, WSMessage{Type: "state", GameID: gameState.ID, Game: &resp})
+}
@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"blackjack-api/game"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+func TestWebSocketStartAndStand(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	controller := NewGameController()
+	router := gin.Default()
+	router.GET("/ws", controller.ServeWS)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws"
+	conn, _, err := websocket.DefaultDialer.Dial(url, http.Header{"X-Player-ID": {"ws-player"}})
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+
+	// readUntilState collects pushed events until the resulting state arrives
+	readUntilState := func() ([]game.Event, *GameResponse) {
+		var events []game.Event
+		for {
+			var msg WSMessage
+			if err := conn.ReadJSON(&msg); err != nil {
+				t.Fatalf("read: %v", err)
+			}
+			switch msg.Type {
+			case "event":
+				events = append(events, *msg.Event)
+			case "state":
+				return events, msg.Game
+			default:
+				t.Fatalf("unexpected message: %+v", msg)
+			}
+		}
+	}
+
+	conn.WriteJSON(WSRequest{Type: "start", BetAmount: 10})
+	events, state := readUntilState()
+
+	// bet + 4 cards, one message each
+	if len(events) < 5 || events[0].Type != game.EventBetPlaced {
+		t.Fatalf("expected bet and deal events, got %+v", events)
+	}
+	hole := events[4]
+	if hole.Type != game.EventCardDealt || hole.FaceUp {
+		t.Fatalf("expected the fifth event to be the face-down deal, got %+v", hole)
+	}
+	if state.Status == game.StatusPlayerTurn && hole.Card.Rank != "" {
+		t.Error("expected hole card to be masked while the game is in progress")
+	}
+	if state.Status != game.StatusPlayerTurn {
+		return // Initial blackjack settled the game
+	}
+
+	conn.WriteJSON(WSRequest{Type: "action", GameID: state.ID, Action: "stand"})
+	events, state = readUntilState()
+	if events[0].Type != game.EventActionTaken || events[0].Action != "stand" {
+		t.Errorf("expected stand to be the first event, got %+v", events[0])
+	}
+	if last := events[len(events)-1]; last.Type != game.EventSettled || state.Status == game.StatusPlayerTurn {
+		t.Errorf("expected game to be settled, got %+v / %s", last, state.Status)
+	}
+
+	conn.WriteJSON(WSRequest{Type: "action", GameID: state.ID, Action: "hit"})
+	var msg WSMessage
+	conn.ReadJSON(&msg)
+	if msg.Type != "error" {
+		t.Errorf("expected an error after the game is over, got %+v", msg)
+	}
+}
+
+func TestWebSocketAuthAndPush(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	controller := NewGameController()
+	router := gin.Default()
+	router.GET("/ws", controller.ServeWS)
+	server := httptest.NewServer(router)
+	defer server.Close()
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws"
+
+	// Without a header the first message must authenticate; the query string is not a credential
+	conn, _, err := websocket.DefaultDialer.Dial(url+"?player_id=browser", nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	conn.WriteJSON(WSRequest{Type: "start", BetAmount: 10})
+	var msg WSMessage
+	if err := conn.ReadJSON(&msg); err != nil || msg.Type != "error" {
+		t.Errorf("expected an error before authenticating, got %+v (%v)", msg, err)
+	}
+	if err := conn.ReadJSON(&msg); err == nil {
+		t.Errorf("expected the connection to close, got %+v", msg)
+	}
+	conn.Close()
+
+	conn, _, err = websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+	conn.WriteJSON(WSRequest{Type: "auth", PlayerID: "browser"})
+	if err := conn.ReadJSON(&msg); err != nil || msg.Type != "ready" {
+		t.Fatalf("expected ready, got %+v (%v)", msg, err)
+	}
+
+	// A game started elsewhere, e.g. over REST, is pushed as it happens
+	started, _, apiErr := controller.startGame("browser", StartGameRequest{BetAmount: 5})
+	if apiErr != nil {
+		t.Fatalf("start: %s", apiErr.Message)
+	}
+	controller.startGame("someone-else", StartGameRequest{BetAmount: 5})
+	if err := conn.ReadJSON(&msg); err != nil || msg.Type != "event" || msg.GameID != started.ID || msg.Event.Type != game.EventBetPlaced {
+		t.Errorf("expected the bet of the game to be pushed, got %+v (%v)", msg, err)
+	}
+}
+
+func TestWebSocketOnlyPushesOwnTables(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	controller := NewGameController()
+	router := gin.Default()
+	router.GET("/ws", controller.ServeWS)
+	server := httptest.NewServer(router)
+	defer server.Close()
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws"
+
+	conn, _, err := websocket.DefaultDialer.Dial(url, http.Header{"X-Player-ID": {"seated"}})
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+	// The subscription is in place once a request has been answered
+	conn.WriteJSON(WSRequest{Type: "action", GameID: "missing", Action: "hit"})
+	var msg WSMessage
+	if err := conn.ReadJSON(&msg); err != nil || msg.Type != "error" {
+		t.Fatalf("expected an error for a missing game, got %+v (%v)", msg, err)
+	}
+
+	// A busy table elsewhere must not crowd out the player's own pushes
+	busy := controller.Tables.Create("busy", game.DefaultTableConfig)
+	for i := 0; i < 100; i++ {
+		busy.Join("other", -1)
+		busy.Leave("other", time.Now())
+	}
+	own := controller.Tables.Create("own", game.DefaultTableConfig)
+	own.Join("seated", -1)
+
+	if err := conn.ReadJSON(&msg); err != nil || msg.Type != "table" || msg.TableID != "own" {
+		t.Errorf("expected only the player's own table to be pushed, got %+v (%v)", msg, err)
+	}
+}
@@ -1,9 +1,19 @@
 package main
 
 import (
+	"blackjack-api/game"
 	"blackjack-api/handlers"
-	"github.com/gin-gonic/gin"
+	"context"
+	"errors"
+	"log"
+	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/gin-gonic/gin"
 )
 
 func main() {
@@ -26,6 +36,61 @@ func main() {
 
 	gameController := handlers.NewGameController()
 
+	// Players out of chips get more only under the bankroll policy
+	bankroll, ok := game.DefaultBankrollConfig(game.BankrollPolicy(envOrDefault("BANKROLL_POLICY", string(game.BankrollDaily))))
+	if !ok {
+		log.Fatalf("invalid BANKROLL_POLICY=%q, expected none, daily, ad or admin", os.Getenv("BANKROLL_POLICY"))
+	}
+	bankroll.Amount = envInt("BANKROLL_GRANT", bankroll.Amount)
+	bankroll.Cooldown = envDuration("BANKROLL_COOLDOWN", bankroll.Cooldown)
+	gameController.Bankroll = game.NewBankroll(gameController.Ledger, bankroll)
+
+	// Restore the in-memory state from the latest snapshot, then keep saving it
+	snapshotter := game.NewSnapshotter(
+		envOrDefault("DATA_DIR", "./data"),
+		envDuration("SNAPSHOT_INTERVAL", 30*time.Second),
+		game.Stores{
+			Games:        gameController.Store,
+			Players:      gameController.PlayerStore,
+			History:      gameController.HistoryStore,
+			Ledger:       gameController.Ledger,
+			Tables:       gameController.Tables,
+			Tournaments:  gameController.Tournaments,
+			Leaderboards: gameController.Leaderboards,
+			Achievements: gameController.Achievements,
+			Stats:        gameController.Stats,
+			Decisions:    gameController.Decisions,
+			Trainers:     gameController.Trainers,
+			Promotions:   gameController.Promotions,
+			Bankroll:     gameController.Bankroll,
+			Safeguards:   gameController.Safeguards,
+		},
+	)
+	if err := snapshotter.Load(); err != nil {
+		log.Fatalf("snapshot: failed to load: %v", err)
+	}
+	snapshotter.Start()
+
+	// Archive finished games and expire abandoned ones
+	expiryPolicy := game.ExpiryPolicy(envOrDefault("GAME_EXPIRY_POLICY", string(game.ExpiryRefund)))
+	if expiryPolicy != game.ExpiryRefund && expiryPolicy != game.ExpiryForfeit {
+		log.Fatalf("invalid GAME_EXPIRY_POLICY=%q, expected %q or %q", expiryPolicy, game.ExpiryRefund, game.ExpiryForfeit)
+	}
+	janitor := game.NewJanitor(gameController.Store, gameController.HistoryStore, gameController.Wallets(), game.JanitorConfig{
+		Interval:    envDuration("JANITOR_INTERVAL", time.Minute),
+		IdleTimeout: envDuration("GAME_IDLE_TIMEOUT", 30*time.Minute),
+		Policy:      expiryPolicy,
+	})
+	janitor.OnExpire = gameController.OnExpired
+	janitor.Start()
+
+	// Apply the betting and turn timeouts of multi-seat tables, and remove the ones left empty
+	gameController.Tables.IdleTimeout = envDuration("TABLE_IDLE_TIMEOUT", game.DefaultTableIdleTimeout)
+	gameController.Tables.Start(time.Second)
+
+	// End tournament rounds that entrants have left unplayed
+	gameController.Tournaments.Start(time.Second)
+
 	// Add middleware to specific group or globally?
 	// User wants "all logs of the api communication".
 	// So we apply it to /api group.
@@ -35,9 +100,145 @@ func main() {
 	{
 		api.POST("/games", gameController.StartGame)
 		api.POST("/games/:id/action", gameController.PerformAction)
+		api.GET("/games/:id/replay", gameController.GetReplay)
+		api.GET("/achievements", gameController.ListAchievements)
+
+		api.POST("/tables", gameController.CreateTable)
+		api.GET("/tables", gameController.ListTables)
+		api.GET("/tables/:id", gameController.GetTable)
+		api.POST("/tables/:id/join", gameController.JoinTable)
+		api.POST("/tables/:id/leave", gameController.LeaveTable)
+		api.POST("/tables/:id/bet", gameController.PlaceTableBet)
+		api.POST("/tables/:id/action", gameController.TableAction)
+
+		api.GET("/tournaments", gameController.ListTournaments)
+		api.GET("/tournaments/:id", gameController.GetTournament)
+		api.GET("/tournaments/:id/leaderboard", gameController.GetTournamentLeaderboard)
+		api.POST("/tournaments/:id/register", gameController.RegisterTournament)
+
+		api.GET("/leaderboards/:metric", gameController.GetLeaderboard)
+		api.GET("/side-bets", gameController.ListSideBets)
+		api.GET("/table-profiles", gameController.ListTableProfiles)
+
+		api.GET("/promotions", gameController.ListPromotions)
+
+		api.POST("/training/start", gameController.StartTraining)
+		api.POST("/training/deal", gameController.TrainingDeal)
+		api.GET("/training/count", gameController.GetCountQuiz)
+		api.POST("/training/count", gameController.AnswerCountQuiz)
+	}
+
+	// A player's own data is keyed off X-Player-ID rather than the path, and kept out of
+	// the request log: /stats serves every logged path and response to anyone
+	me := r.Group("/api/players/me")
+	{
+		me.GET("", gameController.GetPlayer)
+		me.GET("/transactions", gameController.GetTransactions)
+		me.GET("/stats", gameController.GetPlayerStats)
+		me.GET("/accuracy", gameController.GetAccuracy)
+		me.GET("/mistakes", gameController.GetMistakes)
+		me.GET("/bankroll", gameController.GetBankroll)
+		me.POST("/bankroll/claim", gameController.ClaimGrant)
+		me.GET("/limits", gameController.GetSafeguards)
+		me.PUT("/limits", gameController.SetSafeguards)
+		me.POST("/cool-off", gameController.CoolOff)
+		me.POST("/self-exclusion", gameController.SelfExclude)
+	}
+	// The same stats under the player's own ID; the ID in the path keeps it out of the request log too
+	players := r.Group("/api/players")
+	{
+		players.GET("/:id/stats", gameController.GetPlayerStats)
+	}
+
+	// Admin endpoints require X-Admin-Token to match ADMIN_TOKEN; they are disabled if it is unset
+	adminToken := os.Getenv("ADMIN_TOKEN")
+	admin := api.Group("/admin", handlers.RequireAdmin(adminToken))
+	{
+		admin.POST("/tournaments", gameController.CreateTournament)
+		admin.POST("/tournaments/:id/start", gameController.StartTournament)
+		admin.POST("/tournaments/:id/close", gameController.CloseTournament)
+		admin.GET("/promotions", gameController.ListAllPromotions)
+		admin.POST("/promotions", gameController.CreatePromotion)
+		admin.DELETE("/promotions/:id", gameController.DeletePromotion)
+	}
+	// Admin requests that name a player or hand out a credential stay out of the request log
+	adminPrivate := r.Group("/api/admin", handlers.RequireAdmin(adminToken))
+	{
+		adminPrivate.POST("/players/:id/grants", gameController.AdminGrant)
+		adminPrivate.POST("/stream-token", handlers.IssueStreamToken(adminToken))
+	}
+
+	// Event streams stay open indefinitely, so they are kept out of the request log
+	streams := r.Group("/api")
+	{
+		streams.GET("/events", gameController.StreamAllEvents)
+		streams.GET("/games/:id/events", gameController.StreamGameEvents)
+		streams.GET("/games/:id/spectate", gameController.SpectateGame)
+		streams.GET("/tables/:id/spectate", gameController.SpectateTable)
+		// Every player's notifications; EventSource clients pass ?token= from /api/admin/stream-token
+		streams.GET("/admin/events", handlers.RequireAdminStream(adminToken), gameController.StreamGlobalEvents)
 	}
 
 	r.GET("/stats", handlers.GetStats)
+	r.GET("/ws", gameController.ServeWS)
+
+	srv := &http.Server{Addr: ":8080", Handler: r}
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("server: %v", err)
+		}
+	}()
+
+	// Wait for SIGTERM (docker stop) or Ctrl+C, then drain and write a final snapshot
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGTERM, os.Interrupt)
+	<-quit
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		log.Printf("server: shutdown: %v", err)
+	}
+	janitor.Stop()
+	gameController.Tables.Stop()
+	gameController.Tournaments.Stop()
+	if err := snapshotter.Stop(); err != nil {
+		log.Printf("snapshot: final save failed: %v", err)
+	}
+}
+
+// envOrDefault returns the environment variable or a fallback if unset
+func envOrDefault(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
 
-	r.Run(":8080")
+// envInt parses a positive integer environment variable
+func envInt(key string, fallback int) int {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n <= 0 {
+		log.Printf("invalid %s=%q, using %d", key, v, fallback)
+		return fallback
+	}
+	return n
+}
+
+// envDuration parses a duration environment variable (e.g. "30s")
+func envDuration(key string, fallback time.Duration) time.Duration {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		log.Printf("invalid %s=%q, using %s", key, v, fallback)
+		return fallback
+	}
+	return d
 }
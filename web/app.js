@@ -12,12 +12,71 @@ if (!playerId) {
     localStorage.setItem('bj_player_id', playerId);
 }
 
+// Betting limits of every table profile, see GET /api/table-profiles
+let tableProfiles = [];
+
+// Loads the table profiles into the table picker
+async function loadTableProfiles() {
+    try {
+        const response = await fetch('/api/table-profiles');
+        if (!response.ok) throw new Error('Failed to load table profiles');
+        tableProfiles = await response.json();
+    } catch (error) {
+        console.error(error);
+        return;
+    }
+
+    const select = document.getElementById('table-profile');
+    select.innerHTML = '';
+    tableProfiles.forEach(profile => {
+        const option = document.createElement('option');
+        option.value = profile.name;
+        option.innerText = `${profile.name.replace('_', ' ')} (${profile.min_bet}-${profile.max_bet})`;
+        select.appendChild(option);
+    });
+    applyTableProfile();
+}
+
+function selectedTableProfile() {
+    const name = document.getElementById('table-profile').value;
+    return tableProfiles.find(profile => profile.name === name);
+}
+
+// Bounds the bet input by the limits of the selected table; zero limits do not apply
+function applyTableProfile() {
+    const profile = selectedTableProfile();
+    if (!profile) return;
+
+    const betInput = document.getElementById('bet-amount');
+    const minBet = profile.min_bet || 1;
+    betInput.min = minBet;
+    if (profile.max_bet) {
+        betInput.max = profile.max_bet;
+    } else {
+        betInput.removeAttribute('max');
+    }
+    betInput.step = profile.chips && profile.chips.length ? Math.min(...profile.chips) : 1;
+
+    const bet = parseInt(betInput.value, 10);
+    if (isNaN(bet) || bet < minBet || (profile.max_bet && bet > profile.max_bet)) {
+        betInput.value = minBet;
+    }
+}
+
+loadTableProfiles();
+
 async function startGame() {
     const betInput = document.getElementById('bet-amount');
     const betAmount = parseInt(betInput.value, 10);
+    const profile = selectedTableProfile();
+    const minBet = profile ? profile.min_bet || 1 : 1;
 
-    if (isNaN(betAmount) || betAmount < 1) {
-        alert("Please enter a bet of at least 1.");
+    if (isNaN(betAmount) || betAmount < minBet) {
+        alert(`Please enter a bet of at least ${minBet}.`);
+        return;
+    }
+    if (profile && profile.max_bet && betAmount > profile.max_bet) {
+        alert(`Please enter a bet of at most ${profile.max_bet}.`);
         return;
     }
 
@@ -28,11 +87,15 @@ async function startGame() {
                 'Content-Type': 'application/json',
                 'X-Player-ID': playerId
             },
-            body: JSON.stringify({ bet_amount: betAmount })
+            body: JSON.stringify({ bet_amount: betAmount, table: profile ? profile.name : undefined })
         });
 
         if (!response.ok) {
             const err = await response.json();
+            if (err.code === 'insufficient_funds' && confirm('Not enough chips. Claim free chips?')) {
+                await claimChips();
+                return;
+            }
             throw new Error(err.error || 'Failed to start game');
         }
 
@@ -65,6 +128,19 @@ async function startGame() {
     }
 }
 
+// Claims the free-chip grant of the bankroll policy
+async function claimChips() {
+    const response = await fetch('/api/players/me/bankroll/claim', {
+        method: 'POST',
+        headers: { 'X-Player-ID': playerId }
+    });
+    const data = await response.json();
+    if (!response.ok) {
+        throw new Error(data.error || 'Failed to claim chips');
+    }
+    document.getElementById('player-balance').innerText = data.balance;
+}
+
 async function hit() {
     if (!gameId) return;
     try {
@@ -101,6 +177,26 @@ async function stand() {
     }
 }
 
+async function double() {
+    if (!gameId) return;
+    try {
+        const response = await fetch(`${API_URL}/${gameId}/action`, {
+            method: 'POST',
+            headers: {
+                'Content-Type': 'application/json',
+                'X-Player-ID': playerId
+            },
+            body: JSON.stringify({ action: 'double' })
+        });
+        const data = await response.json();
+        if (!response.ok) throw new Error(data.error);
+        updateUI(data);
+    } catch (error) {
+        console.error(error);
+        alert(error.message || "Failed to double");
+    }
+}
+
 async function split() {
     if (!gameId) return;
     try {
@@ -155,6 +251,7 @@ function updateUI(gameState) {
     const balanceSpan = document.getElementById('player-balance');
     const currentBetSpan = document.getElementById('current-bet');
     const splitBtn = document.getElementById('split-btn');
+    const doubleBtn = document.getElementById('double-btn');
 
     // Update Balance & Bet
     if (gameState.player_balance !== undefined) {
@@ -206,6 +303,7 @@ function updateUI(gameState) {
     if (gameState.status !== 'PlayerTurn') {
         enableControls(false);
         splitBtn.classList.add('hidden');
+        doubleBtn.classList.add('hidden');
         document.getElementById('restart-btn').classList.remove('hidden');
 
         // Highlight status
@@ -235,6 +333,13 @@ function updateUI(gameState) {
         } else {
             splitBtn.classList.add('hidden');
         }
+
+        // Double is allowed on a 2-card hand the player can afford to match; after a split only if the rules say so
+        const activeHand = gameState.current_hand_index === 1 ? gameState.split_hand : gameState.player_hand;
+        const canDouble = activeHand && activeHand.cards.length === 2 && !activeHand.doubled &&
+                          (!activeHand.split || gameState.rules.double_after_split) &&
+                          gameState.player_balance >= gameState.current_bet;
+        doubleBtn.classList.toggle('hidden', !canDouble);
     }
 }
 